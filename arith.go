@@ -0,0 +1,194 @@
+package gendsl
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// maxDivScale is the digits after the point kept by a Decimal division whose quotient does not terminate.
+const maxDivScale = 34
+
+// numeric kinds ordered by promotion
+const (
+	numFixed   = iota // Int and Uint
+	numBig            // BigInt
+	numDecimal        // Decimal
+	numFloat          // Float
+)
+
+// Add returns a + b of two numbers.
+//
+// The numbers are promoted so that the arithmetic between Int, Uint, BigInt and Decimal is exact:
+//   - Int and Uint give an Int, or a Uint if both are Uint and the result fits, and a BigInt if the result overflows int64.
+//   - Int, Uint and BigInt give a BigInt.
+//   - Int, Uint, BigInt and Decimal give a Decimal.
+//   - Float with Int, Uint or Float gives a Float, Float with BigInt or Decimal is an error since the result cannot be exact.
+//
+// Time and Duration can be added and subtracted as time.Time and time.Duration do,
+// and a Duration can be multiplied or divided by an integer, an error is returned if a Duration overflows.
+func Add(a, b Value) (Value, error) { return arith('+', a, b) }
+
+// Sub returns a - b of two numbers, the numbers are promoted as [gendsl.Add] does.
+func Sub(a, b Value) (Value, error) { return arith('-', a, b) }
+
+// Mul returns a * b of two numbers, the numbers are promoted as [gendsl.Add] does.
+func Mul(a, b Value) (Value, error) { return arith('*', a, b) }
+
+// Div returns a / b of two numbers, the numbers are promoted as [gendsl.Add] does.
+// The division of integers truncates toward zero.
+// The quotient of Decimals keeps the larger scale of a and b, or more digits if needed to be exact,
+// it is rounded half to even to 34 digits after the point if it does not terminate.
+func Div(a, b Value) (Value, error) { return arith('/', a, b) }
+
+func arith(op byte, a, b Value) (Value, error) {
+	if isTime(a) || isTime(b) {
+		return arithTime(op, a, b)
+	}
+	ka, ok := numKind(a)
+	if !ok {
+		return nil, errors.Errorf("unsupported operand %s for %c", typeName(a), op)
+	}
+	kb, ok := numKind(b)
+	if !ok {
+		return nil, errors.Errorf("unsupported operand %s for %c", typeName(b), op)
+	}
+	k, lo := ka, kb
+	if kb > ka {
+		k, lo = kb, ka
+	}
+	if k == numFloat {
+		if lo != numFixed && lo != numFloat {
+			return nil, errors.Errorf("cannot %c %s and %s exactly, convert the float first", op, a.Type(), b.Type())
+		}
+		return arithFloat(op, toFloat(a), toFloat(b)), nil
+	}
+	if k == numDecimal {
+		return arithDecimal(op, toDecimal(a), toDecimal(b))
+	}
+
+	x, y := toBigInt(a), toBigInt(b)
+	z := new(big.Int)
+	switch op {
+	case '+':
+		z.Add(x, y)
+	case '-':
+		z.Sub(x, y)
+	case '*':
+		z.Mul(x, y)
+	case '/':
+		if y.Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+		z.Quo(x, y)
+	}
+	if k == numBig {
+		return BigInt{i: z}, nil
+	}
+	_, ua := a.(Uint)
+	_, ub := b.(Uint)
+	switch {
+	case ua && ub && z.IsUint64():
+		return Uint(z.Uint64()), nil
+	case z.IsInt64():
+		return Int(z.Int64()), nil
+	}
+	return BigInt{i: z}, nil
+}
+
+func arithFloat(op byte, x, y float64) Value {
+	switch op {
+	case '+':
+		return Float(x + y)
+	case '-':
+		return Float(x - y)
+	case '*':
+		return Float(x * y)
+	}
+	return Float(x / y)
+}
+
+func arithDecimal(op byte, x, y Decimal) (Value, error) {
+	scale := x.scale
+	if y.scale > scale {
+		scale = y.scale
+	}
+	switch op {
+	case '+':
+		return Decimal{unscaled: new(big.Int).Add(x.rescale(scale), y.rescale(scale)), scale: scale}, nil
+	case '-':
+		return Decimal{unscaled: new(big.Int).Sub(x.rescale(scale), y.rescale(scale)), scale: scale}, nil
+	case '*':
+		return Decimal{unscaled: new(big.Int).Mul(x.int(), y.int()), scale: x.scale + y.scale}, nil
+	}
+	if y.int().Sign() == 0 {
+		return nil, errors.New("division by zero")
+	}
+	q := new(big.Rat).Quo(x.Rat(), y.Rat())
+	for ; scale < maxDivScale; scale++ {
+		if new(big.Rat).Mul(q, new(big.Rat).SetInt(pow10(scale))).IsInt() {
+			break
+		}
+	}
+	return DecimalFromRat(q, scale), nil
+}
+
+func numKind(v Value) (int, bool) {
+	switch v.(type) {
+	case Int, Uint:
+		return numFixed, true
+	case BigInt:
+		return numBig, true
+	case Decimal:
+		return numDecimal, true
+	case Float:
+		return numFloat, true
+	}
+	return 0, false
+}
+
+func isTime(v Value) bool {
+	switch v.(type) {
+	case Time, Duration:
+		return true
+	}
+	return false
+}
+
+func toBigInt(v Value) *big.Int {
+	switch v := v.(type) {
+	case Int:
+		return big.NewInt(int64(v))
+	case Uint:
+		return new(big.Int).SetUint64(uint64(v))
+	case BigInt:
+		return v.int()
+	}
+	return new(big.Int)
+}
+
+func toDecimal(v Value) Decimal {
+	if d, ok := v.(Decimal); ok {
+		return d
+	}
+	return Decimal{unscaled: toBigInt(v)}
+}
+
+func toFloat(v Value) float64 {
+	switch v := v.(type) {
+	case Int:
+		return float64(v)
+	case Uint:
+		return float64(v)
+	case Float:
+		return float64(v)
+	}
+	return 0
+}
+
+func typeName(v Value) string {
+	if v == nil {
+		return "nil"
+	}
+	return v.Type().String()
+}
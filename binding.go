@@ -0,0 +1,96 @@
+package gendsl
+
+type (
+	// Binding is an identifier that a procedure call makes visible to some of its arguments.
+	Binding struct {
+		Name string
+		// Def is the node that defines the identifier, leave it zero if the identifier is defined by the host.
+		Def Node
+		// Value is the value of the identifier if it is known before evaluation.
+		Value Value
+		// Args are the indexes of the arguments that the identifier is visible in, nil for all of them.
+		Args []int
+	}
+
+	// Binder reports the bindings introduced by a call to a procedure.
+	// Since tools cannot tell which identifiers a procedure defines without evaluating it,
+	// a procedure that evaluates its arguments with a new env should provide a Binder for static analysis.
+	Binder func(call Node) []Binding
+)
+
+// BindArg returns a Binder for procedures like (LET {name} {value} {body}),
+// the identifier or string literal of the argument `nameArg` is visible in the arguments `scopeArgs`.
+func BindArg(nameArg int, scopeArgs ...int) Binder {
+	return func(call Node) []Binding {
+		args := call.Args()
+		if nameArg >= len(args) {
+			return nil
+		}
+		name := args[nameArg]
+		id := name.Identifier()
+		if name.Type() == ExprTypeLiteral {
+			v, err := name.Literal()
+			if err != nil || v.Type() != ValueTypeString {
+				return nil
+			}
+			id = string(v.(String))
+		}
+		if id == "" {
+			return nil
+		}
+		return []Binding{{Name: id, Def: name, Args: scopeArgs}}
+	}
+}
+
+// InjectEnv returns a Binder for procedures that evaluate the arguments `scopeArgs` with `env`,
+// all the arguments are included if `scopeArgs` is empty.
+func InjectEnv(env *Env, scopeArgs ...int) Binder {
+	return func(Node) []Binding {
+		ret := make([]Binding, 0)
+		env.Range(func(id string, v Value) bool {
+			ret = append(ret, Binding{Name: id, Value: v, Args: scopeArgs})
+			return true
+		})
+		return ret
+	}
+}
+
+// Binders composes multiple Binders into one.
+func Binders(binders ...Binder) Binder {
+	return func(call Node) []Binding {
+		ret := make([]Binding, 0)
+		for _, b := range binders {
+			ret = append(ret, b(call)...)
+		}
+		return ret
+	}
+}
+
+// VisibleIn reports whether the binding is visible in the argument at index `arg`.
+func (b Binding) VisibleIn(arg int) bool {
+	if b.Args == nil {
+		return true
+	}
+	for _, i := range b.Args {
+		if i == arg {
+			return true
+		}
+	}
+	return false
+}
+
+// Bindings returns the bindings introduced by `call` if its operator is a procedure in `env` with a Binder.
+func (e *Env) Bindings(call Node) []Binding {
+	if call.IsZero() || call.Type() != ExprTypeExpr {
+		return nil
+	}
+	v, ok := e.Lookup(call.Operator().Identifier())
+	if !ok {
+		return nil
+	}
+	p, ok := v.(Procedure)
+	if !ok || p.Binder == nil {
+		return nil
+	}
+	return p.Binder(call)
+}
@@ -0,0 +1,96 @@
+package gendsl
+
+import "github.com/pkg/errors"
+
+// Builtin returns the procedure built in the DSL by `id`, it is not defined in an env unless it is added by
+// [gendsl.Env.WithBuiltins] or [gendsl.Env.WithProcedure]:
+//   - (quote x) - same as 'x
+//   - (quasiquote x) - same as `x
+//   - (eval x) - evaluates x, and evaluates its value again if it is a [gendsl.Code]
+//   - (defmacro name (params...) template body...) - defines a macro visible in the body, see [gendsl.ParseContext.MacroExpand]
+func Builtin(id string) (Procedure, bool) {
+	p, ok := builtins[id]
+	return p, ok
+}
+
+// IsBuiltin reports whether `v` is the procedure built in the DSL by `id`.
+func IsBuiltin(v Value, id string) bool {
+	p, ok := v.(Procedure)
+	return ok && id != "" && p.builtin == id
+}
+
+// WithBuiltins defines all the procedures built in the DSL in the env, see [gendsl.Builtin].
+// The ids defined in the env already are left as they are.
+func (e *Env) WithBuiltins() *Env {
+	for id, p := range builtins {
+		if _, ok := e.Lookup(id); !ok {
+			e.WithProcedure(id, p)
+		}
+	}
+	return e
+}
+
+var builtins map[string]Procedure
+
+func init() {
+	builtins = map[string]Procedure{
+		"quote": {
+			Eval: CheckNArgs("1", func(_ *EvalCtx, args []Expr, _ map[string]Value) (Value, error) {
+				if args[0].node == nil {
+					return args[0].value, nil
+				}
+				return quote(args[0].pc, args[0].node), nil
+			}),
+			Doc:       "(quote x) returns x as code without evaluating it, same as 'x.",
+			Signature: &Signature{Params: []ValueType{0}, Returns: ValueTypeCode},
+		},
+		"quasiquote": {
+			Eval: CheckNArgs("1", func(_ *EvalCtx, args []Expr, _ map[string]Value) (Value, error) {
+				if args[0].node == nil {
+					return args[0].value, nil
+				}
+				return quasiquote(args[0].pc, args[0].evalCtx, args[0].node)
+			}),
+			Doc:       "(quasiquote x) returns x as code with its unquotes filled, same as `x.",
+			Signature: &Signature{Params: []ValueType{0}, Returns: ValueTypeCode},
+		},
+		"eval": {
+			Eval: CheckNArgs("1", func(evalCtx *EvalCtx, args []Expr, _ map[string]Value) (Value, error) {
+				v, err := args[0].Eval()
+				if err != nil {
+					return nil, err
+				}
+				code, ok := v.(Code)
+				if !ok {
+					return v, nil
+				}
+				v, err = code.Eval(evalCtx)
+				return v, errors.WithMessage(err, "fail to eval code")
+			}),
+			Doc:       "(eval x) evaluates the code that x returns.",
+			Signature: &Signature{Params: []ValueType{0}},
+		},
+		"defmacro": {
+			Eval: CheckNArgs("+", func(_ *EvalCtx, args []Expr, _ map[string]Value) (Value, error) {
+				// the macros are expanded already, only the body is left to evaluate
+				var ret Value = Nil{}
+				for i := 3; i < len(args); i++ {
+					v, err := args[i].Eval()
+					if err != nil {
+						return nil, err
+					}
+					ret = v
+				}
+				return ret, nil
+			}),
+			Doc: "(defmacro name (params...) template body...) defines a macro visible in the body and returns the value of the last body, " +
+				"a call to the macro is replaced with the code that the template returns before evaluation, " +
+				"the parameter after &rest takes the rest arguments.",
+			Binder: bindMacro,
+		},
+	}
+	for id, p := range builtins {
+		p.builtin = id
+		builtins[id] = p
+	}
+}
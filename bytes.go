@@ -0,0 +1,73 @@
+package gendsl
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Bytes is a binary payload, it is written in hex like #x"deadbeef" or in base64 like #b64"3q2+7w==" in the DSL,
+// spaces and newlines can be used in both forms to split a long payload.
+// Unlike a [gendsl.String], Bytes can hold anything that is not valid UTF-8.
+type Bytes []byte
+
+var _ Value = Bytes(nil)
+
+func (Bytes) _value()         {}
+func (Bytes) Type() ValueType { return ValueTypeBytes }
+func (b Bytes) Unwrap() any   { return []byte(b) }
+
+// Len returns the length of a String or Bytes in bytes.
+func Len(v Value) (int, error) {
+	switch v := v.(type) {
+	case String:
+		return len(v), nil
+	case Bytes:
+		return len(v), nil
+	}
+	return 0, errors.Errorf("cannot get the length of %s", typeName(v))
+}
+
+// Slice returns v[i:j] of a String or Bytes like Go does, but an error is returned instead of panic if the range is invalid.
+// The Bytes returned are copied, so they never share the memory with `v`.
+func Slice(v Value, i, j int) (Value, error) {
+	n, err := Len(v)
+	if err != nil {
+		return nil, err
+	}
+	if i < 0 || j < i || j > n {
+		return nil, errors.Errorf("slice bounds [%d:%d] out of range with length %d", i, j, n)
+	}
+	switch v := v.(type) {
+	case String:
+		return v[i:j], nil
+	case Bytes:
+		return append(Bytes{}, v[i:j]...), nil
+	}
+	return nil, nil
+}
+
+func parseBytesLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	text := c.nodeText(node)
+	begin := strings.IndexByte(text, '"')
+	payload := strings.Join(strings.Fields(text[begin+1:len(text)-1]), "")
+	var (
+		b   []byte
+		err error
+	)
+	switch text[1:begin] {
+	case "x":
+		b, err = hex.DecodeString(payload)
+	default:
+		b, err = base64.StdEncoding.DecodeString(payload)
+		if err != nil && !strings.HasSuffix(payload, "=") {
+			b, err = base64.RawStdEncoding.DecodeString(payload)
+		}
+	}
+	if err != nil {
+		return nil, evalErrorf(c, node, "invalid bytes literal: %s", err)
+	}
+	return Bytes(b), nil
+}
@@ -0,0 +1,71 @@
+package gendsl
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("Bytes", func() {
+	It("can parse the literals", func() {
+		for expr, want := range map[string]Value{
+			`#x"deadbeef"`:               Bytes{0xde, 0xad, 0xbe, 0xef},
+			"#x\"DE AD\n  BE EF\"":       Bytes{0xde, 0xad, 0xbe, 0xef},
+			`#x""`:                       Bytes{},
+			`#b64"3q2+7w=="`:             Bytes{0xde, 0xad, 0xbe, 0xef},
+			`#b64"3q2+7w"`:               Bytes{0xde, 0xad, 0xbe, 0xef},
+			"#b64\"3q2+\n7w==\"":         Bytes{0xde, 0xad, 0xbe, 0xef},
+			`(RETURN #:b #x"00" #x"ff")`: Bytes{0xff},
+		} {
+			v, err := EvalExpr(expr, NewEnv().WithProcedure("RETURN", Procedure{Eval: CheckNArgs("1", _return)}))
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v).Should(gomega.Equal(want), expr)
+		}
+
+		for _, expr := range []string{`#x"abc"`, `#x"zz"`, `#b64"!!"`, `#b64"3q2+7w="`} {
+			_, err := EvalExpr(expr, NewEnv())
+			gomega.Expect(err).Should(gomega.HaveOccurred(), expr)
+		}
+	})
+
+	It("prints the literals", func() {
+		for _, v := range []Bytes{{}, {0xde, 0xad, 0xbe, 0xef}, []byte("\xff\xfe invalid utf-8")} {
+			text := Repr(v)
+			got, err := EvalExpr(text, NewEnv())
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), text)
+			gomega.Expect(got).Should(gomega.Equal(v), text)
+		}
+		gomega.Expect(Repr(Bytes{0xde, 0xad})).Should(gomega.Equal(`#x"dead"`))
+	})
+
+	It("gets the length and slices", func() {
+		b := Bytes{1, 2, 3, 4}
+		n, err := Len(b)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(n).Should(gomega.Equal(4))
+		n, err = Len(String("héllo"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(n).Should(gomega.Equal(6))
+		_, err = Len(Int(1))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+
+		v, err := Slice(b, 1, 3)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Bytes{2, 3}))
+		v.(Bytes)[0] = 0
+		gomega.Expect(b).Should(gomega.Equal(Bytes{1, 2, 3, 4}))
+		v, err = Slice(String("hello"), 1, 3)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(String("el")))
+		for _, r := range [][2]int{{-1, 1}, {2, 1}, {0, 5}} {
+			_, err = Slice(b, r[0], r[1])
+			gomega.Expect(err).Should(gomega.HaveOccurred())
+		}
+
+		c, err := Compare(Bytes{1, 2}, Bytes{1, 3})
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(c).Should(gomega.Equal(-1))
+		v, err = EvalExpr("b", NewEnv().WithBytes("b", b))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.Unwrap()).Should(gomega.Equal([]byte{1, 2, 3, 4}))
+	})
+})
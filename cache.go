@@ -0,0 +1,151 @@
+package gendsl
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"unsafe"
+)
+
+type (
+	// ScriptCache keeps the compiled scripts in a LRU cache, so that the same script is parsed only once.
+	// The *ParseContext returned is shared by all the callers, which is fine since it has no side-affect during evaluation.
+	// It is safe for concurrent use.
+	ScriptCache struct {
+		mu         sync.Mutex
+		maxEntries int
+		maxBytes   int
+		ll         *list.List // front is the most recently used
+		items      map[[sha256.Size]byte]*list.Element
+		bytes      int
+		stats      CacheStats
+	}
+
+	// CacheStats is the statistics of a ScriptCache.
+	CacheStats struct {
+		Hits      uint64
+		Misses    uint64
+		Evictions uint64
+		Entries   int // amount of the scripts cached
+		Bytes     int // estimated memory used by the scripts cached
+	}
+
+	cacheEntry struct {
+		key  [sha256.Size]byte
+		src  string
+		pc   *ParseContext
+		size int
+	}
+)
+
+// DefaultScriptCache is the cache used by [gendsl.EvalExprCached] and [gendsl.EvalExprWithDataCached].
+var DefaultScriptCache = NewScriptCache(1024, 64<<20)
+
+// NewScriptCache creates a ScriptCache that holds at most `maxEntries` scripts taking `maxBytes` of memory,
+// zero or a negative number means no limit.
+func NewScriptCache(maxEntries, maxBytes int) *ScriptCache {
+	return &ScriptCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// Get returns the compiled `expr`, it is parsed by [gendsl.MakeParseContext] if not cached.
+// The scripts that cannot be parsed are not cached.
+func (c *ScriptCache) Get(expr string) (*ParseContext, error) {
+	key := sha256.Sum256([]byte(expr))
+	c.mu.Lock()
+	if elem, ok := c.items[key]; ok && elem.Value.(*cacheEntry).src == expr {
+		c.ll.MoveToFront(elem)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return elem.Value.(*cacheEntry).pc, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// parse without the lock, the script parsed first is kept if it is parsed concurrently
+	pc, err := MakeParseContext(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if entry.src == expr {
+			c.ll.MoveToFront(elem)
+			return entry.pc, nil
+		}
+		c.remove(elem) // hash collision, keep the latest one
+	}
+	entry := &cacheEntry{key: key, src: expr, pc: pc, size: pc.size()}
+	c.items[key] = c.ll.PushFront(entry)
+	c.bytes += entry.size
+	for c.ll.Len() > 1 &&
+		((c.maxEntries > 0 && c.ll.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes)) {
+		c.remove(c.ll.Back())
+		c.stats.Evictions++
+	}
+	return pc, nil
+}
+
+func (c *ScriptCache) remove(elem *list.Element) {
+	entry := c.ll.Remove(elem).(*cacheEntry)
+	delete(c.items, entry.key)
+	c.bytes -= entry.size
+}
+
+// Stats returns the statistics of the cache.
+func (c *ScriptCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.ll.Len()
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// Purge drops all the scripts cached, the statistics are kept.
+func (c *ScriptCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[[sha256.Size]byte]*list.Element)
+	c.bytes = 0
+}
+
+// size estimates the memory used by the compiled script.
+func (c *ParseContext) size() int {
+	nodes := 0
+	var count func(n *node32)
+	count = func(n *node32) {
+		for ; n != nil; n = n.next {
+			nodes++
+			count(n.up)
+		}
+	}
+	count(c.root)
+	return len(c.p.Buffer) +
+		len(c.p.buffer)*int(unsafe.Sizeof(rune(0))) +
+		cap(c.p.tree)*int(unsafe.Sizeof(token32{})) +
+		nodes*int(unsafe.Sizeof(node32{})) +
+		len(c.lineStarts)*int(unsafe.Sizeof(0))
+}
+
+// EvalExprCached works like [gendsl.EvalExpr] but compiles `expr` only once with [gendsl.DefaultScriptCache].
+func EvalExprCached(expr string, env *Env) (Value, error) {
+	return EvalExprWithDataCached(expr, env, nil)
+}
+
+// EvalExprWithDataCached works like [gendsl.EvalExprWithData] but compiles `expr` only once with [gendsl.DefaultScriptCache].
+func EvalExprWithDataCached(expr string, env *Env, data any) (Value, error) {
+	pc, err := DefaultScriptCache.Get(expr)
+	if err != nil {
+		return nil, err
+	}
+	return pc.Eval(NewEvalCtx(nil, data, env))
+}
@@ -0,0 +1,79 @@
+package gendsl
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("ScriptCache", func() {
+	It("can share the compiled scripts", func() {
+		c := NewScriptCache(0, 0)
+		pc1, err := c.Get("(RETURN 1)")
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		pc2, err := c.Get("(RETURN 1)")
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(pc2).Should(gomega.BeIdenticalTo(pc1))
+
+		_, err = c.Get("(RETURN")
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+
+		stats := c.Stats()
+		gomega.Expect(stats.Hits).Should(gomega.BeEquivalentTo(1))
+		gomega.Expect(stats.Misses).Should(gomega.BeEquivalentTo(2))
+		gomega.Expect(stats.Entries).Should(gomega.Equal(1))
+		gomega.Expect(stats.Bytes).Should(gomega.BeNumerically(">", len("(RETURN 1)")))
+
+		c.Purge()
+		gomega.Expect(c.Stats().Entries).Should(gomega.Equal(0))
+		gomega.Expect(c.Stats().Bytes).Should(gomega.Equal(0))
+	})
+
+	It("can evict the least recently used scripts", func() {
+		c := NewScriptCache(2, 0)
+		a, _ := c.Get("1")
+		c.Get("2")
+		c.Get("1") // "2" is the least recently used now
+		c.Get("3")
+		gomega.Expect(c.Stats().Evictions).Should(gomega.BeEquivalentTo(1))
+		gomega.Expect(c.Get("1")).Should(gomega.BeIdenticalTo(a))
+		gomega.Expect(c.Stats().Misses).Should(gomega.BeEquivalentTo(3))
+
+		pc, _ := c.Get("2")
+		c = NewScriptCache(0, pc.size()+1)
+		c.Get("1")
+		c.Get("2")
+		gomega.Expect(c.Stats().Entries).Should(gomega.Equal(1))
+		c.Get("(RETURN (RETURN (RETURN 1)))") // larger than the limit, but the latest one is kept
+		gomega.Expect(c.Stats().Entries).Should(gomega.Equal(1))
+	})
+
+	It("can be used concurrently", func() {
+		c := NewScriptCache(4, 0)
+		wg := sync.WaitGroup{}
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for _, script := range []string{"1", "2", "3", "(RETURN 1)", "#t"} {
+					pc, err := c.Get(script)
+					gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+					_, err = pc.Eval(NewEvalCtx(nil, nil, testEnv))
+					gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				}
+			}(i)
+		}
+		wg.Wait()
+		gomega.Expect(c.Stats().Entries).Should(gomega.Equal(4))
+		gomega.Expect(c.Stats().Hits + c.Stats().Misses).Should(gomega.BeEquivalentTo(40))
+	})
+
+	It("can evaluate the scripts cached", func() {
+		gomega.Expect(EvalExprCached("(RETURN 10)", testEnv)).Should(gomega.Equal(Int(10)))
+		before := DefaultScriptCache.Stats().Hits
+		gomega.Expect(EvalExprWithDataCached("(RETURN 10)", testEnv, 1)).Should(gomega.Equal(Int(10)))
+		gomega.Expect(DefaultScriptCache.Stats().Hits).Should(gomega.Equal(before + 1))
+	})
+})
@@ -0,0 +1,61 @@
+// package main provides a debug adapter that serves DAP for the scripts of cmd/echo,
+// over the stdio by default or over a TCP connection with -listen.
+// A DSL built with gendsl should provide its own command with its env in the same way.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/ccbhj/gendsl"
+	"github.com/ccbhj/gendsl/dap"
+)
+
+func main() {
+	listen := flag.String("listen", "", "serve one client on a TCP address like 127.0.0.1:4711 instead of the stdio")
+	flag.Parse()
+
+	// the stdout is taken by the protocol, print to the stderr instead
+	env := gendsl.NewEnv().WithBuiltins().WithProcedure("ECHO", gendsl.Procedure{
+		Eval: func(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+			for _, arg := range args {
+				v, err := arg.Eval()
+				if err != nil {
+					return nil, err
+				}
+				fmt.Fprintln(os.Stderr, v.Unwrap())
+			}
+			return gendsl.Int(len(args)), nil
+		},
+		Doc: "ECHO prints its arguments line by line and returns the amount of arguments printed.",
+	})
+	server := dap.NewServer(dap.Config{Env: env})
+
+	if *listen == "" {
+		if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fatal(err)
+	}
+	defer l.Close()
+	conn, err := l.Accept()
+	if err != nil {
+		fatal(err)
+	}
+	defer conn.Close()
+	if err := server.Serve(conn, conn); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
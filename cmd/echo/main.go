@@ -1,5 +1,8 @@
 // package main proveides a cmd that reads an expression from input or file and print the result
-// or pretty print the ast for debugging. An procedure called "ECHO" that accepts any amount of arguments, print them and return the amount of arguments printed is already provided in the cmd.
+// or pretty print the ast for debugging. An procedure called "ECHO" that accepts any amount of arguments, print them and return the amount of arguments printed is already provided in the cmd, so are the builtins like quote and defmacro.
+//
+// Run it with -i or without any expression to start a REPL, type :help in the REPL for its commands.
+// Run it with -prof to write a profile of the procedure calls for `go tool pprof`.
 package main
 
 import (
@@ -9,31 +12,19 @@ import (
 	"os"
 
 	"github.com/ccbhj/gendsl"
+	"github.com/ccbhj/gendsl/profiler"
 )
 
 func main() {
 	var (
-		printTree = flag.Bool("pt", false, "print tree")
-		fromFile  = flag.String("file", "", "read input from file")
+		printTree   = flag.Bool("pt", false, "print tree")
+		fromFile    = flag.String("file", "", "read input from file")
+		interactive = flag.Bool("i", false, "start a REPL")
+		profileTo   = flag.String("prof", "", "write a pprof profile of the procedure calls to the file")
 	)
 	flag.Parse()
 
-	input := flag.Args()[0]
-	if fromFile != nil && *fromFile != "" {
-		file, err := os.OpenFile(*fromFile, os.O_RDONLY, os.ModePerm)
-		if err != nil {
-			panic(err)
-		}
-		defer file.Close()
-
-		bs, err := io.ReadAll(file)
-		if err != nil {
-			panic(err)
-		}
-		input = string(bs)
-	}
-
-	env := gendsl.NewEnv().WithProcedure("ECHO", gendsl.Procedure{
+	env := gendsl.NewEnv().WithBuiltins().WithProcedure("ECHO", gendsl.Procedure{
 		Eval: func(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
 			for _, arg := range args {
 				v, err := arg.Eval()
@@ -46,17 +37,70 @@ func main() {
 		},
 	})
 
+	if *interactive || (flag.NArg() == 0 && *fromFile == "") {
+		if err := newREPL(os.Stdin, os.Stdout, os.Stderr, env).Run(); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	var input string
+	if flag.NArg() > 0 {
+		input = flag.Arg(0)
+	}
+	if fromFile != nil && *fromFile != "" {
+		file, err := os.OpenFile(*fromFile, os.O_RDONLY, os.ModePerm)
+		if err != nil {
+			fatal(err)
+		}
+		defer file.Close()
+
+		bs, err := io.ReadAll(file)
+		if err != nil {
+			fatal(err)
+		}
+		input = string(bs)
+	}
+
 	pctx, err := gendsl.MakeParseContext(input)
 	if err != nil {
-		panic(err)
+		fatal(err)
 	}
 	if printTree != nil && *printTree {
 		pctx.PrintTree()
 	}
 
-	ret, err := pctx.Eval(gendsl.NewEvalCtx(nil, nil, env))
+	evalCtx := gendsl.NewEvalCtx(nil, nil, env)
+	if *profileTo == "" {
+		ret, err := pctx.Eval(evalCtx)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(ret.Unwrap())
+		return
+	}
+
+	filename := *fromFile
+	if filename == "" {
+		filename = "<expr>"
+	}
+	p := profiler.New(filename)
+	ret, err := p.Eval(pctx, evalCtx)
 	if err != nil {
-		panic(err)
+		fatal(err)
 	}
 	fmt.Println(ret.Unwrap())
+	out, err := os.Create(*profileTo)
+	if err != nil {
+		fatal(err)
+	}
+	defer out.Close()
+	if err := p.WriteProfile(out); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
 }
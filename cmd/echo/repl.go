@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ccbhj/gendsl"
+)
+
+const (
+	prompt         = "> "
+	continuePrompt = "... "
+)
+
+// repl reads expressions from `in` and prints their results to `out` one by one.
+// Values defined by (DEF {id} {value}) are kept in the session env across entries,
+// and the result of the last entry can be referred by `_`.
+type repl struct {
+	in      *bufio.Scanner
+	out     io.Writer
+	errOut  io.Writer
+	env     *gendsl.Env
+	last    *gendsl.ParseContext
+	history []string
+	timing  bool
+}
+
+func newREPL(in io.Reader, out, errOut io.Writer, env *gendsl.Env) *repl {
+	r := &repl{
+		in:     bufio.NewScanner(in),
+		out:    out,
+		errOut: errOut,
+		env:    env,
+	}
+	r.env.WithProcedure("DEF", gendsl.Procedure{
+		Eval: gendsl.CheckNArgs("2", r.def),
+	})
+	return r
+}
+
+// def for (DEF {id} {value}) binds the value to the id in the session env.
+func (r *repl) def(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+	if args[0].Type() != gendsl.ExprTypeIdentifier {
+		return nil, errors.New("expecting an identifier")
+	}
+	v, err := args[1].Eval()
+	if err != nil {
+		return nil, err
+	}
+	r.env.WithValue(args[0].Text(), v)
+	return v, nil
+}
+
+// Run starts the read-eval-print loop until the input reaches EOF or `:quit` is entered.
+func (r *repl) Run() error {
+	for {
+		input, ok := r.read()
+		if !ok {
+			fmt.Fprintln(r.out)
+			return r.in.Err()
+		}
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+		r.history = append(r.history, input)
+
+		quit, err := r.exec(input)
+		if err != nil {
+			fmt.Fprintln(r.errOut, "error:", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// exec runs an entry, which is a meta-command or an expression, reports whether the REPL should quit.
+func (r *repl) exec(input string) (bool, error) {
+	if strings.HasPrefix(strings.TrimSpace(input), ":") {
+		return r.command(strings.TrimSpace(input))
+	}
+	return false, r.eval(input)
+}
+
+// read reads lines until all the parentheses are closed.
+func (r *repl) read() (string, bool) {
+	var buf strings.Builder
+	fmt.Fprint(r.out, prompt)
+	for r.in.Scan() {
+		buf.WriteString(r.in.Text())
+		buf.WriteByte('\n')
+		if strings.HasPrefix(strings.TrimSpace(buf.String()), ":") || balanced(buf.String()) {
+			return buf.String(), true
+		}
+		fmt.Fprint(r.out, continuePrompt)
+	}
+	if buf.Len() > 0 {
+		return buf.String(), true
+	}
+	return "", false
+}
+
+func (r *repl) eval(input string) error {
+	pctx, err := gendsl.MakeParseContext(input)
+	if err != nil {
+		return err
+	}
+	r.last = pctx
+
+	start := time.Now()
+	ret, err := pctx.Eval(gendsl.NewEvalCtx(nil, nil, r.env))
+	elapsed := time.Since(start)
+	if err != nil {
+		return err
+	}
+	if ret == nil {
+		ret = gendsl.Nil{}
+	}
+	r.env.WithValue("_", ret)
+
+	fmt.Fprintln(r.out, gendsl.Repr(ret))
+	if r.timing {
+		fmt.Fprintf(r.out, "; elapsed %s\n", elapsed)
+	}
+	return nil
+}
+
+// command executes the meta-commands, reports whether the REPL should quit.
+func (r *repl) command(input string) (bool, error) {
+	name, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case ":quit", ":q":
+		return true, nil
+	case ":help":
+		fmt.Fprint(r.out, replHelp)
+	case ":env":
+		r.printEnv()
+	case ":tree":
+		pctx := r.last
+		if arg != "" {
+			p, err := gendsl.MakeParseContext(arg)
+			if err != nil {
+				return false, err
+			}
+			pctx = p
+		}
+		if pctx == nil {
+			return false, errors.New("no expression to print")
+		}
+		pctx.PrintTree()
+	case ":load":
+		if arg == "" {
+			return false, errors.New("expecting a file name")
+		}
+		bs, err := os.ReadFile(arg)
+		if err != nil {
+			return false, err
+		}
+		return false, r.eval(string(bs))
+	case ":time":
+		r.timing = !r.timing
+		fmt.Fprintf(r.out, "; timing %s\n", map[bool]string{true: "on", false: "off"}[r.timing])
+	case ":history":
+		if arg != "" {
+			return r.recall(arg)
+		}
+		for i, h := range r.history[:len(r.history)-1] {
+			fmt.Fprintf(r.out, "%4d  %s\n", i+1, strings.Join(strings.Fields(h), " "))
+		}
+	default:
+		return false, errors.Errorf("unknown command %s, try :help", name)
+	}
+	return false, nil
+}
+
+// recall runs the entry numbered `arg` in the history again,
+// the entry takes the place of the :history command in the history.
+func (r *repl) recall(arg string) (bool, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n >= len(r.history) {
+		return false, errors.Errorf("no entry %s in the history", arg)
+	}
+	entry := r.history[n-1]
+	if fields := strings.Fields(entry); fields[0] == ":history" {
+		return false, errors.New("cannot run a :history command again")
+	}
+	r.history[len(r.history)-1] = entry
+	return r.exec(entry)
+}
+
+func (r *repl) printEnv() {
+	ids := make([]string, 0)
+	vals := make(map[string]gendsl.Value)
+	r.env.Range(func(id string, v gendsl.Value) bool {
+		ids = append(ids, id)
+		vals[id] = v
+		return true
+	})
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(r.out, "%-16s %-10s %s\n", id, vals[id].Type(), gendsl.Repr(vals[id]))
+	}
+}
+
+// balanced reports whether all the parentheses in `s` are closed,
+// parentheses inside strings and comments are ignored.
+func balanced(s string) bool {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], `"""`), strings.HasPrefix(s[i:], `#h"""`):
+			if s[i] == '#' {
+				i += 2
+			}
+			end := strings.Index(s[i+3:], `"""`)
+			if end < 0 {
+				return false
+			}
+			i += end + 5
+			for i+1 < len(s) && s[i+1] == '"' { // the quotes before the closing ones belong to the string
+				i++
+			}
+		case strings.HasPrefix(s[i:], "#|"):
+			nested := 0
+			for ; i+1 < len(s); i++ {
+				if strings.HasPrefix(s[i:], "#|") {
+					nested++
+					i++
+				} else if strings.HasPrefix(s[i:], "|#") {
+					nested--
+					i++
+				}
+				if nested == 0 {
+					break
+				}
+			}
+			if nested > 0 {
+				return false
+			}
+		case strings.HasPrefix(s[i:], "#;"): // a datum comment, the datum is counted as usual
+			i++
+		case s[i] == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(s) {
+				return false
+			}
+		case s[i] == ';':
+			for ; i < len(s) && s[i] != '\n'; i++ {
+			}
+		case s[i] == '(':
+			depth++
+		case s[i] == ')':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+const replHelp = `(DEF {id} {value})  bind a value to an id for later entries, _ holds the last result
+:env                list all the values in the env
+:tree [expr]        print the syntax tree of expr or the last expression
+:load {file}        evaluate the expression in a file
+:time               toggle printing the evaluation time
+:history [n]        list previous entries, or run the entry n again
+:help               print this help
+:quit               exit
+`
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+)
+
+var _ = Describe("REPL", func() {
+	run := func(input string) (string, string) {
+		var out, errOut strings.Builder
+		Expect(newREPL(strings.NewReader(input), &out, &errOut, gendsl.NewEnv()).Run()).Should(Succeed())
+		return out.String(), errOut.String()
+	}
+
+	It("knows whether the parentheses are closed", func() {
+		for s, want := range map[string]bool{
+			"(DEF x 1)":               true,
+			"(DEF x (DEF y 1)":        false,
+			"(DEF x 1))":              true,
+			`(DEF s ")")`:             true,
+			`(DEF s "(")`:             true,
+			`(DEF s "\"(")`:           true,
+			`(DEF s "(`:               false,
+			"(DEF s \"\"\"(\"\"\")":   true,
+			"(DEF s #h\"\"\"(\"\"\")": true,
+			"(DEF s \"\"\")\"\"\"\"":  false,
+			"(DEF x ; )\n":            false,
+			"(DEF x 1) ; (\n":         true,
+			"(DEF x #| ) |# 1)":       true,
+			"(DEF x #| #| |# ) |# 1)": true,
+			"(DEF x #| #| |# 1)":      false,
+			"(DEF x #;(DEF y 1) 1)":   true,
+		} {
+			Expect(balanced(s)).Should(Equal(want), s)
+		}
+	})
+
+	It("reads an expression across lines until the parentheses are closed", func() {
+		out, errOut := run("(DEF s\n  \")(\" ; )\n  )\n(DEF x #| ( |#\n  (DEF y 2))\ns\n")
+		Expect(errOut).Should(BeEmpty())
+		Expect(out).Should(Equal(prompt + continuePrompt + continuePrompt + `")("` + "\n" +
+			prompt + continuePrompt + "2\n" +
+			prompt + `")("` + "\n" +
+			prompt + "\n"))
+	})
+
+	It("binds values by DEF and keeps the last result in _", func() {
+		out, errOut := run("(DEF x 41)\nx\n_\n(DEF \"x\" 1)\n")
+		Expect(out).Should(Equal(prompt + "41\n" + prompt + "41\n" + prompt + "41\n" + prompt + prompt + "\n"))
+		Expect(errOut).Should(Equal("error: expecting an identifier\n"))
+	})
+
+	It("prints the errors and goes on", func() {
+		out, errOut := run("foo\n(DEF x\n(DEF x 1)\n")
+		Expect(errOut).Should(HavePrefix("error: "))
+		Expect(errOut).Should(ContainSubstring("foo"))
+		Expect(out).ShouldNot(ContainSubstring("1\n"))
+
+		out, errOut = run("(DEF x 1)\n(DEF x 1 2)\nx\n")
+		Expect(strings.Count(errOut, "error: ")).Should(Equal(1))
+		Expect(strings.Count(out, "1\n")).Should(Equal(2))
+	})
+
+	It("runs the meta-commands", func() {
+		out, errOut := run(":help\n")
+		Expect(errOut).Should(BeEmpty())
+		Expect(out).Should(ContainSubstring(replHelp))
+
+		out, _ = run("(DEF x 1)\n(DEF s \"a\")\n:env\n")
+		Expect(out).Should(MatchRegexp(`s\s+string\s+"a"\n`))
+		Expect(out).Should(MatchRegexp(`x\s+int\s+1\n`))
+
+		out, _ = run(":time\n(DEF x 1)\n:time\n(DEF x 2)\n")
+		Expect(out).Should(ContainSubstring("; timing on\n"))
+		Expect(out).Should(ContainSubstring("; timing off\n"))
+		Expect(strings.Count(out, "; elapsed ")).Should(Equal(1))
+
+		out, _ = run("(DEF x\n  1)\n:env\n:history\n")
+		Expect(out).Should(ContainSubstring("   1  (DEF x 1)\n   2  :env\n"))
+		Expect(out).ShouldNot(ContainSubstring("3  :history"))
+
+		out, errOut = run("(DEF x 1)\n:quit\n(DEF y 2)\n")
+		Expect(errOut).Should(BeEmpty())
+		Expect(out).ShouldNot(ContainSubstring("2\n"))
+
+		_, errOut = run(":foo\n")
+		Expect(errOut).Should(Equal("error: unknown command :foo, try :help\n"))
+	})
+
+	It("runs the entries in the history again", func() {
+		out, errOut := run("(DEF x 41)\n:history 1\n:history\n:history 4\n:history x\n")
+		Expect(out).Should(Equal(prompt + "41\n" + prompt + "41\n" +
+			prompt + "   1  (DEF x 41)\n   2  (DEF x 41)\n" + prompt + prompt + prompt + "\n"))
+		Expect(errOut).Should(Equal("error: no entry 4 in the history\nerror: no entry x in the history\n"))
+
+		out, errOut = run("(DEF x 41)\n:history 9\n:history 2\n:history 1\n:quit\n:history 5\n")
+		Expect(out).Should(Equal(prompt + "41\n" + prompt + prompt + prompt + "41\n" + prompt))
+		Expect(errOut).Should(Equal("error: no entry 9 in the history\nerror: cannot run a :history command again\n"))
+	})
+
+	It("loads a file", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "x.dsl")
+		Expect(os.WriteFile(file, []byte("(DEF x\n  42)"), 0o600)).Should(Succeed())
+
+		out, errOut := run(":load " + file + "\nx\n")
+		Expect(errOut).Should(BeEmpty())
+		Expect(strings.Count(out, "42\n")).Should(Equal(2))
+
+		_, errOut = run(":load\n:load " + file + ".missing\n")
+		Expect(strings.Count(errOut, "error: ")).Should(Equal(2))
+		Expect(errOut).Should(ContainSubstring("expecting a file name"))
+	})
+
+	It("prints the syntax tree", func() {
+		stdout := os.Stdout
+		r, w, err := os.Pipe()
+		Expect(err).ShouldNot(HaveOccurred())
+		os.Stdout = w
+		_, errOut := run(":tree\n(DEF x 1)\n:tree\n:tree (DEF y 2)\n:tree (DEF\n")
+		os.Stdout = stdout
+		Expect(w.Close()).Should(Succeed())
+		tree, err := io.ReadAll(r)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(strings.Count(string(tree), "Script")).Should(Equal(2))
+		Expect(string(tree)).Should(ContainSubstring("x"))
+		Expect(string(tree)).Should(ContainSubstring("y"))
+		Expect(strings.Count(errOut, "error: ")).Should(Equal(2))
+		Expect(errOut).Should(ContainSubstring("no expression to print"))
+	})
+})
@@ -0,0 +1,24 @@
+// package main provides a language server that serves LSP over the stdio for the scripts of cmd/echo.
+// It also shows how a host program plugs its own env into the [lsp] package,
+// a DSL built with gendsl should provide its own command in the same way.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ccbhj/gendsl"
+	"github.com/ccbhj/gendsl/lsp"
+)
+
+func main() {
+	env := gendsl.NewEnv().WithBuiltins().WithProcedure("ECHO", gendsl.Procedure{
+		Doc: "ECHO prints its arguments line by line and returns the amount of arguments printed.",
+	})
+
+	server := lsp.NewServer(lsp.Config{Env: env})
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,304 @@
+package gendsl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Equaler can be implemented by the V of a UserData to decide whether it equals to another Value, see [gendsl.Equal].
+	Equaler interface {
+		Equal(other Value) bool
+	}
+
+	// Comparer can be implemented by the V of a UserData to order it with another Value, see [gendsl.Compare].
+	// It returns -1, 0 or +1 as the value is less than, equal to or greater than `other`,
+	// or an error if they cannot be compared.
+	Comparer interface {
+		Compare(other Value) (int, error)
+	}
+
+	// Hasher can be implemented by the V of a UserData to be hashed by [gendsl.Hash].
+	// The values that are equal should have the same hash.
+	Hasher interface {
+		Hash() uint64
+	}
+)
+
+// Equal reports whether `a` equals to `b`, nil is taken as Nil:
+//   - numbers of any type are equal if their exact values are equal, so Int(1) equals to Float(1) and 1.0m, but NaN equals to nothing.
+//   - Times are equal if they are the same instant regardless of the locations.
+//   - Symbols and Keywords are equal if they have the same name, Strings and Bytes are compared byte-wise.
+//   - Regexps are equal if they have the same pattern, and Codes are equal if they have the same text.
+//   - A UserData whose V implements [gendsl.Equaler] decides by itself, no matter which side it is on.
+//     The other UserDatas are equal if their Vs are both []Value with the elements equal, or if the Vs are [reflect.DeepEqual].
+//   - Procedures are never equal.
+//
+// Values of the other different types are not equal.
+func Equal(a, b Value) bool {
+	a, b = nilToNil(a), nilToNil(b)
+	if eq, ok := userData[Equaler](a); ok {
+		return eq.Equal(b)
+	}
+	if eq, ok := userData[Equaler](b); ok {
+		return eq.Equal(a)
+	}
+	if _, ok := numKind(a); ok {
+		if _, ok := numKind(b); ok {
+			c, err := compareNumbers(a, b)
+			return err == nil && c == 0
+		}
+	}
+	switch x := a.(type) {
+	case String, Bool, Nil, Symbol, Keyword, Duration:
+		return a == b
+	case Bytes:
+		y, ok := b.(Bytes)
+		return ok && bytes.Equal(x, y)
+	case Time:
+		y, ok := b.(Time)
+		return ok && time.Time(x).Equal(time.Time(y))
+	case Regexp:
+		y, ok := b.(Regexp)
+		return ok && x.re.String() == y.re.String()
+	case Code:
+		y, ok := b.(Code)
+		return ok && x.Text() == y.Text()
+	case *UserData:
+		y, ok := b.(*UserData)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		xs, xok := x.V.([]Value)
+		ys, yok := y.V.([]Value)
+		if xok && yok {
+			return equalValues(xs, ys)
+		}
+		return reflect.DeepEqual(x.V, y.V)
+	}
+	return false
+}
+
+func equalValues(xs, ys []Value) bool {
+	if len(xs) != len(ys) {
+		return false
+	}
+	for i := range xs {
+		if !Equal(xs[i], ys[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns the hash of `v` that is consistent with [gendsl.Equal], so that the values can be used as keys of a hash table.
+// A UserData can be hashed if its V implements [gendsl.Hasher] or it is a []Value, an error is returned for the other UserDatas and Procedures.
+// A UserData whose V equals to a value of another type should make sure they have the same hash by itself.
+func Hash(v Value) (uint64, error) {
+	h := fnv.New64a()
+	if err := writeHash(h, nilToNil(v)); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+type hashWriter interface {
+	Write([]byte) (int, error)
+}
+
+func writeHash(h hashWriter, v Value) error {
+	var (
+		tag byte
+		buf []byte
+	)
+	switch x := v.(type) {
+	case Int:
+		tag, buf = 'n', strconv.AppendInt(buf, int64(x), 10)
+	case Uint:
+		tag, buf = 'n', strconv.AppendUint(buf, uint64(x), 10)
+	case BigInt, Decimal, Float:
+		r, inf, err := toRat(x)
+		switch {
+		case err != nil: // NaN
+			tag, buf = 'n', []byte("nan")
+		case inf != 0:
+			tag, buf = 'n', strconv.AppendInt(buf, int64(inf), 10)
+			buf = append(buf, "inf"...)
+		default:
+			tag, buf = 'n', []byte(r.RatString())
+		}
+	case String:
+		tag, buf = 's', []byte(x)
+	case Bytes:
+		tag, buf = 'b', x
+	case Bool:
+		tag, buf = 't', []byte(strconv.FormatBool(bool(x)))
+	case Nil:
+		tag = '0'
+	case Symbol:
+		tag, buf = 'y', []byte(x.Name())
+	case Keyword:
+		tag, buf = 'k', []byte(x.Name())
+	case Time:
+		t := time.Time(x)
+		tag = 'i'
+		buf = binary.BigEndian.AppendUint64(buf, uint64(t.Unix()))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(t.Nanosecond()))
+	case Duration:
+		tag, buf = 'd', binary.BigEndian.AppendUint64(buf, uint64(x))
+	case Regexp:
+		tag, buf = 'r', []byte(x.re.String())
+	case Code:
+		tag, buf = 'c', []byte(x.Text())
+	case *UserData:
+		if hs, ok := x.V.(Hasher); ok {
+			tag, buf = 'u', binary.BigEndian.AppendUint64(buf, hs.Hash())
+			break
+		}
+		vs, ok := x.V.([]Value)
+		if !ok {
+			return errors.Errorf("cannot hash userdata of %T, it should implement gendsl.Hasher", x.V)
+		}
+		_, _ = h.Write([]byte{'l'})
+		for _, elem := range vs {
+			if err := writeHash(h, elem); err != nil {
+				return err
+			}
+		}
+		tag, buf = ';', nil
+	default:
+		return errors.Errorf("cannot hash %s", typeName(v))
+	}
+	_, _ = h.Write([]byte{tag})
+	_, _ = h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(buf))))
+	_, _ = h.Write(buf)
+	return nil
+}
+
+// Compare returns -1, 0 or +1 as `a` is less than, equal to or greater than `b`.
+//
+// The values that can be compared are:
+//   - numbers of Int, Uint, BigInt, Decimal and Float, they are compared by their exact values, so Int(1) equals to 1.0m,
+//     and a NaN cannot be compared.
+//   - Strings, compared byte-wise.
+//   - Bytes, compared byte-wise.
+//   - Times, compared by the instants regardless of the locations.
+//   - Durations.
+//   - Symbols, compared by their names, and so are Keywords.
+//   - A UserData whose V implements [gendsl.Comparer], no matter which side it is on.
+func Compare(a, b Value) (int, error) {
+	if cmp, ok := userData[Comparer](a); ok {
+		return cmp.Compare(b)
+	}
+	if cmp, ok := userData[Comparer](b); ok {
+		c, err := cmp.Compare(a)
+		return -c, err
+	}
+	if _, ok := numKind(a); ok {
+		if _, ok := numKind(b); ok {
+			return compareNumbers(a, b)
+		}
+	}
+	switch x := a.(type) {
+	case String:
+		if y, ok := b.(String); ok {
+			return strings.Compare(string(x), string(y)), nil
+		}
+	case Bytes:
+		if y, ok := b.(Bytes); ok {
+			return bytes.Compare(x, y), nil
+		}
+	case Time:
+		if y, ok := b.(Time); ok {
+			return time.Time(x).Compare(time.Time(y)), nil
+		}
+	case Duration:
+		if y, ok := b.(Duration); ok {
+			return compareInt(int64(x), int64(y)), nil
+		}
+	case Symbol:
+		if y, ok := b.(Symbol); ok {
+			return strings.Compare(x.Name(), y.Name()), nil
+		}
+	case Keyword:
+		if y, ok := b.(Keyword); ok {
+			return strings.Compare(x.Name(), y.Name()), nil
+		}
+	}
+	return 0, errors.Errorf("cannot compare %s and %s", typeName(a), typeName(b))
+}
+
+// userData returns the V of `v` as T if `v` is a UserData.
+func userData[T any](v Value) (T, bool) {
+	if u, ok := v.(*UserData); ok && u != nil {
+		t, ok := u.V.(T)
+		return t, ok
+	}
+	var zero T
+	return zero, false
+}
+
+func nilToNil(v Value) Value {
+	if v == nil {
+		return Nil{}
+	}
+	return v
+}
+
+func compareNumbers(a, b Value) (int, error) {
+	x, xinf, err := toRat(a)
+	if err != nil {
+		return 0, err
+	}
+	y, yinf, err := toRat(b)
+	if err != nil {
+		return 0, err
+	}
+	if xinf != 0 || yinf != 0 {
+		return compareInt(int64(xinf), int64(yinf)), nil
+	}
+	return x.Cmp(y), nil
+}
+
+// toRat returns the exact value of a number, or the sign of an infinite Float.
+func toRat(v Value) (*big.Rat, int, error) {
+	switch v := v.(type) {
+	case Float:
+		f := float64(v)
+		switch {
+		case math.IsNaN(f):
+			return nil, 0, errors.New("cannot compare NaN")
+		case math.IsInf(f, 0):
+			if f > 0 {
+				return nil, 1, nil
+			}
+			return nil, -1, nil
+		}
+		return new(big.Rat).SetFloat64(f), 0, nil
+	case Decimal:
+		return v.Rat(), 0, nil
+	}
+	return new(big.Rat).SetInt(toBigInt(v)), 0, nil
+}
+
+func compareInt(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
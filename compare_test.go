@@ -0,0 +1,131 @@
+package gendsl
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// caseless is a string that equals to and orders with the Strings regardless of the cases.
+type caseless string
+
+func (c caseless) Equal(other Value) bool {
+	s, err := ToString(other)
+	return err == nil && strings.EqualFold(string(c), string(s))
+}
+
+func (c caseless) Compare(other Value) (int, error) {
+	s, err := ToString(other)
+	if err != nil {
+		return 0, err
+	}
+	return strings.Compare(strings.ToLower(string(c)), strings.ToLower(string(s))), nil
+}
+
+func (c caseless) Hash() uint64 {
+	h, _ := Hash(String(strings.ToLower(string(c))))
+	return h
+}
+
+var _ = Describe("Equal and Hash", func() {
+	It("tells the values equal across the types of numbers", func() {
+		t := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		for _, pair := range [][2]Value{
+			{Int(1), Float(1)},
+			{Int(1), Uint(1)},
+			{Int(-1), decimal("-1.00")},
+			{Float(0.5), decimal("0.5")},
+			{bigInt("18446744073709551616"), Float(1 << 64)},
+			{Float(math.Inf(1)), Float(math.Inf(1))},
+			{String("a"), String("a")},
+			{Bytes("a"), Bytes("a")},
+			{NewSymbol("a"), NewSymbol("a")},
+			{NewKeyword("a"), NewKeyword("a")},
+			{Time(t), Time(t.In(time.FixedZone("UTC+8", 8*3600)))},
+			{Duration(time.Second), Duration(time.Second)},
+			{nil, Nil{}},
+			{Bool(true), Bool(true)},
+			{NewRegexp(regexp.MustCompile("a+")), NewRegexp(regexp.MustCompile("a+"))},
+			{&UserData{V: []Value{Int(1), String("a")}}, &UserData{V: []Value{Float(1), String("a")}}},
+		} {
+			gomega.Expect(Equal(pair[0], pair[1])).Should(gomega.BeTrue(), Repr(pair[0]))
+			gomega.Expect(Equal(pair[1], pair[0])).Should(gomega.BeTrue(), Repr(pair[0]))
+			h1, err := Hash(pair[0])
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			h2, err := Hash(pair[1])
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Expect(h1).Should(gomega.Equal(h2), Repr(pair[0]))
+		}
+
+		for _, pair := range [][2]Value{
+			{Int(1), Float(1.5)},
+			{Float(math.NaN()), Float(math.NaN())},
+			{Int(1), String("1")},
+			{String("a"), NewSymbol("a")},
+			{NewSymbol("a"), NewKeyword("a")},
+			{Bytes("a"), String("a")},
+			{Nil{}, Bool(false)},
+			{&UserData{V: []Value{Int(1)}}, &UserData{V: []Value{Int(1), Int(2)}}},
+			{Procedure{}, Procedure{}},
+		} {
+			gomega.Expect(Equal(pair[0], pair[1])).Should(gomega.BeFalse(), Repr(pair[0]))
+			gomega.Expect(Equal(pair[1], pair[0])).Should(gomega.BeFalse(), Repr(pair[0]))
+		}
+
+		h1, _ := Hash(String("1"))
+		h2, _ := Hash(Int(1))
+		gomega.Expect(h1).ShouldNot(gomega.Equal(h2))
+	})
+
+	It("compares the codes by their texts", func() {
+		v1, err := EvalExpr(`'(PLUS 1 2)`, NewEnv())
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		v2, err := EvalExpr(`(quote (PLUS 1 2))`, NewEnv().WithBuiltins())
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(Equal(v1, v2)).Should(gomega.BeTrue())
+	})
+
+	It("lets the userdata decide", func() {
+		u := &UserData{V: Map{"a": Int(1)}}
+		gomega.Expect(Equal(u, u)).Should(gomega.BeTrue())
+		gomega.Expect(Equal(u, &UserData{V: Map{"a": Int(1)}})).Should(gomega.BeTrue())
+		gomega.Expect(Equal(u, &UserData{V: Map{"a": Int(2)}})).Should(gomega.BeFalse())
+		_, err := Hash(u)
+		gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("gendsl.Hasher")))
+		_, err = Hash(Procedure{})
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+
+		foo := &UserData{V: caseless("FOO")}
+		gomega.Expect(Equal(String("foo"), foo)).Should(gomega.BeTrue())
+		gomega.Expect(Equal(foo, String("bar"))).Should(gomega.BeFalse())
+		h1, err := Hash(foo)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		h2, err := Hash(&UserData{V: caseless("Foo")})
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(h1).Should(gomega.Equal(h2))
+
+		c, err := Compare(foo, String("bar"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(c).Should(gomega.Equal(1))
+		c, err = Compare(String("bar"), foo)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(c).Should(gomega.Equal(-1))
+		_, err = Compare(foo, Int(1))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+
+	It("orders the symbols and keywords by their names", func() {
+		c, err := Compare(NewSymbol("a"), NewSymbol("b"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(c).Should(gomega.Equal(-1))
+		c, err = Compare(NewKeyword("b"), NewKeyword("a"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(c).Should(gomega.Equal(1))
+		_, err = Compare(NewKeyword("a"), NewSymbol("a"))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+})
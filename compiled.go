@@ -0,0 +1,485 @@
+package gendsl
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The encoding of a compiled script is:
+//
+//	magic       "GDSL"
+//	version     uvarint, compiledVersion
+//	grammar     8 bytes, grammarFingerprint in big endian
+//	source      uvarint length + UTF-8 bytes
+//	tokens      uvarint count + (rule uvarint, begin delta varint, length uvarint) for each token
+//
+// The positions of the tokens are in runes, and the empty tokens are dropped since they never appear in the tree.
+const (
+	compiledMagic   = "GDSL"
+	compiledVersion = 1
+)
+
+// ErrIncompatibleCompiled is returned by [gendsl.LoadCompiled]
+// when the script was compiled by an incompatible version of the grammar or the encoding.
+var ErrIncompatibleCompiled = errors.New("incompatible compiled script")
+
+// grammarSource is the grammar that grammar.go is generated from.
+//
+//go:embed grammar.peg
+var grammarSource []byte
+
+// grammarFingerprint identifies the grammar, it changes whenever the grammar is changed.
+var grammarFingerprint = func() uint64 {
+	h := fnv.New64a()
+	h.Write(grammarSource)
+	return h.Sum64()
+}()
+
+// MarshalBinary encodes the compiled script so that it can be loaded by [gendsl.LoadCompiled] without parsing.
+func (c *ParseContext) MarshalBinary() ([]byte, error) {
+	src := c.p.Buffer
+	tokens := c.p.Tokens()
+	buf := make([]byte, 0, len(compiledMagic)+len(src)+len(tokens)*4+32)
+	buf = append(buf, compiledMagic...)
+	buf = binary.AppendUvarint(buf, compiledVersion)
+	buf = binary.BigEndian.AppendUint64(buf, grammarFingerprint)
+	buf = binary.AppendUvarint(buf, uint64(len(src)))
+	buf = append(buf, src...)
+
+	count := 0
+	for _, t := range tokens {
+		if t.begin != t.end {
+			count++
+		}
+	}
+	buf = binary.AppendUvarint(buf, uint64(count))
+	var prev int64
+	for _, t := range tokens {
+		if t.begin == t.end {
+			continue
+		}
+		buf = binary.AppendUvarint(buf, uint64(t.pegRule))
+		buf = binary.AppendVarint(buf, int64(t.begin)-prev)
+		buf = binary.AppendUvarint(buf, uint64(t.end-t.begin))
+		prev = int64(t.begin)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary loads a script encoded by [gendsl.ParseContext.MarshalBinary], see [gendsl.LoadCompiled].
+func (c *ParseContext) UnmarshalBinary(data []byte) error {
+	pc, err := LoadCompiled(data)
+	if err != nil {
+		return err
+	}
+	*c = *pc
+	return nil
+}
+
+// LoadCompiled loads a script encoded by [gendsl.ParseContext.MarshalBinary].
+// [gendsl.ErrIncompatibleCompiled] is returned if it was compiled by an incompatible version of gendsl,
+// and an error is returned if the tokens in it do not fit the grammar or the source.
+func LoadCompiled(data []byte) (*ParseContext, error) {
+	r := compiledReader{data: data}
+	if string(r.bytes(len(compiledMagic))) != compiledMagic {
+		return nil, errors.WithMessage(ErrIncompatibleCompiled, "not a compiled script")
+	}
+	if v := r.uvarint(); r.err == nil && v != compiledVersion {
+		return nil, errors.WithMessagef(ErrIncompatibleCompiled, "encoding version %d is not supported", v)
+	}
+	if fp := r.bytes(8); r.err == nil && binary.BigEndian.Uint64(fp) != grammarFingerprint {
+		return nil, errors.WithMessage(ErrIncompatibleCompiled, "compiled by another version of the grammar")
+	}
+	src := string(r.bytes(int(r.uvarint())))
+
+	p := &parser{Buffer: src, Pretty: true}
+	if err := p.Init(); err != nil {
+		return nil, err
+	}
+	count := r.uvarint()
+	if r.err == nil && count > uint64(len(data)) { // each token takes 3 bytes at least
+		r.err = errors.New("too many tokens")
+	}
+	tokens := make([]token32, 0, count)
+	var begin int64
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		rule := r.uvarint()
+		begin += r.varint()
+		length := r.uvarint()
+		end := begin + int64(length)
+		if rule >= uint64(len(rul3s)) || begin < 0 || length == 0 || end >= int64(len(p.buffer)) { // the last rune is the endSymbol
+			r.err = errors.Errorf("invalid token #%d", i)
+			break
+		}
+		tokens = append(tokens, token32{pegRule: pegRule(rule), begin: uint32(begin), end: uint32(end)})
+	}
+	if r.err == nil && len(r.data) != 0 {
+		r.err = errors.New("unexpected trailing bytes")
+	}
+	if r.err != nil {
+		return nil, errors.WithMessage(r.err, "invalid compiled script")
+	}
+	if err := checkTree(tokens); err != nil {
+		return nil, errors.WithMessage(err, "invalid compiled script")
+	}
+	if err := checkLiterals(p.buffer, tokens); err != nil {
+		return nil, errors.WithMessage(err, "invalid compiled script")
+	}
+	p.tree = tokens
+	return newParseContext(p)
+}
+
+// checkTree checks that the tokens form a syntax tree like the parser builds:
+// the children come before their parent and are nested inside it without overlapping each other,
+// the rules of the children fit the rule of their parent in the grammar,
+// and all the tokens are inside one Script at last.
+func checkTree(tokens []token32) error {
+	var (
+		stack    []token32
+		children []rune
+	)
+	for i, t := range tokens {
+		n := len(stack)
+		for n > 0 && stack[n-1].begin >= t.begin && stack[n-1].end <= t.end {
+			n--
+		}
+		if n > 0 && stack[n-1].end > t.begin {
+			return errors.Errorf("token #%d overlaps another one", i)
+		}
+		children = children[:0]
+		for _, child := range stack[n:] {
+			children = append(children, ruleRune(child.pegRule))
+		}
+		if re := childRules[t.pegRule]; re == nil || !re.MatchString(string(children)) {
+			return errors.Errorf("token #%d of %s does not fit the grammar", i, rul3s[t.pegRule])
+		}
+		stack = append(stack[:n], t)
+	}
+	switch {
+	case len(stack) == 0:
+		return errors.New("empty syntax tree")
+	case len(stack) > 1 || stack[0].pegRule != ruleScript:
+		return errors.New("the syntax tree has no Script root")
+	}
+	return nil
+}
+
+// checkLiterals checks that the text of each Literal is lexed to the tokens under it,
+// since the values of the literals are converted from their text.
+func checkLiterals(buffer []rune, tokens []token32) error {
+	p := &parser{}
+	if err := p.Init(); err != nil {
+		return err
+	}
+	for i, t := range tokens {
+		if t.pegRule != ruleLiteral {
+			continue
+		}
+		first := i // the first token under the Literal
+		for first > 0 && tokens[first-1].begin >= t.begin {
+			first--
+		}
+		p.Buffer = string(buffer[t.begin:t.end])
+		p.Reset()
+		if err := p.Parse(int(ruleLiteral)); err != nil {
+			return errors.Errorf("token #%d is not a Literal", i)
+		}
+		want := tokens[first : i+1]
+		for _, lexed := range p.Tokens() {
+			if lexed.begin == lexed.end {
+				continue
+			}
+			if len(want) == 0 || want[0] != (token32{lexed.pegRule, lexed.begin + t.begin, lexed.end + t.begin}) {
+				return errors.Errorf("token #%d is not the Literal in the text", i)
+			}
+			want = want[1:]
+		}
+		if len(want) > 0 {
+			return errors.Errorf("token #%d is not the Literal in the text", i)
+		}
+	}
+	return nil
+}
+
+// childRules holds the regexps for each rule that match the rules of the children of its tokens,
+// in which a rule is written as ruleRune(rule).
+// They are derived from grammarSource, where the terminals and the predicates are dropped since they add no tokens,
+// and the rules that can match nothing are optional since their empty tokens are dropped.
+var childRules = compileChildRules(string(grammarSource))
+
+func ruleRune(rule pegRule) rune {
+	return 0xE000 + rune(rule) // in the private use area so that it is never special in a regexp
+}
+
+type (
+	// pegExpr is an expression in the grammar.
+	pegExpr struct {
+		op   byte // 's' for a sequence, '/' for a choice, '*', '+' or '?' for a repetition, 'r' for a rule, 't' for a terminal, 0 for a predicate
+		rule string
+		subs []*pegExpr
+	}
+
+	// pegReader reads the expression of a rule from the tokens of the grammar.
+	pegReader struct {
+		tokens []string
+	}
+)
+
+func compileChildRules(grammar string) []*regexp.Regexp {
+	tokens := pegTokens(grammar)
+	exprs := make(map[string]*pegExpr)
+	for i := 0; i+1 < len(tokens); {
+		if tokens[i+1] != "<-" { // the header before the first rule
+			i++
+			continue
+		}
+		end := i + 2
+		for end < len(tokens) && (end+1 >= len(tokens) || tokens[end+1] != "<-") {
+			end++
+		}
+		r := &pegReader{tokens: tokens[i+2 : end]}
+		exprs[tokens[i]] = r.choice()
+		if len(r.tokens) > 0 {
+			panic(fmt.Sprintf("unexpected %q in the rule %s of the grammar", r.tokens[0], tokens[i]))
+		}
+		i = end
+	}
+
+	nullable := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for name, e := range exprs {
+			if !nullable[name] && e.nullable(nullable) {
+				nullable[name], changed = true, true
+			}
+		}
+	}
+
+	rules := make(map[string]pegRule, len(rul3s))
+	for i, name := range rul3s {
+		rules[name] = pegRule(i)
+	}
+	ret := make([]*regexp.Regexp, len(rul3s))
+	for name, e := range exprs {
+		rule, ok := rules[name]
+		if !ok {
+			panic(fmt.Sprintf("the rule %s of the grammar is not generated", name))
+		}
+		ret[rule] = regexp.MustCompile("^(?:" + e.pattern(rules, nullable) + ")$")
+	}
+	return ret
+}
+
+// pegTokens splits the grammar into identifiers and operators, a terminal is read as a single "'".
+func pegTokens(grammar string) []string {
+	tokens := make([]string, 0, len(grammar)/4)
+	for i := 0; i < len(grammar); {
+		switch c := grammar[i]; {
+		case c == '#': // comments
+			for i < len(grammar) && grammar[i] != '\n' {
+				i++
+			}
+		case c == ' ', c == '\t', c == '\r', c == '\n':
+			i++
+		case c == '\'', c == '"', c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			for i++; i < len(grammar) && grammar[i] != closing; i++ {
+				if grammar[i] == '\\' {
+					i++
+				}
+			}
+			tokens = append(tokens, "'")
+			i++
+		case c == '.':
+			tokens = append(tokens, "'")
+			i++
+		case strings.HasPrefix(grammar[i:], "<-"):
+			tokens = append(tokens, "<-")
+			i += 2
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			j := i + 1
+			for j < len(grammar) && (grammar[j] == '_' || 'a' <= grammar[j] && grammar[j] <= 'z' || 'A' <= grammar[j] && grammar[j] <= 'Z' || '0' <= grammar[j] && grammar[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, grammar[i:j])
+			i = j
+		default:
+			tokens = append(tokens, grammar[i:i+1])
+			i++
+		}
+	}
+	return tokens
+}
+
+func (r *pegReader) peek() string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	return r.tokens[0]
+}
+
+func (r *pegReader) next() string {
+	t := r.peek()
+	if t != "" {
+		r.tokens = r.tokens[1:]
+	}
+	return t
+}
+
+func (r *pegReader) choice() *pegExpr {
+	e := &pegExpr{op: '/', subs: []*pegExpr{r.sequence()}}
+	for r.peek() == "/" {
+		r.next()
+		e.subs = append(e.subs, r.sequence())
+	}
+	return e
+}
+
+func (r *pegReader) sequence() *pegExpr {
+	e := &pegExpr{op: 's'}
+	for t := r.peek(); t != "" && t != "/" && t != ")"; t = r.peek() {
+		e.subs = append(e.subs, r.prefixed())
+	}
+	return e
+}
+
+func (r *pegReader) prefixed() *pegExpr {
+	if t := r.peek(); t == "!" || t == "&" {
+		r.next()
+		r.suffixed()
+		return &pegExpr{}
+	}
+	return r.suffixed()
+}
+
+func (r *pegReader) suffixed() *pegExpr {
+	e := r.primary()
+	for t := r.peek(); t == "*" || t == "+" || t == "?"; t = r.peek() {
+		r.next()
+		e = &pegExpr{op: t[0], subs: []*pegExpr{e}}
+	}
+	return e
+}
+
+func (r *pegReader) primary() *pegExpr {
+	switch t := r.next(); t {
+	case "(":
+		e := r.choice()
+		if r.next() != ")" {
+			panic("unclosed parenthesis in the grammar")
+		}
+		return e
+	case "'":
+		return &pegExpr{op: 't'}
+	default:
+		return &pegExpr{op: 'r', rule: t}
+	}
+}
+
+// nullable reports whether the expression can match nothing, `rules` tells the rules that can.
+func (e *pegExpr) nullable(rules map[string]bool) bool {
+	switch e.op {
+	case 0, '*', '?':
+		return true
+	case 't':
+		return false
+	case 'r':
+		return rules[e.rule]
+	case '+':
+		return e.subs[0].nullable(rules)
+	case 's':
+		for _, sub := range e.subs {
+			if !sub.nullable(rules) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, sub := range e.subs { // '/'
+		if sub.nullable(rules) {
+			return true
+		}
+	}
+	return false
+}
+
+// pattern returns the regexp that matches the rules of the tokens added by the expression.
+func (e *pegExpr) pattern(rules map[string]pegRule, nullable map[string]bool) string {
+	switch e.op {
+	case 0, 't':
+		return ""
+	case 'r':
+		rule, ok := rules[e.rule]
+		if !ok {
+			panic(fmt.Sprintf("the rule %s of the grammar is not generated", e.rule))
+		}
+		if nullable[e.rule] {
+			return string(ruleRune(rule)) + "?"
+		}
+		return string(ruleRune(rule))
+	case '*', '+', '?':
+		return "(?:" + e.subs[0].pattern(rules, nullable) + ")" + string(e.op)
+	}
+	subs := make([]string, len(e.subs))
+	for i, sub := range e.subs {
+		subs[i] = sub.pattern(rules, nullable)
+	}
+	if e.op == 's' {
+		return strings.Join(subs, "")
+	}
+	return "(?:" + strings.Join(subs, "|") + ")"
+}
+
+// compiledReader reads a compiled script, it stops at the first error.
+type compiledReader struct {
+	data []byte
+	err  error
+}
+
+func (r *compiledReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data) {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	ret := r.data[:n]
+	r.data = r.data[n:]
+	return ret
+}
+
+func (r *compiledReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errors.New("invalid varint")
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *compiledReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errors.New("invalid varint")
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
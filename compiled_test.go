@@ -0,0 +1,175 @@
+package gendsl
+
+import (
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Compiled", func() {
+	script := `; sum them up
+(PLUS 1
+  (PLUS #:n "中文" 2 3) ; comment
+  foo)`
+
+	It("can load a compiled script", func() {
+		pc, err := MakeParseContext(script)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		bs, err := pc.MarshalBinary()
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+		loaded, err := LoadCompiled(bs)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(loaded.Root().Text()).Should(gomega.Equal(pc.Root().Text()))
+		gomega.Expect(loaded.Comments()).Should(gomega.Equal(pc.Comments()))
+		gomega.Expect(loaded.Root().Args()[1].Range()).Should(gomega.Equal(pc.Root().Args()[1].Range()))
+
+		env := NewEnv().WithProcedure("PLUS", Procedure{Eval: _plus}).WithInt("foo", 10)
+		gomega.Expect(loaded.Eval(NewEvalCtx(nil, nil, env))).Should(gomega.Equal(Int(16)))
+
+		_, err = loaded.Eval(NewEvalCtx(nil, nil, NewEnv().WithProcedure("PLUS", Procedure{Eval: _plus})))
+		var ee *UnboundedIdentifierError
+		gomega.Expect(errors.As(err, &ee)).Should(gomega.BeTrue())
+		gomega.Expect(err.Error()).Should(gomega.ContainSubstring("line 4"))
+
+		var unmarshaled ParseContext
+		gomega.Expect(unmarshaled.UnmarshalBinary(bs)).Should(gomega.Succeed())
+		gomega.Expect(unmarshaled.Root().Text()).Should(gomega.Equal(pc.Root().Text()))
+	})
+
+	It("rejects incompatible or broken scripts", func() {
+		pc, err := MakeParseContext(script)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		bs, err := pc.MarshalBinary()
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+		_, err = LoadCompiled([]byte("(PLUS 1 2)"))
+		gomega.Expect(err).Should(gomega.MatchError(ErrIncompatibleCompiled))
+
+		version := append([]byte(compiledMagic), binary.AppendUvarint(nil, compiledVersion+1)...)
+		_, err = LoadCompiled(append(version, bs[len(version):]...))
+		gomega.Expect(err).Should(gomega.MatchError(ErrIncompatibleCompiled))
+
+		grammar := append([]byte(nil), bs...)
+		grammar[len(compiledMagic)+1] ^= 0xff
+		_, err = LoadCompiled(grammar)
+		gomega.Expect(err).Should(gomega.MatchError(ErrIncompatibleCompiled))
+
+		for _, n := range []int{len(bs) - 1, len(bs) / 2, 14} {
+			_, err = LoadCompiled(bs[:n])
+			gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("invalid compiled script")))
+		}
+	})
+
+	It("rejects the tokens that do not form a syntax tree", func() {
+		compile := func(mutate func(tokens []token32) []token32) []byte {
+			pc, err := MakeParseContext(script)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			pc.p.tree = mutate(append([]token32(nil), pc.p.Tokens()...))
+			bs, err := pc.MarshalBinary()
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			return bs
+		}
+		for name, mutate := range map[string]func(tokens []token32) []token32{
+			"root shrunk": func(tokens []token32) []token32 {
+				tokens[len(tokens)-1].end = tokens[len(tokens)-1].begin + 1
+				return tokens
+			},
+			"root missing": func(tokens []token32) []token32 { return tokens[:len(tokens)-1] },
+			"root renamed": func(tokens []token32) []token32 {
+				tokens[len(tokens)-1].pegRule = ruleExpression
+				return tokens
+			},
+			"parent first": func(tokens []token32) []token32 {
+				return append(tokens[len(tokens)-1:], tokens[:len(tokens)-1]...)
+			},
+			"rule changed": func(tokens []token32) []token32 {
+				for i := range tokens {
+					if tokens[i].pegRule == ruleExpression {
+						tokens[i].pegRule = ruleQuote
+						break
+					}
+				}
+				return tokens
+			},
+			"child dropped": func(tokens []token32) []token32 {
+				for i := range tokens {
+					if tokens[i].pegRule == ruleOperator {
+						return append(tokens[:i], tokens[i+1:]...)
+					}
+				}
+				return tokens
+			},
+			"literal changed": func(tokens []token32) []token32 {
+				for i := range tokens {
+					if tokens[i].pegRule == ruleStringLiteral {
+						tokens[i].end-- // without the closing quote
+						return tokens
+					}
+				}
+				return tokens
+			},
+			"siblings overlapped": func(tokens []token32) []token32 {
+				for i := 1; i < len(tokens); i++ {
+					if tokens[i].begin >= tokens[i-1].end {
+						tokens[i].begin = tokens[i-1].end - 1
+						break
+					}
+				}
+				return tokens
+			},
+		} {
+			_, err := LoadCompiled(compile(mutate))
+			gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("invalid compiled script")), name)
+		}
+
+		_, err := LoadCompiled(compile(func(tokens []token32) []token32 { return tokens }))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	})
+
+	It("rejects the corrupted scripts instead of panicking", func() {
+		pc, err := MakeParseContext("(PLUS 1 #h\"\"\"a\"\"\" 1.5m 10ms #x\"ff\" :k '(x) `(y ,foo) #f\"a${foo}\" #rx\"a+\" #:n 2 #;(PLUS) foo.bar)")
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		tokens := make([]token32, 0)
+		for _, t := range pc.p.Tokens() {
+			if t.begin != t.end {
+				tokens = append(tokens, t)
+			}
+		}
+		src := []rune(pc.p.Buffer)
+		env := NewEnv().WithBuiltins().WithProcedure("PLUS", Procedure{Eval: _plus}).WithInt("foo", 10)
+		load := func(src []rune, tokens []token32) {
+			corrupted := *pc
+			corrupted.p = &parser{Buffer: string(src), tokens32: tokens32{tree: tokens}}
+			bs, err := corrupted.MarshalBinary()
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Expect(func() {
+				loaded, err := LoadCompiled(bs)
+				if err != nil {
+					return
+				}
+				_, _ = loaded.Eval(NewEvalCtx(nil, nil, env))
+				_ = loaded.Comments()
+				Inspect(loaded.Root(), func(n Node) bool { _ = n.Text(); return true })
+			}).ShouldNot(gomega.Panic(), string(src))
+		}
+
+		for i := range tokens {
+			for rule := range rul3s {
+				corrupted := append([]token32(nil), tokens...)
+				corrupted[i].pegRule = pegRule(rule)
+				load(src, corrupted)
+			}
+			load(src, append(append([]token32(nil), tokens[:i]...), tokens[i+1:]...))
+		}
+		for i := range src {
+			for _, r := range "x\"(;#" {
+				corrupted := append([]rune(nil), src...)
+				corrupted[i] = r
+				load(corrupted, tokens)
+			}
+		}
+	})
+})
@@ -0,0 +1,355 @@
+// Package config generates a declarative config DSL from a Go struct type.
+//
+// The root struct is built by a procedure, whose fields are set by options, and by the nested calls
+// named after the fields, a nested struct is built by its own call and a slice accepts repeated calls:
+//
+//	type Route struct {
+//		Path    string `dsl:"path"`
+//		Backend string `dsl:"backend"`
+//	}
+//	type Server struct {
+//		Host   string  `dsl:"host"`
+//		Port   int     `dsl:"port"`
+//		Routes []Route `dsl:"route"`
+//	}
+//
+//	dsl, err := config.New[Server]("server")
+//	srv, err := dsl.Eval(`
+//	(server #:host "localhost"
+//	  (port 8080)
+//	  (route #:path "/" #:backend "web")
+//	  (route #:path "/api" #:backend "api"))`)
+//
+// A field is named by its `dsl` tag, or its name in lower case if it has no tag, and the fields tagged with `dsl:"-"` are skipped.
+// The values are decoded into the fields by [gendsl.Decode], and an [config.Error] is returned with the position
+// of the option or the argument that sets an unknown field or a value of the wrong type.
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	// DSL is a config DSL generated from the struct type T, create it by [config.New].
+	DSL[T any] struct {
+		name string
+		root *schema
+	}
+
+	// Error is an error in a config script, positioned at the option or the argument that is wrong.
+	Error struct {
+		Range gendsl.Range
+		Err   error
+	}
+
+	// schema is how a struct type is built.
+	schema struct {
+		typ    reflect.Type
+		fields map[string]*field
+		env    *gendsl.Env // procedures of the fields, evaluated inside the call of the struct
+	}
+
+	field struct {
+		key      string
+		index    []int
+		typ      reflect.Type
+		elem     *schema // schema of the struct that the field holds, nil if the field is not a struct
+		repeated bool    // whether the field is a slice that appends the values of the repeated calls
+	}
+
+	// assignment is the result of a call of a field.
+	assignment struct {
+		owner  *schema
+		field  *field
+		values []reflect.Value
+	}
+
+	// locatedError is an error that can be located in the call that returns it.
+	locatedError struct {
+		option string // the option that is wrong
+		arg    int    // the argument that is wrong, -1 if it is the whole call
+		err    error
+	}
+)
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("config error (line %v symbol %v - line %v symbol %v): %s",
+		e.Range.Begin.Line, e.Range.Begin.Symbol, e.Range.End.Line, e.Range.End.Symbol, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func (e *locatedError) Error() string { return e.err.Error() }
+
+// scalarStructs are the structs that are decoded as values instead of being built by calls.
+var scalarStructs = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):     true,
+	reflect.TypeOf(big.Int{}):       true,
+	reflect.TypeOf(big.Rat{}):       true,
+	reflect.TypeOf(regexp.Regexp{}): true,
+}
+
+// New generates the DSL of the struct type T, whose instance is built by the procedure `name`.
+// An error is returned if T is not a struct or two fields have the same name.
+func New[T any](name string) (*DSL[T], error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, errors.Errorf("cannot generate config DSL for %s, expecting a struct", typ)
+	}
+	root, err := newSchema(typ, make(map[reflect.Type]*schema))
+	if err != nil {
+		return nil, err
+	}
+	return &DSL[T]{name: name, root: root}, nil
+}
+
+// Env returns the env that holds the procedure of the root struct,
+// evaluating the procedure returns a UserData of *T.
+func (d *DSL[T]) Env() *gendsl.Env {
+	return gendsl.NewEnv().WithProcedure(d.name, d.root.procedure(d.name))
+}
+
+// Eval evaluates `script` and returns the root struct it builds.
+func (d *DSL[T]) Eval(script string) (*T, error) {
+	pc, err := gendsl.MakeParseContext(script)
+	if err != nil {
+		return nil, err
+	}
+	v, err := pc.Eval(gendsl.NewEvalCtx(nil, nil, d.Env()))
+	if err != nil {
+		return nil, locate(pc.Root(), err)
+	}
+	ret, err := gendsl.As[*T](v)
+	if err != nil {
+		return nil, &Error{Range: pc.Root().Range(), Err: errors.WithMessagef(err, "expecting a call of %s", d.name)}
+	}
+	return ret, nil
+}
+
+func newSchema(typ reflect.Type, schemas map[reflect.Type]*schema) (*schema, error) {
+	if s, ok := schemas[typ]; ok {
+		return s, nil
+	}
+	s := &schema{typ: typ, fields: make(map[string]*field)}
+	schemas[typ] = s
+
+	var collect func(typ reflect.Type, index []int) error
+	collect = func(typ reflect.Type, index []int) error {
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			tag, tagged := sf.Tag.Lookup("dsl")
+			key, _, _ := strings.Cut(tag, ",")
+			fieldIndex := append(append([]int{}, index...), i)
+			switch {
+			case key == "-":
+				continue
+			case sf.Anonymous && !tagged && sf.Type.Kind() == reflect.Struct:
+				if err := collect(sf.Type, fieldIndex); err != nil {
+					return err
+				}
+				continue
+			case !sf.IsExported():
+				continue
+			case key == "":
+				key = strings.ToLower(sf.Name)
+			}
+			if _, ok := s.fields[key]; ok {
+				return errors.Errorf("duplicated field %s in %s", key, s.typ)
+			}
+			f := &field{key: key, index: fieldIndex, typ: sf.Type}
+			elem := sf.Type
+			if elem.Kind() == reflect.Slice {
+				elem, f.repeated = elem.Elem(), true
+			}
+			if elem.Kind() == reflect.Pointer {
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Struct && !scalarStructs[elem] {
+				var err error
+				if f.elem, err = newSchema(elem, schemas); err != nil {
+					return err
+				}
+			}
+			s.fields[key] = f
+		}
+		return nil
+	}
+	if err := collect(typ, nil); err != nil {
+		return nil, err
+	}
+
+	s.env = gendsl.NewEnv()
+	for key, f := range s.fields {
+		s.env.WithProcedure(key, s.fieldProcedure(f))
+	}
+	return s, nil
+}
+
+// procedure returns the procedure that builds the struct, which returns a UserData of a pointer to the struct.
+func (s *schema) procedure(name string) gendsl.Procedure {
+	docs := make(map[string]string)
+	for key, f := range s.fields {
+		if f.elem == nil {
+			docs[key] = f.typ.String()
+		}
+	}
+	return gendsl.Procedure{
+		Eval: func(evalCtx *gendsl.EvalCtx, args []gendsl.Expr, options map[string]gendsl.Value) (gendsl.Value, error) {
+			out, err := s.build(args, options)
+			if err != nil {
+				return nil, err
+			}
+			return &gendsl.UserData{V: out.Interface()}, nil
+		},
+		Doc:     fmt.Sprintf("builds a %s, its fields are set by the options or the calls of the fields", s.typ),
+		Options: docs,
+	}
+}
+
+// build builds the struct with the options and the calls of the fields in `args`, it returns a pointer to the struct.
+func (s *schema) build(args []gendsl.Expr, options map[string]gendsl.Value) (reflect.Value, error) {
+	var (
+		out = reflect.New(s.typ)
+		set = make(map[*field]bool)
+	)
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f, ok := s.fields[name]
+		switch {
+		case !ok:
+			return out, &locatedError{option: name, err: errors.Errorf("unknown field %s of %s", name, s.typ)}
+		case f.elem != nil:
+			return out, &locatedError{option: name, err: errors.Errorf("field %s of %s should be set by (%s ...)", name, s.typ, name)}
+		}
+		if err := decode(options[name], f.of(out)); err != nil {
+			return out, &locatedError{option: name, err: errors.WithMessagef(err, "invalid field %s", name)}
+		}
+		set[f] = true
+	}
+
+	for i, arg := range args {
+		v, err := arg.EvalWithEnv(s.env)
+		if err != nil {
+			return out, locate(arg.Node(), err)
+		}
+		a, ok := v.Unwrap().(assignment)
+		if !ok || a.owner != s {
+			return out, &locatedError{arg: i, err: errors.Errorf("unexpected %s, expecting a call of the fields of %s", arg.Text(), s.typ)}
+		}
+		if set[a.field] && !a.field.repeated {
+			return out, &locatedError{arg: i, err: errors.Errorf("field %s is set more than once", a.field.key)}
+		}
+		set[a.field] = true
+		dst := a.field.of(out)
+		for _, v := range a.values {
+			if a.field.repeated {
+				dst.Set(reflect.Append(dst, v))
+			} else {
+				dst.Set(v)
+			}
+		}
+	}
+	return out, nil
+}
+
+// fieldProcedure returns the procedure that sets the field `f`.
+// A struct field is built by the options and the calls in it like the root struct,
+// and the other fields are decoded from the arguments, a slice accepts any number of them and the others accept exactly one.
+func (s *schema) fieldProcedure(f *field) gendsl.Procedure {
+	elemType := f.typ
+	if f.repeated {
+		elemType = elemType.Elem()
+	}
+	if f.elem != nil {
+		p := f.elem.procedure(f.key)
+		p.Eval = func(_ *gendsl.EvalCtx, args []gendsl.Expr, options map[string]gendsl.Value) (gendsl.Value, error) {
+			out, err := f.elem.build(args, options)
+			if err != nil {
+				return nil, err
+			}
+			if elemType.Kind() != reflect.Pointer {
+				out = out.Elem()
+			}
+			return &gendsl.UserData{V: assignment{owner: s, field: f, values: []reflect.Value{out}}}, nil
+		}
+		return p
+	}
+
+	return gendsl.Procedure{
+		Eval: func(_ *gendsl.EvalCtx, args []gendsl.Expr, options map[string]gendsl.Value) (gendsl.Value, error) {
+			for name := range options {
+				return nil, &locatedError{option: name, err: errors.Errorf("field %s accepts no option", f.key)}
+			}
+			if !f.repeated && len(args) != 1 {
+				return nil, &locatedError{arg: -1, err: errors.Errorf("field %s expects 1 argument, but got %d", f.key, len(args))}
+			}
+			values := make([]reflect.Value, 0, len(args))
+			for i, arg := range args {
+				v, err := arg.Eval()
+				if err != nil {
+					return nil, err
+				}
+				dst := reflect.New(elemType).Elem()
+				if err := decode(v, dst); err != nil {
+					return nil, &locatedError{arg: i, err: errors.WithMessagef(err, "invalid field %s", f.key)}
+				}
+				values = append(values, dst)
+			}
+			return &gendsl.UserData{V: assignment{owner: s, field: f, values: values}}, nil
+		},
+		Doc: fmt.Sprintf("sets the field %s of %s", f.key, s.typ),
+	}
+}
+
+// of returns the field of the struct that `out` points to.
+func (f *field) of(out reflect.Value) reflect.Value {
+	return out.Elem().FieldByIndex(f.index)
+}
+
+func decode(v gendsl.Value, dst reflect.Value) error {
+	err := gendsl.Decode(v, dst.Addr().Interface())
+	var de *gendsl.DecodeError
+	if errors.As(err, &de) && de.Path == "" {
+		return de.Err
+	}
+	return err
+}
+
+// locate positions the error returned by the call `n`.
+func locate(n gendsl.Node, err error) error {
+	le, ok := err.(*locatedError)
+	if !ok {
+		return err
+	}
+	if n.IsZero() { // a value spliced
+		return le.err
+	}
+	r := n.Range()
+	switch {
+	case le.option != "":
+		for _, opt := range n.Options() {
+			if opt.Name == le.option {
+				r = opt.Range
+			}
+		}
+	case le.arg >= 0:
+		if args := n.Args(); le.arg < len(args) {
+			r = args[le.arg].Range()
+		}
+	}
+	return &Error{Range: r, Err: le.err}
+}
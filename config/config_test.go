@@ -0,0 +1,129 @@
+package config
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	route struct {
+		Path    string `dsl:"path"`
+		Backend string `dsl:"backend"`
+	}
+
+	tls struct {
+		Cert string `dsl:"cert"`
+		Key  string `dsl:"key"`
+	}
+
+	listen struct {
+		Host string `dsl:"host"`
+		Port int    `dsl:"port"`
+	}
+
+	server struct {
+		listen
+		Name    string
+		Timeout time.Duration `dsl:"timeout"`
+		Tags    []string      `dsl:"tag"`
+		Routes  []route       `dsl:"route"`
+		TLS     *tls          `dsl:"tls"`
+		Backup  *server       `dsl:"backup"`
+		Secret  string        `dsl:"-"`
+	}
+)
+
+var _ = Describe("DSL", func() {
+	var dsl *DSL[server]
+	BeforeEach(func() {
+		var err error
+		dsl, err = New[server]("server")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("builds the struct by the options and the nested calls", func() {
+		srv, err := dsl.Eval(`
+(server #:host "localhost" #:name "main"
+  (port 8080)
+  (timeout 1m30s)
+  (tag "a" "b")
+  (tag "c")
+  (route #:path "/" #:backend "web")
+  (route #:path "/api" (backend "api"))
+  (tls #:cert "a.crt" #:key "a.key")
+  (backup #:port 8081))`)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(*srv).Should(Equal(server{
+			listen:  listen{Host: "localhost", Port: 8080},
+			Name:    "main",
+			Timeout: 90 * time.Second,
+			Tags:    []string{"a", "b", "c"},
+			Routes:  []route{{Path: "/", Backend: "web"}, {Path: "/api", Backend: "api"}},
+			TLS:     &tls{Cert: "a.crt", Key: "a.key"},
+			Backup:  &server{listen: listen{Port: 8081}},
+		}))
+
+		v, err := gendsl.EvalExpr(`(server (port 1))`, dsl.Env())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v.Unwrap()).Should(Equal(&server{listen: listen{Port: 1}}))
+	})
+
+	It("reports the positions of the wrong fields", func() {
+		for script, want := range map[string]struct {
+			line, sym int
+			msg       string
+		}{
+			`(server #:hots "a")`:                    {1, 9, "unknown field hots of config.server"},
+			"(server\n  (route #:path 1))":           {2, 10, "invalid field path: expecting string|symbol|keyword, but got int"},
+			"(server\n  (port \"80\"))":              {2, 9, "invalid field port: expecting int|uint|bigint, but got string"},
+			"(server (port 1 2))":                    {1, 9, "field port expects 1 argument, but got 2"},
+			"(server (port 1) (port 2))":             {1, 18, "field port is set more than once"},
+			"(server #:port 1 (port 2))":             {1, 18, "field port is set more than once"},
+			"(server #:tls 1)":                       {1, 9, "field tls of config.server should be set by (tls ...)"},
+			"(server (tls #:cert \"a\" 1))":          {1, 25, "unexpected 1, expecting a call of the fields of config.tls"},
+			"(server (tls (port 1)))":                {1, 14, "unexpected (port 1), expecting a call of the fields of config.tls"},
+			"(server (tag #:x 1))":                   {1, 14, "field tag accepts no option"},
+			"(server (backup (backup #:timeout 1)))": {1, 25, "invalid field timeout: expecting duration, but got int"},
+		} {
+			_, err := dsl.Eval(script)
+			var ce *Error
+			Expect(errors.As(err, &ce)).Should(BeTrue(), script)
+			Expect(ce.Range.Begin.Line).Should(Equal(want.line), script)
+			Expect(ce.Range.Begin.Symbol).Should(Equal(want.sym), script)
+			Expect(ce.Err).Should(MatchError(want.msg), script)
+		}
+
+		_, err := dsl.Eval(`(server (rout #:path "/"))`)
+		var ee *gendsl.EvaluateError
+		Expect(errors.As(err, &ee)).Should(BeTrue())
+		Expect(ee.BeginSym).Should(Equal(10))
+		Expect(err).Should(MatchError(ContainSubstring("unsupported operator rout")))
+		_, err = dsl.Eval(`"server"`)
+		Expect(err).Should(MatchError(ContainSubstring("expecting a call of server")))
+	})
+
+	It("rejects the types that cannot be built", func() {
+		_, err := New[int]("x")
+		Expect(err).Should(HaveOccurred())
+		_, err = New[struct {
+			A int `dsl:"a"`
+			B int `dsl:"a"`
+		}]("x")
+		Expect(err).Should(MatchError(ContainSubstring("duplicated field a")))
+	})
+
+	It("documents the procedures", func() {
+		v, ok := dsl.Env().Lookup("server")
+		Expect(ok).Should(BeTrue())
+		p := v.(gendsl.Procedure)
+		Expect(p.Doc).Should(ContainSubstring("config.server"))
+		Expect(p.Options).Should(HaveKeyWithValue("port", "int"))
+		Expect(p.Options).ShouldNot(HaveKey("route"))
+		Expect(p.Options).ShouldNot(HaveKey("secret"))
+	})
+})
@@ -0,0 +1,228 @@
+package gendsl
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// TypeError is returned when a Value cannot be used as the type expected.
+type TypeError struct {
+	Actual   ValueType // type of the value
+	Expected ValueType // type expected, a union if more than one type is accepted
+	// Reason tells why a value of the right type cannot be converted, like an overflow, empty if the type is wrong.
+	Reason string
+}
+
+func (e *TypeError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("cannot convert %s to %s: %s", e.Actual, e.Expected, e.Reason)
+	}
+	return fmt.Sprintf("expecting %s, but got %s", e.Expected, e.Actual)
+}
+
+// goTypes maps the Go types that Values unwrap into to the ValueTypes.
+var goTypes = map[reflect.Type]ValueType{
+	reflect.TypeOf(int64(0)):              ValueTypeInt,
+	reflect.TypeOf(uint64(0)):             ValueTypeUInt,
+	reflect.TypeOf(""):                    ValueTypeString,
+	reflect.TypeOf(false):                 ValueTypeBool,
+	reflect.TypeOf(float64(0)):            ValueTypeFloat,
+	reflect.TypeOf(ProcedureFn(nil)):      ValueTypeProcedure,
+	reflect.TypeOf(Node{}):                ValueTypeCode,
+	reflect.TypeOf((*big.Int)(nil)):       ValueTypeBigInt,
+	reflect.TypeOf((*big.Rat)(nil)):       ValueTypeDecimal,
+	reflect.TypeOf(time.Time{}):           ValueTypeTime,
+	reflect.TypeOf(time.Duration(0)):      ValueTypeDuration,
+	reflect.TypeOf([]byte(nil)):           ValueTypeBytes,
+	reflect.TypeOf((*regexp.Regexp)(nil)): ValueTypeRegexp,
+}
+
+// As returns `v` as T, T can be a type of Value like String, or a Go type that `v` unwraps into like string.
+// No conversion is done, use ToInt, ToUint, ToFloat or ToString to convert the values. For example:
+//
+//	re, err := gendsl.As[gendsl.Regexp](v)
+//	s, err := gendsl.As[string](v)         // the same as gendsl.As[gendsl.String] but returns a string
+//	kv, err := gendsl.As[KV](v)            // the V of a UserData
+//
+// A [gendsl.TypeError] is returned if `v` is not a T.
+func As[T any](v Value) (T, error) {
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	if v != nil {
+		if t, ok := v.Unwrap().(T); ok {
+			return t, nil
+		}
+	}
+	var zero T
+	return zero, &TypeError{Actual: typeOf(v), Expected: expectedType[T]()}
+}
+
+// expectedType returns the ValueType of T, a Go type that is not mapped by any Value is held by a UserData.
+func expectedType[T any]() ValueType {
+	var zero T
+	if v, ok := any(zero).(Value); ok {
+		return v.Type()
+	}
+	if t, ok := goTypes[reflect.TypeOf((*T)(nil)).Elem()]; ok {
+		return t
+	}
+	return ValueTypeUserData
+}
+
+func typeOf(v Value) ValueType {
+	if v == nil {
+		return ValueTypeNil
+	}
+	return v.Type()
+}
+
+// ToInt converts a number to Int:
+//   - Int is returned as it is.
+//   - Uint, BigInt are converted if they fit in int64.
+//   - Float and Decimal are converted if they are integers that fit in int64, a fraction is never truncated.
+//
+// A [gendsl.TypeError] is returned if `v` is not a number or it cannot be converted.
+func ToInt(v Value) (Int, error) {
+	switch x := v.(type) {
+	case Int:
+		return x, nil
+	case Uint:
+		if x > math.MaxInt64 {
+			return 0, &TypeError{Actual: ValueTypeUInt, Expected: ValueTypeInt, Reason: fmt.Sprintf("%d overflows int64", x)}
+		}
+		return Int(x), nil
+	case Float:
+		f := float64(x)
+		if f != math.Trunc(f) || math.IsInf(f, 0) {
+			return 0, &TypeError{Actual: ValueTypeFloat, Expected: ValueTypeInt, Reason: fmt.Sprintf("%v is not an integer", f)}
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 { // float64(math.MaxInt64) is 2^63
+			return 0, &TypeError{Actual: ValueTypeFloat, Expected: ValueTypeInt, Reason: fmt.Sprintf("%v overflows int64", f)}
+		}
+		return Int(f), nil
+	case BigInt, Decimal:
+		i, err := bigInteger(x, ValueTypeInt)
+		if err != nil {
+			return 0, err
+		}
+		if !i.IsInt64() {
+			return 0, &TypeError{Actual: x.Type(), Expected: ValueTypeInt, Reason: fmt.Sprintf("%s overflows int64", i)}
+		}
+		return Int(i.Int64()), nil
+	}
+	return 0, &TypeError{Actual: typeOf(v), Expected: numberTypes}
+}
+
+// ToUint converts a number to Uint as ToInt does, an error is returned if the number is negative.
+func ToUint(v Value) (Uint, error) {
+	switch x := v.(type) {
+	case Uint:
+		return x, nil
+	case Int:
+		if x < 0 {
+			return 0, &TypeError{Actual: ValueTypeInt, Expected: ValueTypeUInt, Reason: fmt.Sprintf("%d is negative", x)}
+		}
+		return Uint(x), nil
+	case Float:
+		f := float64(x)
+		if f != math.Trunc(f) || math.IsInf(f, 0) {
+			return 0, &TypeError{Actual: ValueTypeFloat, Expected: ValueTypeUInt, Reason: fmt.Sprintf("%v is not an integer", f)}
+		}
+		if f < 0 || f >= math.MaxUint64 { // float64(math.MaxUint64) is 2^64
+			return 0, &TypeError{Actual: ValueTypeFloat, Expected: ValueTypeUInt, Reason: fmt.Sprintf("%v overflows uint64", f)}
+		}
+		return Uint(f), nil
+	case BigInt, Decimal:
+		i, err := bigInteger(x, ValueTypeUInt)
+		if err != nil {
+			return 0, err
+		}
+		if !i.IsUint64() {
+			return 0, &TypeError{Actual: x.Type(), Expected: ValueTypeUInt, Reason: fmt.Sprintf("%s overflows uint64", i)}
+		}
+		return Uint(i.Uint64()), nil
+	}
+	return 0, &TypeError{Actual: typeOf(v), Expected: numberTypes}
+}
+
+// ToFloat converts a number to Float:
+//   - Float is returned as it is.
+//   - Int, Uint, BigInt and Decimal are converted to the nearest float64, an error is returned if it overflows float64.
+//
+// A [gendsl.TypeError] is returned if `v` is not a number.
+func ToFloat(v Value) (Float, error) {
+	var f float64
+	switch x := v.(type) {
+	case Float:
+		return x, nil
+	case Int:
+		return Float(x), nil
+	case Uint:
+		return Float(x), nil
+	case BigInt:
+		f, _ = new(big.Float).SetInt(x.int()).Float64()
+	case Decimal:
+		f, _ = x.Rat().Float64()
+	default:
+		return 0, &TypeError{Actual: typeOf(v), Expected: numberTypes}
+	}
+	if math.IsInf(f, 0) {
+		return 0, &TypeError{Actual: v.Type(), Expected: ValueTypeFloat, Reason: "overflows float64"}
+	}
+	return Float(f), nil
+}
+
+// ToString converts a textual value to String:
+//   - String is returned as it is.
+//   - Symbol and Keyword are converted to their names.
+//   - Bytes are converted if they are valid UTF-8.
+//
+// The other values are not converted, use [gendsl.Repr] to print them.
+// A [gendsl.TypeError] is returned if `v` cannot be converted.
+func ToString(v Value) (String, error) {
+	switch x := v.(type) {
+	case String:
+		return x, nil
+	case Symbol:
+		return String(x.Name()), nil
+	case Keyword:
+		return String(x.Name()), nil
+	case Bytes:
+		if !utf8.Valid(x) {
+			return "", &TypeError{Actual: ValueTypeBytes, Expected: ValueTypeString, Reason: "invalid UTF-8"}
+		}
+		return String(x), nil
+	}
+	return "", &TypeError{Actual: typeOf(v), Expected: ValueTypeString | ValueTypeSymbol | ValueTypeKeyword | ValueTypeBytes}
+}
+
+// Truthy reports whether `v` is considered true as a condition, Nil and Bool(false) are false and anything else is true.
+func Truthy(v Value) bool {
+	switch x := v.(type) {
+	case nil, Nil:
+		return false
+	case Bool:
+		return bool(x)
+	}
+	return true
+}
+
+const numberTypes = ValueTypeInt | ValueTypeUInt | ValueTypeFloat | ValueTypeBigInt | ValueTypeDecimal
+
+// bigInteger returns the integer of a BigInt or a Decimal without a fraction.
+func bigInteger(v Value, expected ValueType) (*big.Int, error) {
+	if d, ok := v.(Decimal); ok {
+		r := d.Rat()
+		if !r.IsInt() {
+			return nil, &TypeError{Actual: ValueTypeDecimal, Expected: expected, Reason: fmt.Sprintf("%s is not an integer", d)}
+		}
+		return r.Num(), nil
+	}
+	return toBigInt(v), nil
+}
@@ -0,0 +1,122 @@
+package gendsl
+
+import (
+	"math"
+	"math/big"
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Convert", func() {
+	It("gets the values as their types", func() {
+		s, err := As[String](String("foo"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(s).Should(gomega.Equal(String("foo")))
+		str, err := As[string](String("foo"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(str).Should(gomega.Equal("foo"))
+		m, err := As[Map](&UserData{V: Map{"a": Int(1)}})
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(m).Should(gomega.HaveKey("a"))
+		re, err := As[*regexp.Regexp](NewRegexp(regexp.MustCompile("a")))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(re.String()).Should(gomega.Equal("a"))
+
+		var te *TypeError
+		_, err = As[string](Int(1))
+		gomega.Expect(errors.As(err, &te)).Should(gomega.BeTrue())
+		gomega.Expect(te.Actual).Should(gomega.Equal(ValueType(ValueTypeInt)))
+		gomega.Expect(te.Expected).Should(gomega.Equal(ValueType(ValueTypeString)))
+		gomega.Expect(err.Error()).Should(gomega.Equal("expecting string, but got int"))
+		_, err = As[Regexp](nil)
+		gomega.Expect(errors.As(err, &te)).Should(gomega.BeTrue())
+		gomega.Expect(te.Actual).Should(gomega.Equal(ValueType(ValueTypeNil)))
+		gomega.Expect(te.Expected).Should(gomega.Equal(ValueType(ValueTypeRegexp)))
+		_, err = As[Map](Int(1))
+		gomega.Expect(errors.As(err, &te)).Should(gomega.BeTrue())
+		gomega.Expect(te.Expected).Should(gomega.Equal(ValueType(ValueTypeUserData)))
+	})
+
+	It("converts the numbers to integers", func() {
+		for v, want := range map[Value]Int{
+			Int(-1):             -1,
+			Uint(math.MaxInt64): math.MaxInt64,
+			Float(-3):           -3,
+			Float(-(1 << 63)):   math.MinInt64,
+			bigInt("-12"):       -12,
+			decimal("12.00"):    12,
+		} {
+			got, err := ToInt(v)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), Repr(v))
+			gomega.Expect(got).Should(gomega.Equal(want), Repr(v))
+		}
+		for v, reason := range map[Value]string{
+			Uint(math.MaxInt64 + 1):       "overflows int64",
+			Float(1 << 63):                "overflows int64",
+			Float(1.5):                    "is not an integer",
+			Float(math.NaN()):             "is not an integer",
+			Float(math.Inf(1)):            "is not an integer",
+			bigInt("9223372036854775808"): "overflows int64",
+			decimal("1.5"):                "is not an integer",
+			String("1"):                   "expecting int|uint|float|bigint|decimal, but got string",
+		} {
+			_, err := ToInt(v)
+			gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring(reason)), Repr(v))
+		}
+
+		u, err := ToUint(Float(1 << 63))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(u).Should(gomega.Equal(Uint(1 << 63)))
+		u, err = ToUint(bigInt("18446744073709551615"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(u).Should(gomega.Equal(Uint(math.MaxUint64)))
+		for _, v := range []Value{Int(-1), Float(-1), Float(1 << 64), bigInt("-1"), decimal("0.5")} {
+			_, err := ToUint(v)
+			var te *TypeError
+			gomega.Expect(errors.As(err, &te)).Should(gomega.BeTrue(), Repr(v))
+			gomega.Expect(te.Expected).Should(gomega.Equal(ValueType(ValueTypeUInt)), Repr(v))
+		}
+	})
+
+	It("converts the numbers to floats", func() {
+		for v, want := range map[Value]Float{
+			Float(1.5):      1.5,
+			Int(-2):         -2,
+			Uint(3):         3,
+			bigInt("1000"):  1000,
+			decimal("0.25"): 0.25,
+		} {
+			got, err := ToFloat(v)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), Repr(v))
+			gomega.Expect(got).Should(gomega.Equal(want), Repr(v))
+		}
+		_, err := ToFloat(NewBigInt(new(big.Int).Lsh(big.NewInt(1), 1024)))
+		gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("overflows float64")))
+		_, err = ToFloat(Bool(true))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+
+	It("converts the texts to strings", func() {
+		for _, v := range []Value{String("a"), NewSymbol("a"), NewKeyword("a"), Bytes("a")} {
+			got, err := ToString(v)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), Repr(v))
+			gomega.Expect(got).Should(gomega.Equal(String("a")), Repr(v))
+		}
+		_, err := ToString(Bytes{0xff})
+		gomega.Expect(err).Should(gomega.MatchError("cannot convert bytes to string: invalid UTF-8"))
+		_, err = ToString(Int(1))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+
+	It("tells the truthy values", func() {
+		for _, v := range []Value{Nil{}, Bool(false), nil} {
+			gomega.Expect(Truthy(v)).Should(gomega.BeFalse())
+		}
+		for _, v := range []Value{Bool(true), Int(0), String(""), &UserData{}} {
+			gomega.Expect(Truthy(v)).Should(gomega.BeTrue())
+		}
+	})
+})
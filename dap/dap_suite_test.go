@@ -0,0 +1,13 @@
+package dap
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDAP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DAP Suite")
+}
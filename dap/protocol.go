@@ -0,0 +1,144 @@
+package dap
+
+import "encoding/json"
+
+// The subset of the Debug Adapter Protocol used by the server,
+// see https://microsoft.github.io/debug-adapter-protocol/specification
+
+type (
+	message struct {
+		Seq        int             `json:"seq"`
+		Type       string          `json:"type"` // "request", "response" or "event"
+		Command    string          `json:"command,omitempty"`
+		Arguments  json.RawMessage `json:"arguments,omitempty"`
+		RequestSeq int             `json:"request_seq,omitempty"`
+		Success    bool            `json:"success,omitempty"`
+		Message    string          `json:"message,omitempty"`
+		Event      string          `json:"event,omitempty"`
+		Body       json.RawMessage `json:"body,omitempty"`
+	}
+
+	Capabilities struct {
+		SupportsConfigurationDoneRequest bool `json:"supportsConfigurationDoneRequest"`
+		SupportsFunctionBreakpoints      bool `json:"supportsFunctionBreakpoints"`
+		SupportsTerminateRequest         bool `json:"supportsTerminateRequest"`
+	}
+
+	LaunchArguments struct {
+		Program     string `json:"program"` // path of the script
+		StopOnEntry bool   `json:"stopOnEntry"`
+		NoDebug     bool   `json:"noDebug"`
+	}
+
+	Source struct {
+		Name string `json:"name,omitempty"`
+		Path string `json:"path,omitempty"`
+	}
+
+	SourceBreakpoint struct {
+		Line   int `json:"line"`
+		Column int `json:"column,omitempty"`
+	}
+
+	SetBreakpointsArguments struct {
+		Source      Source             `json:"source"`
+		Breakpoints []SourceBreakpoint `json:"breakpoints"`
+	}
+
+	FunctionBreakpoint struct {
+		Name string `json:"name"`
+	}
+
+	SetFunctionBreakpointsArguments struct {
+		Breakpoints []FunctionBreakpoint `json:"breakpoints"`
+	}
+
+	Breakpoint struct {
+		ID       int    `json:"id"`
+		Verified bool   `json:"verified"`
+		Message  string `json:"message,omitempty"`
+		Line     int    `json:"line,omitempty"`
+		Column   int    `json:"column,omitempty"`
+	}
+
+	SetBreakpointsResponse struct {
+		Breakpoints []Breakpoint `json:"breakpoints"`
+	}
+
+	Thread struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	ThreadsResponse struct {
+		Threads []Thread `json:"threads"`
+	}
+
+	StackTraceArguments struct {
+		ThreadID int `json:"threadId"`
+	}
+
+	StackFrame struct {
+		ID        int    `json:"id"`
+		Name      string `json:"name"`
+		Source    Source `json:"source"`
+		Line      int    `json:"line"`
+		Column    int    `json:"column"`
+		EndLine   int    `json:"endLine"`
+		EndColumn int    `json:"endColumn"`
+	}
+
+	StackTraceResponse struct {
+		StackFrames []StackFrame `json:"stackFrames"`
+		TotalFrames int          `json:"totalFrames"`
+	}
+
+	ScopesArguments struct {
+		FrameID int `json:"frameId"`
+	}
+
+	Scope struct {
+		Name               string `json:"name"`
+		VariablesReference int    `json:"variablesReference"`
+		Expensive          bool   `json:"expensive"`
+	}
+
+	ScopesResponse struct {
+		Scopes []Scope `json:"scopes"`
+	}
+
+	VariablesArguments struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+
+	Variable struct {
+		Name               string `json:"name"`
+		Value              string `json:"value"`
+		Type               string `json:"type,omitempty"`
+		VariablesReference int    `json:"variablesReference"`
+	}
+
+	VariablesResponse struct {
+		Variables []Variable `json:"variables"`
+	}
+
+	ContinueResponse struct {
+		AllThreadsContinued bool `json:"allThreadsContinued"`
+	}
+
+	StoppedEvent struct {
+		Reason            string `json:"reason"`
+		ThreadID          int    `json:"threadId"`
+		AllThreadsStopped bool   `json:"allThreadsStopped"`
+		HitBreakpointIDs  []int  `json:"hitBreakpointIds,omitempty"`
+	}
+
+	OutputEvent struct {
+		Category string `json:"category"`
+		Output   string `json:"output"`
+	}
+
+	ExitedEvent struct {
+		ExitCode int `json:"exitCode"`
+	}
+)
@@ -0,0 +1,491 @@
+// Package dap implements a Debug Adapter Protocol server for DSLs built with gendsl.
+//
+// The server speaks DAP over a pair of streams(the stdio or a TCP connection) and debugs one script per session
+// with [debug.Debugger], it supports:
+//   - breakpoints on source lines and procedure names
+//   - stack frames of the nested procedure calls being evaluated
+//   - variables of the scopes of each frame, and the options and arguments of the pending call
+//   - continue, next, step in, step out and pause
+//
+// The host program supplies the procedures of the DSL by [dap.Config]:
+//
+//	server := dap.NewServer(dap.Config{Env: env})
+//	err := server.Serve(os.Stdin, os.Stdout)
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/ccbhj/gendsl"
+	"github.com/ccbhj/gendsl/debug"
+	"github.com/ccbhj/gendsl/internal/wire"
+)
+
+// threadID is the only thread, since a script is evaluated in a single goroutine.
+const threadID = 1
+
+type (
+	// Config specifies how the scripts are evaluated.
+	Config struct {
+		Env      *gendsl.Env // env that the scripts are evaluated with
+		UserData any         // passed to [gendsl.NewEvalCtx]
+	}
+
+	// Server is a debug adapter, create it by [dap.NewServer].
+	Server struct {
+		cfg      Config
+		debugger *debug.Debugger
+
+		wmu sync.Mutex // guards the writing
+		out io.Writer
+		seq int
+
+		// used by the reading loop only
+		launch     LaunchArguments
+		pc         *gendsl.ParseContext
+		lineBPs    []int  // IDs of the breakpoints on lines
+		funcBPs    []int  // IDs of the breakpoints on procedures
+		after      func() // called after the response is sent
+		disconnect bool
+
+		mu       sync.Mutex // guards the fields below, which are shared with the goroutine watching the session
+		session  *debug.Session
+		stop     *debug.Stop // nil if the script is running
+		entry    bool        // whether the next stop is the entry
+		aborting bool
+		vars     map[int][]Variable // variables by references, valid until the script is resumed
+		finished chan struct{}
+	}
+)
+
+// NewServer creates a debug adapter with `cfg`.
+func NewServer(cfg Config) *Server {
+	if cfg.Env == nil {
+		cfg.Env = gendsl.NewEnv()
+	}
+	return &Server{
+		cfg:      cfg,
+		debugger: debug.New(),
+	}
+}
+
+// Serve reads requests from `r` and writes responses and events to `w`,
+// until the client sends the disconnect request or `r` is closed.
+// The script being debugged is aborted when it returns.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.out = w
+	defer s.abort()
+	br := bufio.NewReader(r)
+	for {
+		body, err := wire.ReadMessage(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return errors.WithMessage(err, "invalid message")
+		}
+		if msg.Type != "request" {
+			continue
+		}
+
+		result, herr := s.handle(&msg)
+		if err := s.reply(&msg, result, herr); err != nil {
+			return err
+		}
+		if s.after != nil {
+			s.after()
+			s.after = nil
+		}
+		if s.disconnect {
+			return nil
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) (any, error) {
+	switch msg.Command {
+	case "initialize":
+		s.after = func() { _ = s.event("initialized", nil) }
+		return Capabilities{
+			SupportsConfigurationDoneRequest: true,
+			SupportsFunctionBreakpoints:      true,
+			SupportsTerminateRequest:         true,
+		}, nil
+	case "launch":
+		if err := json.Unmarshal(msg.Arguments, &s.launch); err != nil {
+			return nil, err
+		}
+		return nil, s.load(s.launch.Program)
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.setBreakpoints(args)
+	case "setFunctionBreakpoints":
+		var args SetFunctionBreakpointsArguments
+		if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.setFunctionBreakpoints(args)
+	case "configurationDone":
+		if s.pc == nil {
+			return nil, errors.New("no script launched")
+		}
+		s.after = s.start
+		return nil, nil
+	case "threads":
+		return ThreadsResponse{Threads: []Thread{{ID: threadID, Name: "main"}}}, nil
+	case "stackTrace":
+		return s.stackTrace()
+	case "scopes":
+		var args ScopesArguments
+		if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.scopes(args)
+	case "variables":
+		var args VariablesArguments
+		if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.variables(args), nil
+	case "continue":
+		return ContinueResponse{AllThreadsContinued: true}, s.resume(debug.Continue)
+	case "next":
+		return nil, s.resume(debug.StepOver)
+	case "stepIn":
+		return nil, s.resume(debug.StepInto)
+	case "stepOut":
+		return nil, s.resume(debug.StepOut)
+	case "pause":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.session != nil {
+			s.session.Pause()
+		}
+		return nil, nil
+	case "terminate":
+		s.abort()
+		return nil, nil
+	case "disconnect":
+		s.abort()
+		s.disconnect = true
+		return nil, nil
+	}
+	return nil, errors.Errorf("unsupported command %q", msg.Command)
+}
+
+func (s *Server) reply(req *message, body any, herr error) error {
+	resp := message{
+		Type:       "response",
+		Command:    req.Command,
+		RequestSeq: req.Seq,
+		Success:    herr == nil,
+	}
+	if herr != nil {
+		resp.Message = herr.Error()
+	} else if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		resp.Body = bs
+	}
+	return s.send(resp)
+}
+
+func (s *Server) event(name string, body any) error {
+	msg := message{Type: "event", Event: name}
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		msg.Body = bs
+	}
+	return s.send(msg)
+}
+
+func (s *Server) send(msg message) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	s.seq++
+	msg.Seq = s.seq
+	bs, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return wire.WriteMessage(s.out, bs)
+}
+
+func (s *Server) load(program string) error {
+	if program == "" {
+		return errors.New("program is not specified")
+	}
+	bs, err := os.ReadFile(program)
+	if err != nil {
+		return err
+	}
+	pc, err := gendsl.MakeParseContext(string(bs))
+	if err != nil {
+		return err
+	}
+	s.pc = pc
+	return nil
+}
+
+// source returns the source of the script launched.
+func (s *Server) source() Source {
+	return Source{Name: filepath.Base(s.launch.Program), Path: s.launch.Program}
+}
+
+func (s *Server) setBreakpoints(args SetBreakpointsArguments) (SetBreakpointsResponse, error) {
+	for _, id := range s.lineBPs {
+		s.debugger.ClearBreakpoint(id)
+	}
+	s.lineBPs = s.lineBPs[:0]
+
+	// only the lines where an expression begins can be stopped at
+	lines := make(map[int]bool)
+	if s.pc != nil {
+		gendsl.Inspect(s.pc.Root(), func(n gendsl.Node) bool {
+			if n.Type() == gendsl.ExprTypeExpr {
+				lines[n.Range().Begin.Line] = true
+			}
+			return true
+		})
+	}
+
+	ret := SetBreakpointsResponse{Breakpoints: make([]Breakpoint, 0, len(args.Breakpoints))}
+	for _, sbp := range args.Breakpoints {
+		bp, err := s.debugger.SetBreakpoint(debug.Breakpoint{Line: sbp.Line, Column: sbp.Column})
+		if err != nil {
+			return ret, err
+		}
+		s.lineBPs = append(s.lineBPs, bp.ID)
+		verified := s.pc == nil || lines[sbp.Line]
+		out := Breakpoint{ID: bp.ID, Verified: verified, Line: sbp.Line, Column: sbp.Column}
+		if !verified {
+			out.Message = "no expression begins at this line"
+		}
+		ret.Breakpoints = append(ret.Breakpoints, out)
+	}
+	return ret, nil
+}
+
+func (s *Server) setFunctionBreakpoints(args SetFunctionBreakpointsArguments) (SetBreakpointsResponse, error) {
+	for _, id := range s.funcBPs {
+		s.debugger.ClearBreakpoint(id)
+	}
+	s.funcBPs = s.funcBPs[:0]
+
+	ret := SetBreakpointsResponse{Breakpoints: make([]Breakpoint, 0, len(args.Breakpoints))}
+	for _, fbp := range args.Breakpoints {
+		bp, err := s.debugger.SetBreakpoint(debug.Breakpoint{Procedure: fbp.Name})
+		if err != nil {
+			return ret, err
+		}
+		s.funcBPs = append(s.funcBPs, bp.ID)
+		_, verified := s.cfg.Env.Lookup(fbp.Name)
+		ret.Breakpoints = append(ret.Breakpoints, Breakpoint{ID: bp.ID, Verified: verified})
+	}
+	return ret, nil
+}
+
+// start evaluates the script launched in background.
+func (s *Server) start() {
+	d := s.debugger
+	if s.launch.NoDebug {
+		d = debug.New()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = d.Start(s.pc, gendsl.NewEvalCtx(nil, s.cfg.UserData, s.cfg.Env))
+	s.finished = make(chan struct{})
+	if s.launch.StopOnEntry && !s.launch.NoDebug {
+		s.entry = true
+		s.session.Pause()
+	}
+	go s.watch(s.session)
+}
+
+// watch reports the stops and the result of the session.
+func (s *Server) watch(session *debug.Session) {
+	for stop, ok := session.Next(); ok; stop, ok = session.Next() {
+		s.mu.Lock()
+		if s.aborting {
+			s.mu.Unlock()
+			session.Resume(debug.Abort)
+			continue
+		}
+		s.stop, s.vars = &stop, make(map[int][]Variable)
+		ev := StoppedEvent{Reason: stop.Reason.String(), ThreadID: threadID, AllThreadsStopped: true}
+		if s.entry {
+			ev.Reason, s.entry = "entry", false
+		}
+		if stop.Reason == debug.ReasonBreakpoint {
+			ev.HitBreakpointIDs = []int{stop.Breakpoint.ID}
+		}
+		s.mu.Unlock()
+		_ = s.event("stopped", ev)
+	}
+
+	v, err := session.Wait()
+	exitCode := 0
+	if err != nil {
+		exitCode = 1
+		_ = s.event("output", OutputEvent{Category: "stderr", Output: "error: " + err.Error() + "\n"})
+	} else if v != nil {
+		_ = s.event("output", OutputEvent{Category: "console", Output: gendsl.Repr(v) + "\n"})
+	}
+	_ = s.event("exited", ExitedEvent{ExitCode: exitCode})
+	_ = s.event("terminated", nil)
+	close(s.finished)
+}
+
+// resume resumes the stopped script with `action` after the response is sent.
+func (s *Server) resume(action debug.Action) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return errors.New("script is not stopped")
+	}
+	s.stop, s.vars = nil, nil
+	session := s.session
+	s.after = func() { session.Resume(action) }
+	return nil
+}
+
+// abort aborts the script being debugged and waits for it to finish.
+func (s *Server) abort() {
+	s.mu.Lock()
+	session, finished := s.session, s.finished
+	if session == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.aborting = true
+	stopped := s.stop != nil
+	s.stop = nil
+	s.mu.Unlock()
+
+	if stopped {
+		session.Resume(debug.Abort)
+	} else {
+		session.Pause()
+	}
+	<-finished
+}
+
+func (s *Server) stackTrace() (StackTraceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return StackTraceResponse{}, errors.New("script is not stopped")
+	}
+	frames := make([]StackFrame, 0, len(s.stop.Frames))
+	for i, f := range s.stop.Frames {
+		begin := f.Node.Range().Begin
+		endLine, endColumn := end(begin, f.Node.Text())
+		frames = append(frames, StackFrame{
+			ID:        i + 1,
+			Name:      f.Name,
+			Source:    s.source(),
+			Line:      begin.Line,
+			Column:    begin.Symbol,
+			EndLine:   endLine,
+			EndColumn: endColumn,
+		})
+	}
+	return StackTraceResponse{StackFrames: frames, TotalFrames: len(frames)}, nil
+}
+
+func (s *Server) scopes(args ScopesArguments) (ScopesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return ScopesResponse{}, errors.New("script is not stopped")
+	}
+	if args.FrameID < 1 || args.FrameID > len(s.stop.Frames) {
+		return ScopesResponse{}, errors.Errorf("frame %d not found", args.FrameID)
+	}
+	frame := s.stop.Frames[args.FrameID-1]
+
+	ret := ScopesResponse{Scopes: make([]Scope, 0)}
+	if args.FrameID == 1 { // the pending call
+		call := s.stop.Call
+		vars := make([]Variable, 0, len(call.Options)+len(call.Args))
+		for _, opt := range call.Node.Options() {
+			if v, ok := call.Options[opt.Name]; ok {
+				vars = append(vars, variable("#:"+opt.Name, v))
+			}
+		}
+		for i, arg := range call.Args {
+			vars = append(vars, Variable{Name: fmt.Sprintf("arg%d", i), Value: arg.Text(), Type: arg.Type().String()})
+		}
+		ret.Scopes = append(ret.Scopes, Scope{Name: "Call", VariablesReference: s.addVars(vars)})
+	}
+	for i, sc := range debug.Scopes(frame.EvalCtx) {
+		name := "Locals"
+		if sc.EvalCtx.OutScopeEvalCtx() == nil {
+			name = "Globals"
+		} else if i > 0 {
+			name = fmt.Sprintf("Outer %d", i)
+		}
+		vars := make([]Variable, 0, len(sc.Variables))
+		for _, v := range sc.Variables {
+			vars = append(vars, variable(v.Name, v.Value))
+		}
+		ret.Scopes = append(ret.Scopes, Scope{Name: name, VariablesReference: s.addVars(vars)})
+	}
+	return ret, nil
+}
+
+func (s *Server) variables(args VariablesArguments) VariablesResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vars := s.vars[args.VariablesReference]
+	if vars == nil {
+		vars = []Variable{}
+	}
+	return VariablesResponse{Variables: vars}
+}
+
+// addVars keeps `vars` until the script is resumed and returns its reference.
+func (s *Server) addVars(vars []Variable) int {
+	ref := len(s.vars) + 1
+	s.vars[ref] = vars
+	return ref
+}
+
+// end returns the line and the column after the last character of `text` that begins at `begin`,
+// it is on the same line of the last character even if the character is followed by a '\n'.
+func end(begin gendsl.Position, text string) (int, int) {
+	line, column := begin.Line, begin.Symbol
+	for _, r := range text {
+		if r == '\n' {
+			line, column = line+1, 1
+			continue
+		}
+		column++
+	}
+	return line, column
+}
+
+func variable(name string, v gendsl.Value) Variable {
+	return Variable{Name: name, Value: gendsl.Repr(v), Type: v.Type().String()}
+}
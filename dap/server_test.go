@@ -0,0 +1,237 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+	"github.com/ccbhj/gendsl/internal/wire"
+)
+
+// client talks to a Server in the same process.
+type client struct {
+	w      io.WriteCloser
+	msgs   chan message
+	events []message
+	seq    int
+	done   chan error
+}
+
+func newClient(s *Server) *client {
+	cr, sw := io.Pipe()
+	sr, cw := io.Pipe()
+	c := &client{w: cw, msgs: make(chan message, 64), done: make(chan error, 1)}
+	go func() {
+		c.done <- s.Serve(sr, sw)
+		sw.Close()
+	}()
+	go func() {
+		defer close(c.msgs)
+		r := bufio.NewReader(cr)
+		for {
+			bs, err := wire.ReadMessage(r)
+			if err != nil {
+				return
+			}
+			var msg message
+			if json.Unmarshal(bs, &msg) == nil {
+				c.msgs <- msg
+			}
+		}
+	}()
+	return c
+}
+
+func (c *client) next() message {
+	var msg message
+	Eventually(c.msgs).Should(Receive(&msg))
+	return msg
+}
+
+// call sends a request and decodes the body of its response into `body`, the events before it are kept.
+func (c *client) call(command string, args any, body any) message {
+	c.seq++
+	bs, err := json.Marshal(args)
+	Expect(err).ShouldNot(HaveOccurred())
+	req, err := json.Marshal(message{Seq: c.seq, Type: "request", Command: command, Arguments: bs})
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(wire.WriteMessage(c.w, req)).Should(Succeed())
+
+	for {
+		msg := c.next()
+		if msg.Type == "event" {
+			c.events = append(c.events, msg)
+			continue
+		}
+		Expect(msg.RequestSeq).Should(Equal(c.seq))
+		if body != nil && msg.Success {
+			Expect(json.Unmarshal(msg.Body, body)).Should(Succeed())
+		}
+		return msg
+	}
+}
+
+// event waits for the event `name` and decodes its body into `body`, the events before it are dropped.
+func (c *client) event(name string, body any) {
+	for {
+		var msg message
+		if len(c.events) > 0 {
+			msg, c.events = c.events[0], c.events[1:]
+		} else {
+			msg = c.next()
+		}
+		if msg.Type == "event" && msg.Event == name {
+			if body != nil {
+				Expect(json.Unmarshal(msg.Body, body)).Should(Succeed())
+			}
+			return
+		}
+	}
+}
+
+func plus(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+	var ret gendsl.Int
+	for _, arg := range args {
+		v, err := arg.Eval()
+		if err != nil {
+			return nil, err
+		}
+		ret += v.(gendsl.Int)
+	}
+	return ret, nil
+}
+
+var _ = Describe("Server", func() {
+	var (
+		c       *client
+		program string
+		script  = `(PLUS #:n 1 x
+  (PLUS 2 3)
+  (PLUS 4))
+`
+	)
+
+	BeforeEach(func() {
+		program = filepath.Join(GinkgoT().TempDir(), "main.dsl")
+		Expect(os.WriteFile(program, []byte(script), 0o644)).Should(Succeed())
+		env := gendsl.NewEnv().
+			WithProcedure("PLUS", gendsl.Procedure{Eval: plus}).
+			WithInt("x", 10)
+		c = newClient(NewServer(Config{Env: env}))
+
+		var caps Capabilities
+		Expect(c.call("initialize", map[string]any{"adapterID": "gendsl"}, &caps).Success).Should(BeTrue())
+		Expect(caps.SupportsConfigurationDoneRequest).Should(BeTrue())
+		c.event("initialized", nil)
+	})
+
+	AfterEach(func() {
+		Expect(c.call("disconnect", nil, nil).Success).Should(BeTrue())
+		Eventually(c.done).Should(Receive(BeNil()))
+	})
+
+	It("can stop at breakpoints and show the frames and variables", func() {
+		Expect(c.call("launch", LaunchArguments{Program: program}, nil).Success).Should(BeTrue())
+		var bps SetBreakpointsResponse
+		c.call("setBreakpoints", SetBreakpointsArguments{
+			Source:      Source{Path: program},
+			Breakpoints: []SourceBreakpoint{{Line: 2}, {Line: 4}},
+		}, &bps)
+		Expect(bps.Breakpoints).Should(HaveLen(2))
+		Expect(bps.Breakpoints[0].Verified).Should(BeTrue())
+		Expect(bps.Breakpoints[1].Verified).Should(BeFalse())
+		Expect(c.call("configurationDone", nil, nil).Success).Should(BeTrue())
+
+		var stopped StoppedEvent
+		c.event("stopped", &stopped)
+		Expect(stopped.Reason).Should(Equal("breakpoint"))
+		Expect(stopped.HitBreakpointIDs).Should(Equal([]int{bps.Breakpoints[0].ID}))
+
+		var trace StackTraceResponse
+		c.call("stackTrace", StackTraceArguments{ThreadID: threadID}, &trace)
+		Expect(trace.StackFrames).Should(HaveLen(2))
+		Expect(trace.StackFrames[0]).Should(Equal(StackFrame{
+			ID: 1, Name: "PLUS", Source: Source{Name: "main.dsl", Path: program},
+			Line: 2, Column: 3, EndLine: 2, EndColumn: 13,
+		}))
+		Expect(trace.StackFrames[1].Line).Should(Equal(1))
+
+		var scopes ScopesResponse
+		c.call("scopes", ScopesArguments{FrameID: 2}, &scopes)
+		Expect(scopes.Scopes).Should(HaveLen(1))
+		Expect(scopes.Scopes[0].Name).Should(Equal("Globals"))
+		var vars VariablesResponse
+		c.call("variables", VariablesArguments{VariablesReference: scopes.Scopes[0].VariablesReference}, &vars)
+		Expect(vars.Variables).Should(ContainElement(Variable{Name: "x", Value: "10", Type: "int"}))
+
+		c.call("scopes", ScopesArguments{FrameID: 1}, &scopes)
+		Expect(scopes.Scopes[0].Name).Should(Equal("Call"))
+		c.call("variables", VariablesArguments{VariablesReference: scopes.Scopes[0].VariablesReference}, &vars)
+		Expect(vars.Variables).Should(Equal([]Variable{
+			{Name: "arg0", Value: "2", Type: "ExprLiteral"},
+			{Name: "arg1", Value: "3", Type: "ExprLiteral"},
+		}))
+
+		Expect(c.call("continue", map[string]any{"threadId": threadID}, nil).Success).Should(BeTrue())
+		var output OutputEvent
+		c.event("output", &output)
+		Expect(output.Output).Should(Equal("19\n"))
+		var exited ExitedEvent
+		c.event("exited", &exited)
+		Expect(exited.ExitCode).Should(Equal(0))
+		c.event("terminated", nil)
+	})
+
+	It("can step through the calls", func() {
+		Expect(c.call("launch", LaunchArguments{Program: program, StopOnEntry: true}, nil).Success).Should(BeTrue())
+		c.call("configurationDone", nil, nil)
+
+		var stopped StoppedEvent
+		c.event("stopped", &stopped)
+		Expect(stopped.Reason).Should(Equal("entry"))
+
+		lines := make([]int, 0)
+		for _, cmd := range []string{"stepIn", "next"} {
+			Expect(c.call(cmd, map[string]any{"threadId": threadID}, nil).Success).Should(BeTrue())
+			c.event("stopped", &stopped)
+			Expect(stopped.Reason).Should(Equal("step"))
+			var trace StackTraceResponse
+			c.call("stackTrace", StackTraceArguments{ThreadID: threadID}, &trace)
+			lines = append(lines, trace.StackFrames[0].Line)
+		}
+		Expect(lines).Should(Equal([]int{2, 3}))
+
+		Expect(c.call("stepOut", map[string]any{"threadId": threadID}, nil).Success).Should(BeTrue())
+		c.event("terminated", nil)
+		Expect(c.call("next", map[string]any{"threadId": threadID}, nil).Success).Should(BeFalse())
+	})
+
+	It("can stop at procedures and terminate the script", func() {
+		Expect(c.call("launch", LaunchArguments{Program: program}, nil).Success).Should(BeTrue())
+		var bps SetBreakpointsResponse
+		c.call("setFunctionBreakpoints", SetFunctionBreakpointsArguments{
+			Breakpoints: []FunctionBreakpoint{{Name: "PLUS"}, {Name: "MINUS"}},
+		}, &bps)
+		Expect(bps.Breakpoints[0].Verified).Should(BeTrue())
+		Expect(bps.Breakpoints[1].Verified).Should(BeFalse())
+		c.call("configurationDone", nil, nil)
+		c.event("stopped", nil)
+
+		Expect(c.call("terminate", nil, nil).Success).Should(BeTrue())
+		var exited ExitedEvent
+		c.event("exited", &exited)
+		Expect(exited.ExitCode).Should(Equal(1))
+	})
+
+	It("reports the errors in launching", func() {
+		resp := c.call("launch", LaunchArguments{Program: filepath.Join(filepath.Dir(program), "none.dsl")}, nil)
+		Expect(resp.Success).Should(BeFalse())
+		Expect(resp.Message).Should(ContainSubstring("no such file"))
+	})
+})
@@ -0,0 +1,320 @@
+// Package debug pauses the evaluation of a script to let you look into it.
+//
+// A [debug.Debugger] holds the breakpoints, and a [debug.Session] evaluates a script in background
+// and stops before the procedure calls that hit the breakpoints or the steps:
+//
+//	d := debug.New()
+//	d.SetBreakpoint(debug.Breakpoint{Procedure: "PRINTLN"})
+//	s := d.Start(pc, gendsl.NewEvalCtx(nil, nil, env))
+//	for stop, ok := s.Next(); ok; stop, ok = s.Next() {
+//		fmt.Println(stop.Call.Name, debug.Scopes(stop.Call.EvalCtx))
+//		s.Resume(debug.StepOver)
+//	}
+//	v, err := s.Wait()
+package debug
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	// Breakpoint stops the evaluation before calling the procedures that match it.
+	Breakpoint struct {
+		ID        int    // assigned by the Debugger
+		Line      int    // line where the expression begins, 0 to match any line
+		Column    int    // symbol in the line where the expression begins, 0 to match any column
+		Procedure string // name of the procedure, empty to match any procedure
+	}
+
+	// Action tells a stopped Session how to go on.
+	Action int
+
+	// Reason tells why a Session stops.
+	Reason int
+
+	// Frame is a procedure call being evaluated.
+	Frame struct {
+		Name    string // name of the procedure
+		Node    gendsl.Node
+		EvalCtx *gendsl.EvalCtx // EvalCtx that the expression is evaluated with
+	}
+
+	// Stop describes where a Session stops.
+	Stop struct {
+		Reason     Reason
+		Breakpoint Breakpoint       // the breakpoint hit if Reason is ReasonBreakpoint
+		Call       gendsl.TraceCall // the call to be made after resuming
+		Frames     []Frame          // calls being evaluated, the innermost one comes first
+	}
+
+	// Scope is the bindings of an env in the scope chain.
+	Scope struct {
+		EvalCtx   *gendsl.EvalCtx
+		Variables []Variable // sorted by their names
+	}
+
+	// Variable is a binding in a scope.
+	Variable struct {
+		Name  string
+		Value gendsl.Value
+	}
+
+	// Debugger holds the breakpoints for the sessions, it is safe to change them while the sessions are running.
+	Debugger struct {
+		mu          sync.Mutex
+		breakpoints []Breakpoint
+		nextID      int
+	}
+
+	// Session is a script evaluated by a Debugger.
+	Session struct {
+		d       *Debugger
+		evalCtx *gendsl.EvalCtx
+		prev    gendsl.Tracer // tracer replaced during the evaluation
+
+		// used by the evaluation only
+		frames    []Frame
+		mode      Action
+		modeDepth int
+
+		pause   atomic.Bool
+		stops   chan Stop
+		actions chan Action
+		done    chan struct{}
+		result  gendsl.Value
+		err     error
+	}
+)
+
+const (
+	Continue Action = iota // run until a breakpoint is hit
+	StepInto               // stop at the next call
+	StepOver               // stop at the next call that is not inside the current one
+	StepOut                // stop at the next call after the current one returns
+	Abort                  // abort the evaluation with ErrAborted
+)
+
+const (
+	ReasonBreakpoint Reason = iota
+	ReasonStep
+	ReasonPause
+)
+
+// ErrAborted is returned by the evaluation aborted by [debug.Abort].
+var ErrAborted = errors.New("evaluation aborted by the debugger")
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonBreakpoint:
+		return "breakpoint"
+	case ReasonStep:
+		return "step"
+	case ReasonPause:
+		return "pause"
+	}
+	return "unknown"
+}
+
+// New creates a Debugger without any breakpoint.
+func New() *Debugger {
+	return &Debugger{}
+}
+
+// SetBreakpoint adds a breakpoint and returns it with its ID assigned.
+// An error is returned if the breakpoint matches everything.
+func (d *Debugger) SetBreakpoint(bp Breakpoint) (Breakpoint, error) {
+	if bp.Line <= 0 && bp.Column <= 0 && bp.Procedure == "" {
+		return Breakpoint{}, errors.New("breakpoint should specify a line, a column or a procedure")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextID++
+	bp.ID = d.nextID
+	d.breakpoints = append(d.breakpoints, bp)
+	return bp, nil
+}
+
+// ClearBreakpoint removes the breakpoint with `id`, it returns false if not found.
+func (d *Debugger) ClearBreakpoint(id int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, bp := range d.breakpoints {
+		if bp.ID == id {
+			d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ClearBreakpoints removes all the breakpoints.
+func (d *Debugger) ClearBreakpoints() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = nil
+}
+
+// Breakpoints returns the breakpoints set.
+func (d *Debugger) Breakpoints() []Breakpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Breakpoint(nil), d.breakpoints...)
+}
+
+// match returns the first breakpoint matching `call`.
+func (d *Debugger) match(call gendsl.TraceCall) (Breakpoint, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	begin := call.Node.Range().Begin
+	for _, bp := range d.breakpoints {
+		if (bp.Line <= 0 || bp.Line == begin.Line) &&
+			(bp.Column <= 0 || bp.Column == begin.Symbol) &&
+			(bp.Procedure == "" || bp.Procedure == call.Name) {
+			return bp, true
+		}
+	}
+	return Breakpoint{}, false
+}
+
+// Start evaluates the script of `pc` with `evalCtx` in background.
+// The tracer of `evalCtx` is replaced by the Session during the evaluation, and is still told about the nodes.
+// It will panic if evalCtx is nil.
+func (d *Debugger) Start(pc *gendsl.ParseContext, evalCtx *gendsl.EvalCtx) *Session {
+	if evalCtx == nil {
+		panic("evalCtx cannot be nil")
+	}
+	s := &Session{
+		d:       d,
+		evalCtx: evalCtx,
+		prev:    evalCtx.Tracer(),
+		mode:    Continue,
+		stops:   make(chan Stop),
+		actions: make(chan Action),
+		done:    make(chan struct{}),
+	}
+	evalCtx.WithTracer(s)
+	go func() {
+		defer close(s.done)
+		s.result, s.err = pc.Eval(evalCtx)
+		evalCtx.WithTracer(s.prev)
+	}()
+	return s
+}
+
+// Next blocks until the evaluation stops or finishes, it returns false if finished.
+// The evaluation keeps stopped until [debug.Session.Resume] is called.
+func (s *Session) Next() (Stop, bool) {
+	select {
+	case stop := <-s.stops:
+		return stop, true
+	case <-s.done:
+		return Stop{}, false
+	}
+}
+
+// Resume continues a stopped evaluation with `action`,
+// it should only be called once after [debug.Session.Next] returns a Stop.
+func (s *Session) Resume(action Action) {
+	select {
+	case s.actions <- action:
+	case <-s.done:
+	}
+}
+
+// Pause stops the evaluation before the next procedure call.
+func (s *Session) Pause() {
+	s.pause.Store(true)
+}
+
+// Wait continues all the stops and returns the result of the evaluation.
+func (s *Session) Wait() (gendsl.Value, error) {
+	for _, ok := s.Next(); ok; _, ok = s.Next() {
+		s.Resume(Continue)
+	}
+	return s.result, s.err
+}
+
+func (s *Session) EnterNode(ev gendsl.TraceEvent) {
+	if s.prev != nil {
+		s.prev.EnterNode(ev)
+	}
+	if ev.Kind == gendsl.ExprTypeExpr {
+		s.frames = append(s.frames, Frame{Name: ev.Name, Node: ev.Node, EvalCtx: ev.EvalCtx})
+	}
+}
+
+func (s *Session) ExitNode(ev gendsl.TraceEvent) {
+	if ev.Kind == gendsl.ExprTypeExpr {
+		s.frames = s.frames[:len(s.frames)-1]
+	}
+	if s.prev != nil {
+		s.prev.ExitNode(ev)
+	}
+}
+
+func (s *Session) CallProcedure(call gendsl.TraceCall) error {
+	if ct, ok := s.prev.(gendsl.CallTracer); ok {
+		if err := ct.CallProcedure(call); err != nil {
+			return err
+		}
+	}
+
+	depth := len(s.frames)
+	stop := Stop{Call: call}
+	if bp, ok := s.d.match(call); ok {
+		stop.Reason, stop.Breakpoint = ReasonBreakpoint, bp
+	} else if s.pause.Swap(false) {
+		stop.Reason = ReasonPause
+	} else if s.stepped(depth) {
+		stop.Reason = ReasonStep
+	} else {
+		return nil
+	}
+	s.pause.Store(false)
+
+	stop.Frames = make([]Frame, 0, depth)
+	for i := depth - 1; i >= 0; i-- {
+		stop.Frames = append(stop.Frames, s.frames[i])
+	}
+	s.stops <- stop
+	action := <-s.actions
+	if action == Abort {
+		return ErrAborted
+	}
+	s.mode, s.modeDepth = action, depth
+	return nil
+}
+
+// stepped reports whether a call at `depth` finishes the current step.
+func (s *Session) stepped(depth int) bool {
+	switch s.mode {
+	case StepInto:
+		return true
+	case StepOver:
+		return depth <= s.modeDepth
+	case StepOut:
+		return depth < s.modeDepth
+	}
+	return false
+}
+
+// Scopes returns the scopes visible to `evalCtx`, the innermost one comes first.
+func Scopes(evalCtx *gendsl.EvalCtx) []Scope {
+	scopes := make([]Scope, 0)
+	for c := evalCtx; c != nil; c = c.OutScopeEvalCtx() {
+		vars := make([]Variable, 0)
+		c.Env().Range(func(id string, v gendsl.Value) bool {
+			vars = append(vars, Variable{Name: id, Value: v})
+			return true
+		})
+		sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+		scopes = append(scopes, Scope{EvalCtx: c, Variables: vars})
+	}
+	return scopes
+}
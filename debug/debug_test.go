@@ -0,0 +1,130 @@
+package debug
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+)
+
+func plus(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+	var ret gendsl.Int
+	for _, arg := range args {
+		v, err := arg.Eval()
+		if err != nil {
+			return nil, err
+		}
+		ret += v.(gendsl.Int)
+	}
+	return ret, nil
+}
+
+// let evaluates (LET {name} {value} {body}).
+func let(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+	v, err := args[1].Eval()
+	if err != nil {
+		return nil, err
+	}
+	return args[2].EvalWithEnv(gendsl.NewEnv().WithValue(args[0].Text(), v))
+}
+
+var _ = Describe("Debugger", func() {
+	var (
+		env    *gendsl.Env
+		d      *Debugger
+		script = `(LET x 1
+  (PLUS #:n 0 x
+    (PLUS 2 3)
+    (PLUS 4)))`
+	)
+
+	BeforeEach(func() {
+		env = gendsl.NewEnv().
+			WithProcedure("PLUS", gendsl.Procedure{Eval: plus}).
+			WithProcedure("LET", gendsl.Procedure{Eval: let})
+		d = New()
+	})
+
+	start := func() *Session {
+		pc, err := gendsl.MakeParseContext(script)
+		Expect(err).ShouldNot(HaveOccurred())
+		return d.Start(pc, gendsl.NewEvalCtx(nil, nil, env))
+	}
+
+	// lines returns the lines of the stops when resuming with `action`.
+	lines := func(s *Session, action Action) []int {
+		ret := make([]int, 0)
+		for stop, ok := s.Next(); ok; stop, ok = s.Next() {
+			ret = append(ret, stop.Call.Node.Range().Begin.Line)
+			s.Resume(action)
+		}
+		return ret
+	}
+
+	It("can stop at the breakpoints", func() {
+		_, err := d.SetBreakpoint(Breakpoint{Procedure: "PLUS"})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		s := start()
+		stop, ok := s.Next()
+		Expect(ok).Should(BeTrue())
+		Expect(stop.Reason).Should(Equal(ReasonBreakpoint))
+		Expect(stop.Breakpoint.ID).Should(Equal(1))
+		Expect(stop.Call.Name).Should(Equal("PLUS"))
+		Expect(stop.Call.Args).Should(HaveLen(3))
+		Expect(stop.Call.Options).Should(HaveKeyWithValue("n", gendsl.Int(0)))
+		Expect(stop.Frames).Should(HaveLen(2))
+		Expect(stop.Frames[1].Name).Should(Equal("LET"))
+
+		scopes := Scopes(stop.Call.EvalCtx)
+		Expect(scopes).Should(HaveLen(2))
+		Expect(scopes[0].Variables).Should(Equal([]Variable{{Name: "x", Value: gendsl.Int(1)}}))
+		Expect(scopes[1].Variables).Should(HaveLen(2))
+
+		s.Resume(Continue)
+		Expect(lines(s, Continue)).Should(Equal([]int{3, 4}))
+		Expect(s.Wait()).Should(Equal(gendsl.Int(10)))
+	})
+
+	It("can stop at the breakpoints on lines", func() {
+		_, err := d.SetBreakpoint(Breakpoint{Line: 3})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lines(start(), Continue)).Should(Equal([]int{3}))
+
+		_, err = d.SetBreakpoint(Breakpoint{})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("can step into, over and out", func() {
+		d.SetBreakpoint(Breakpoint{Line: 1})
+		Expect(lines(start(), StepInto)).Should(Equal([]int{1, 2, 3, 4}))
+		Expect(lines(start(), StepOver)).Should(Equal([]int{1}))
+
+		d.ClearBreakpoints()
+		d.SetBreakpoint(Breakpoint{Line: 2})
+		s := start()
+		stop, _ := s.Next()
+		Expect(stop.Call.Name).Should(Equal("PLUS"))
+		s.Resume(StepInto)
+		stop, _ = s.Next()
+		Expect(stop.Reason).Should(Equal(ReasonStep))
+		Expect(stop.Call.Node.Range().Begin.Line).Should(Equal(3))
+		s.Resume(StepOver)
+		stop, _ = s.Next()
+		Expect(stop.Call.Node.Range().Begin.Line).Should(Equal(4))
+		s.Resume(StepOut)
+		Expect(lines(s, StepOut)).Should(BeEmpty())
+		Expect(s.Wait()).Should(Equal(gendsl.Int(10)))
+	})
+
+	It("can pause and abort the evaluation", func() {
+		s := start()
+		s.Pause()
+		stop, ok := s.Next()
+		Expect(ok).Should(BeTrue())
+		Expect(stop.Reason).Should(Equal(ReasonPause))
+		s.Resume(Abort)
+		_, err := s.Wait()
+		Expect(err).Should(MatchError(ErrAborted))
+	})
+})
@@ -0,0 +1,403 @@
+package gendsl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Decoder populates Go values with the Values of a script, like the config evaluated by a DSL, see [gendsl.Decode].
+	Decoder struct {
+		// WeakNumbers allows the numbers to be converted between integers and floats by [gendsl.ToInt], [gendsl.ToUint] and [gendsl.ToFloat],
+		// and the Strings of numbers like "8080" to be decoded into the numeric fields.
+		// Without it, an integer field only accepts Int, Uint and BigInt, and a float field only accepts Float.
+		WeakNumbers bool
+	}
+
+	// DecodeError is returned when a Value cannot be decoded into a Go value,
+	// Path tells where the decoding failed, like servers[1].port, which is empty for the root value.
+	DecodeError struct {
+		Path string
+		Err  error
+	}
+)
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("cannot decode: %s", e.Err)
+	}
+	return fmt.Sprintf("cannot decode %s: %s", e.Path, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Decode populates `out`, which must be a non-nil pointer, with `v` in the default [gendsl.Decoder]:
+//   - a struct is populated with a UserData of [gendsl.Object], map[string]Value, map[string]any or a [gendsl.Selector],
+//     a field is decoded from the key in its `dsl` tag, or the key matching its name regardless of the case if it has no tag.
+//     The fields tagged with `dsl:"-"` are skipped, the embedded structs without tags are flattened, and the fields without keys are left as they are.
+//   - a map of string keys is populated with the same kinds of values as a struct except for a Selector.
+//   - a slice or an array is populated with a UserData of []Value or []any, and a []byte with Bytes as well.
+//   - a string is decoded from a String, Symbol or Keyword, and a bool is decoded from a Bool.
+//   - an integer or a float is decoded from a number, an error is returned if it overflows.
+//   - a time.Time, time.Duration, *big.Int, *big.Rat or *regexp.Regexp is decoded from the Value that unwraps into it.
+//   - a pointer is allocated and its element is decoded.
+//   - a Value is set with `v` itself if it is assignable, and an any is set with the Go value `v` unwraps into,
+//     where the lists and objects are decoded into []any and map[string]any.
+//   - any type is set with the V of a UserData if the V is assignable to it.
+//
+// Nil decodes into the zero value of any type. A [gendsl.DecodeError] is returned if any value cannot be decoded.
+func Decode(v Value, out any) error {
+	return Decoder{}.Decode(v, out)
+}
+
+// Decode populates `out` with `v`, see [gendsl.Decode] for the rules.
+func (d Decoder) Decode(v Value, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.Errorf("cannot decode into %T, expecting a non-nil pointer", out)
+	}
+	return d.decode(v, rv.Elem(), "")
+}
+
+func (d Decoder) decode(v Value, out reflect.Value, path string) error {
+	err := d.decodeValue(nilToNil(v), out, path)
+	if _, ok := err.(*DecodeError); err != nil && !ok {
+		return &DecodeError{Path: path, Err: err}
+	}
+	return err
+}
+
+func (d Decoder) decodeValue(v Value, out reflect.Value, path string) error {
+	t := out.Type()
+	if _, ok := v.(Nil); ok {
+		out.Set(reflect.Zero(t))
+		return nil
+	}
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		if x := natural(v); x != nil {
+			out.Set(reflect.ValueOf(x))
+		} else {
+			out.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+	if reflect.TypeOf(v).AssignableTo(t) {
+		out.Set(reflect.ValueOf(v))
+		return nil
+	}
+	if uv := v.Unwrap(); uv != nil && reflect.TypeOf(uv).AssignableTo(t) {
+		out.Set(reflect.ValueOf(uv))
+		return nil
+	}
+	switch t {
+	case durationType: // not an integer of nanoseconds
+		return &TypeError{Actual: v.Type(), Expected: ValueTypeDuration}
+	case timeType:
+		return &TypeError{Actual: v.Type(), Expected: ValueTypeTime}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		if err := d.decode(v, elem.Elem(), path); err != nil {
+			return err
+		}
+		out.Set(elem)
+	case reflect.Bool:
+		b, ok := v.(Bool)
+		if !ok {
+			return &TypeError{Actual: v.Type(), Expected: ValueTypeBool}
+		}
+		out.SetBool(bool(b))
+	case reflect.String:
+		s, err := ToString(v)
+		if _, ok := v.(Bytes); ok || err != nil {
+			return &TypeError{Actual: v.Type(), Expected: ValueTypeString | ValueTypeSymbol | ValueTypeKeyword}
+		}
+		out.SetString(string(s))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := d.checkNumber(v, ValueTypeInt|ValueTypeUInt|ValueTypeBigInt); err != nil {
+			return err
+		}
+		i, err := ToInt(d.number(v))
+		if err != nil {
+			return err
+		}
+		if out.OverflowInt(int64(i)) {
+			return &TypeError{Actual: v.Type(), Expected: ValueTypeInt, Reason: fmt.Sprintf("%d overflows %s", i, t)}
+		}
+		out.SetInt(int64(i))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if err := d.checkNumber(v, ValueTypeInt|ValueTypeUInt|ValueTypeBigInt); err != nil {
+			return err
+		}
+		u, err := ToUint(d.number(v))
+		if err != nil {
+			return err
+		}
+		if out.OverflowUint(uint64(u)) {
+			return &TypeError{Actual: v.Type(), Expected: ValueTypeUInt, Reason: fmt.Sprintf("%d overflows %s", u, t)}
+		}
+		out.SetUint(uint64(u))
+	case reflect.Float32, reflect.Float64:
+		if err := d.checkNumber(v, ValueTypeFloat); err != nil {
+			return err
+		}
+		f, err := ToFloat(d.number(v))
+		if err != nil {
+			return err
+		}
+		if out.OverflowFloat(float64(f)) {
+			return &TypeError{Actual: v.Type(), Expected: ValueTypeFloat, Reason: fmt.Sprintf("%v overflows %s", f, t)}
+		}
+		out.SetFloat(float64(f))
+	case reflect.Slice, reflect.Array:
+		return d.decodeList(v, out, path)
+	case reflect.Map:
+		return d.decodeMap(v, out, path)
+	case reflect.Struct:
+		return d.decodeStruct(v, out, path)
+	default:
+		return errors.Errorf("cannot decode %s into %s", v.Type(), t)
+	}
+	return nil
+}
+
+// checkNumber returns an error if `v` is not one of the types `strict` when the numbers are not weakly converted.
+func (d Decoder) checkNumber(v Value, strict ValueType) error {
+	if d.WeakNumbers {
+		return nil
+	}
+	if typeOf(v)&strict == 0 {
+		return &TypeError{Actual: typeOf(v), Expected: strict}
+	}
+	return nil
+}
+
+// number parses a String of number when the numbers are weakly converted.
+func (d Decoder) number(v Value) Value {
+	s, ok := v.(String)
+	if !ok || !d.WeakNumbers {
+		return v
+	}
+	text := strings.TrimSpace(string(s))
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return Int(i)
+	}
+	if u, err := strconv.ParseUint(text, 10, 64); err == nil {
+		return Uint(u)
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return Float(f)
+	}
+	return v
+}
+
+func (d Decoder) decodeList(v Value, out reflect.Value, path string) error {
+	vs, ok := listOf(v)
+	if !ok {
+		return &TypeError{Actual: v.Type(), Expected: ValueTypeUserData}
+	}
+	t := out.Type()
+	if t.Kind() == reflect.Array {
+		if len(vs) != t.Len() {
+			return errors.Errorf("expecting %d element(s), but got %d", t.Len(), len(vs))
+		}
+		for i, elem := range vs {
+			if err := d.decode(elem, out.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	ret := reflect.MakeSlice(t, len(vs), len(vs))
+	for i, elem := range vs {
+		if err := d.decode(elem, ret.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	out.Set(ret)
+	return nil
+}
+
+func (d Decoder) decodeMap(v Value, out reflect.Value, path string) error {
+	t := out.Type()
+	if t.Key().Kind() != reflect.String {
+		return errors.Errorf("cannot decode into %s, expecting string keys", t)
+	}
+	entries, _, ok := entriesOf(v)
+	if !ok || entries == nil {
+		return &TypeError{Actual: v.Type(), Expected: ValueTypeUserData}
+	}
+	ret := reflect.MakeMapWithSize(t, len(entries))
+	for _, k := range sortedKeys(entries) {
+		elem := reflect.New(t.Elem()).Elem()
+		if err := d.decode(entries[k], elem, joinPath(path, k)); err != nil {
+			return err
+		}
+		ret.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+	}
+	out.Set(ret)
+	return nil
+}
+
+func (d Decoder) decodeStruct(v Value, out reflect.Value, path string) error {
+	entries, selector, ok := entriesOf(v)
+	if !ok {
+		return &TypeError{Actual: v.Type(), Expected: ValueTypeUserData}
+	}
+	var keys []string
+	if entries != nil {
+		keys = sortedKeys(entries)
+	}
+	lookup := func(name string, tagged bool) (Value, string, bool) {
+		if entries == nil {
+			fv, ok := selector.Select(name)
+			return fv, name, ok
+		}
+		if fv, ok := entries[name]; ok {
+			return fv, name, true
+		}
+		if tagged {
+			return nil, "", false
+		}
+		for _, k := range keys {
+			if strings.EqualFold(k, name) {
+				return entries[k], k, true
+			}
+		}
+		return nil, "", false
+	}
+
+	var fill func(out reflect.Value) error
+	fill = func(out reflect.Value) error {
+		t := out.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag, tagged := field.Tag.Lookup("dsl")
+			name, _, _ := strings.Cut(tag, ",")
+			switch {
+			case name == "-":
+				continue
+			case field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct:
+				if err := fill(out.Field(i)); err != nil {
+					return err
+				}
+				continue
+			case !field.IsExported():
+				continue
+			case name == "":
+				name, tagged = field.Name, false
+			}
+			fv, key, ok := lookup(name, tagged)
+			if !ok {
+				continue
+			}
+			if err := d.decode(fv, out.Field(i), joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fill(out)
+}
+
+// listOf returns the elements of a UserData of []Value or []any.
+func listOf(v Value) ([]Value, bool) {
+	u, ok := v.(*UserData)
+	if !ok {
+		return nil, false
+	}
+	switch x := u.V.(type) {
+	case []Value:
+		return x, true
+	case []any:
+		vs := make([]Value, 0, len(x))
+		for _, elem := range x {
+			vs = append(vs, toValue(elem))
+		}
+		return vs, true
+	}
+	return nil, false
+}
+
+// entriesOf returns the entries of a UserData of Object, map[string]Value or map[string]any,
+// or the Selector of a UserData that can only be selected.
+func entriesOf(v Value) (map[string]Value, Selector, bool) {
+	u, ok := v.(*UserData)
+	if !ok {
+		return nil, nil, false
+	}
+	switch x := u.V.(type) {
+	case Object:
+		return x, nil, true
+	case map[string]Value:
+		return x, nil, true
+	case map[string]any:
+		entries := make(map[string]Value, len(x))
+		for k, elem := range x {
+			entries[k] = toValue(elem)
+		}
+		return entries, nil, true
+	case Selector:
+		return nil, x, true
+	}
+	return nil, nil, false
+}
+
+// toValue returns `x` if it is a Value, or wraps it in a UserData.
+func toValue(x any) Value {
+	switch x := x.(type) {
+	case nil:
+		return Nil{}
+	case Value:
+		return x
+	}
+	return &UserData{V: x}
+}
+
+// natural returns the Go value that `v` unwraps into, the lists and the objects are unwrapped as []any and map[string]any.
+func natural(v Value) any {
+	if vs, ok := listOf(v); ok {
+		ret := make([]any, 0, len(vs))
+		for _, elem := range vs {
+			ret = append(ret, natural(elem))
+		}
+		return ret
+	}
+	if entries, _, ok := entriesOf(v); ok && entries != nil {
+		ret := make(map[string]any, len(entries))
+		for k, elem := range entries {
+			ret[k] = natural(elem)
+		}
+		return ret
+	}
+	return v.Unwrap()
+}
+
+func sortedKeys(m map[string]Value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,144 @@
+package gendsl
+
+import (
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+type (
+	listenConfig struct {
+		Host string `dsl:"host"`
+		Port uint16 `dsl:"port"`
+	}
+
+	serverConfig struct {
+		listenConfig
+		Name     string
+		Timeout  time.Duration     `dsl:"timeout"`
+		Weight   float64           `dsl:"weight"`
+		Tags     []string          `dsl:"tags"`
+		Limits   map[string]int    `dsl:"limits"`
+		Backup   *listenConfig     `dsl:"backup"`
+		Since    time.Time         `dsl:"since"`
+		Total    *big.Int          `dsl:"total"`
+		Extra    any               `dsl:"extra"`
+		Raw      Value             `dsl:"raw"`
+		Ignored  string            `dsl:"-"`
+		Defaults map[string]string `dsl:"defaults"`
+	}
+)
+
+var _ = Describe("Decode", func() {
+	It("populates the structs", func() {
+		since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		v := &UserData{V: Object{
+			"host":    String("localhost"),
+			"port":    Int(8080),
+			"NAME":    NewKeyword("main"),
+			"timeout": Duration(time.Second),
+			"weight":  Float(0.5),
+			"tags":    &UserData{V: []Value{String("a"), NewSymbol("b")}},
+			"limits":  &UserData{V: map[string]Value{"cpu": Int(2), "mem": Uint(512)}},
+			"backup":  &UserData{V: map[string]any{"host": String("backup"), "port": Int(9090)}},
+			"since":   Time(since),
+			"total":   bigInt("123456789012345678901234567890"),
+			"extra":   &UserData{V: []Value{Int(1), &UserData{V: Object{"a": String("b")}}, Nil{}}},
+			"raw":     Float(1.5),
+			"-":       String("no"),
+			"Ignored": String("no"),
+		}}
+		cfg := serverConfig{Defaults: map[string]string{"keep": "me"}}
+		gomega.Expect(Decode(v, &cfg)).Should(gomega.Succeed())
+		gomega.Expect(cfg).Should(gomega.Equal(serverConfig{
+			listenConfig: listenConfig{Host: "localhost", Port: 8080},
+			Name:         "main",
+			Timeout:      time.Second,
+			Weight:       0.5,
+			Tags:         []string{"a", "b"},
+			Limits:       map[string]int{"cpu": 2, "mem": 512},
+			Backup:       &listenConfig{Host: "backup", Port: 9090},
+			Since:        since,
+			Total:        bigInt("123456789012345678901234567890").Unwrap().(*big.Int),
+			Extra:        []any{int64(1), map[string]any{"a": "b"}, nil},
+			Raw:          Float(1.5),
+			Defaults:     map[string]string{"keep": "me"},
+		}))
+	})
+
+	It("decodes the results of the scripts", func() {
+		env := NewEnv().WithProcedure("LIST", Procedure{Eval: CheckNArgs("*", _array)})
+		v, err := EvalExpr(`(LIST 1 2 3)`, env)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		var ports [3]int8
+		gomega.Expect(Decode(v, &ports)).Should(gomega.Succeed())
+		gomega.Expect(ports).Should(gomega.Equal([3]int8{1, 2, 3}))
+		var pair [2]int
+		gomega.Expect(Decode(v, &pair)).Should(gomega.MatchError("cannot decode: expecting 2 element(s), but got 3"))
+
+		v, err = FromJSON([]byte(`{"host": "h", "port": 80, "backup": null}`))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		cfg := serverConfig{Backup: &listenConfig{}}
+		gomega.Expect(Decode(v, &cfg)).Should(gomega.Succeed())
+		gomega.Expect(cfg.Host).Should(gomega.Equal("h"))
+		gomega.Expect(cfg.Port).Should(gomega.Equal(uint16(80)))
+		gomega.Expect(cfg.Backup).Should(gomega.BeNil())
+
+		var user struct{ Bar Value }
+		gomega.Expect(Decode(&UserData{V: Map{"Bar": Int(10)}}, &user)).Should(gomega.Succeed())
+		gomega.Expect(user.Bar).Should(gomega.Equal(Int(10)))
+	})
+
+	It("converts the numbers weakly if it is allowed", func() {
+		var cfg serverConfig
+		v := &UserData{V: Object{"port": Float(80), "weight": Int(1), "limits": &UserData{V: Object{"cpu": String(" 4 ")}}}}
+		err := Decode(v, &cfg)
+		var te *TypeError
+		gomega.Expect(errors.As(err, &te)).Should(gomega.BeTrue())
+		gomega.Expect(err).Should(gomega.MatchError("cannot decode port: expecting int|uint|bigint, but got float"))
+
+		gomega.Expect(Decoder{WeakNumbers: true}.Decode(v, &cfg)).Should(gomega.Succeed())
+		gomega.Expect(cfg.Port).Should(gomega.Equal(uint16(80)))
+		gomega.Expect(cfg.Weight).Should(gomega.Equal(1.0))
+		gomega.Expect(cfg.Limits).Should(gomega.Equal(map[string]int{"cpu": 4}))
+
+		for v, msg := range map[Value]string{
+			&UserData{V: Object{"port": Float(80.5)}}: "cannot decode port: cannot convert float to uint: 80.5 is not an integer",
+			&UserData{V: Object{"port": Int(65536)}}:  "cannot decode port: cannot convert int to uint: 65536 overflows uint16",
+			&UserData{V: Object{"port": Int(-1)}}:     "cannot decode port: cannot convert int to uint: -1 is negative",
+			&UserData{V: Object{"port": String("x")}}: "cannot decode port: expecting int|uint|float|bigint|decimal, but got string",
+		} {
+			gomega.Expect(Decoder{WeakNumbers: true}.Decode(v, &cfg)).Should(gomega.MatchError(msg))
+		}
+	})
+
+	It("reports the paths where the decoding failed", func() {
+		var cfg struct {
+			Servers []serverConfig `dsl:"servers"`
+		}
+		for v, want := range map[Value]string{
+			&UserData{V: Object{"servers": &UserData{V: []Value{
+				&UserData{V: Object{"host": String("a")}},
+				&UserData{V: Object{"tags": &UserData{V: []Value{String("a"), Int(1)}}}},
+			}}}}: "servers[1].tags[1]",
+			&UserData{V: Object{"servers": &UserData{V: []Value{
+				&UserData{V: Object{"limits": &UserData{V: Object{"cpu": Bool(true)}}}},
+			}}}}: "servers[0].limits.cpu",
+			&UserData{V: Object{"servers": &UserData{V: []Value{
+				&UserData{V: Object{"timeout": Int(1)}},
+			}}}}: "servers[0].timeout",
+			&UserData{V: Object{"servers": String("a")}}: "servers",
+			Int(1): "",
+		} {
+			err := Decode(v, &cfg)
+			var de *DecodeError
+			gomega.Expect(errors.As(err, &de)).Should(gomega.BeTrue(), want)
+			gomega.Expect(de.Path).Should(gomega.Equal(want))
+		}
+
+		gomega.Expect(Decode(Int(1), cfg)).Should(gomega.MatchError(gomega.ContainSubstring("expecting a non-nil pointer")))
+	})
+})
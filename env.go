@@ -1,5 +1,7 @@
 package gendsl
 
+import "github.com/pkg/errors"
+
 // Env stores the mapping of identifiers and values.
 // Note that an Env is not concurrently safe.
 type Env struct {
@@ -82,8 +84,86 @@ func (e *Env) WithUserData(id string, ud *UserData) *Env {
 	return e
 }
 
+// WithSymbol registers a [gendsl.Symbol] into the env.
+func (e *Env) WithSymbol(id string, s Symbol) *Env {
+	e.m[id] = s
+	return e
+}
+
+// WithKeyword registers a [gendsl.Keyword] into the env.
+func (e *Env) WithKeyword(id string, k Keyword) *Env {
+	e.m[id] = k
+	return e
+}
+
+// WithBigInt registers a [gendsl.BigInt] into the env.
+func (e *Env) WithBigInt(id string, b BigInt) *Env {
+	e.m[id] = b
+	return e
+}
+
+// WithDecimal registers a [gendsl.Decimal] into the env.
+func (e *Env) WithDecimal(id string, d Decimal) *Env {
+	e.m[id] = d
+	return e
+}
+
+// WithTime registers a [gendsl.Time] into the env.
+func (e *Env) WithTime(id string, t Time) *Env {
+	e.m[id] = t
+	return e
+}
+
+// WithDuration registers a [gendsl.Duration] into the env.
+func (e *Env) WithDuration(id string, d Duration) *Env {
+	e.m[id] = d
+	return e
+}
+
+// WithBytes registers a [gendsl.Bytes] into the env.
+func (e *Env) WithBytes(id string, b Bytes) *Env {
+	e.m[id] = b
+	return e
+}
+
+// WithRegexp registers a [gendsl.Regexp] into the env.
+func (e *Env) WithRegexp(id string, r Regexp) *Env {
+	e.m[id] = r
+	return e
+}
+
 // WithNil registers a [gendsl.Nil] into the env.
 func (e *Env) WithNil(id string, n Nil) *Env {
 	e.m[id] = n
 	return e
 }
+
+// WithKey registers `val` into the env by the identifier that `key` refers to,
+// a key can be a [gendsl.Symbol], a [gendsl.Keyword] or a [gendsl.String], so that 'red, :red and "red" all refer to red.
+// It will panic if the key is not supported or val == nil.
+func (e *Env) WithKey(key Value, val Value) *Env {
+	id, ok := keyName(key)
+	if !ok {
+		panic(errors.Errorf("invalid key %s for env", Repr(key)))
+	}
+	return e.WithValue(id, val)
+}
+
+// LookupKey looks up the value of the identifier that `key` refers to, see [gendsl.Env.WithKey].
+func (e *Env) LookupKey(key Value) (v Value, found bool) {
+	id, ok := keyName(key)
+	if !ok {
+		return nil, false
+	}
+	return e.Lookup(id)
+}
+
+// Range calls `f` for each identifier and its value in the env in no particular order.
+// It stops the iteration once `f` returns false.
+func (e *Env) Range(f func(id string, v Value) bool) {
+	for k, v := range e.m {
+		if !f(k, v) {
+			return
+		}
+	}
+}
@@ -8,13 +8,47 @@ import (
 
 // SyntaxError got thrown when a parsing error found.
 type SyntaxError struct {
-	pe *parseError
+	// pos near where the error found
+	BeginLine, EndLine int
+	BeginSym, EndSym   int
+	pe                 *parseError
+	cause              error // set if the script is parsed but a literal is invalid
 }
 
 func (e *SyntaxError) Error() string {
+	if e.pe == nil {
+		return fmt.Sprintf("syntax error (line %v symbol %v - line %v symbol %v): %s",
+			e.BeginLine, e.BeginSym, e.EndLine, e.EndSym, e.cause)
+	}
 	return e.pe.Error()
 }
 
+func newSyntaxError(pe *parseError) error {
+	pos := translatePositions(pe.p.buffer, []int{int(pe.max.begin), int(pe.max.end)})
+	beg, end := pos[int(pe.max.begin)], pos[int(pe.max.end)]
+
+	return &SyntaxError{
+		BeginLine: beg.line,
+		EndLine:   end.line,
+		BeginSym:  beg.symbol,
+		EndSym:    end.symbol,
+		pe:        pe,
+	}
+}
+
+func syntaxErrorf(p *parser, begin, end uint32, f string, args ...any) error {
+	pos := translatePositions(p.buffer, []int{int(begin), int(end)})
+	beg, en := pos[int(begin)], pos[int(end)]
+
+	return &SyntaxError{
+		BeginLine: beg.line,
+		EndLine:   en.line,
+		BeginSym:  beg.symbol,
+		EndSym:    en.symbol,
+		cause:     errors.Errorf(f, args...),
+	}
+}
+
 // EvaluateError got thrown during the evaluation.
 type EvaluateError struct {
 	// pos where the expression cannot be evaluated.
@@ -4,10 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"regexp"
 	"strings"
 
 	"github.com/ccbhj/gendsl"
+	"github.com/pkg/errors"
 )
 
 type MiniAWK struct {
@@ -58,7 +58,7 @@ func _pattern(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value)
 	}, nil
 }
 
-// _match for (match {pattern} {text}) to report if `text` match the regex expr {pattern}.
+// _match for (match {pattern} {text}) to report if `text` match the regexp literal {pattern}, which is compiled only once.
 func _match(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
 	if len(args) < 1 {
 		panic("need one or more arguments")
@@ -71,7 +71,11 @@ func _match(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (g
 	if err != nil {
 		panic(err)
 	}
-	result, err := regexp.MatchString(p.Unwrap().(string), s.Unwrap().(string))
+	re, err := gendsl.As[gendsl.Regexp](p)
+	if err != nil {
+		return nil, errors.WithMessage(err, "pattern of match")
+	}
+	result, err := re.Match(s)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +102,11 @@ func _printf(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (
 		arr = append(arr, v)
 	}
 
-	fmt.Printf(s.Unwrap().(string), arr...)
+	format, err := gendsl.ToString(s)
+	if err != nil {
+		return nil, errors.WithMessage(err, "format of printf")
+	}
+	fmt.Printf(string(format), arr...)
 	return gendsl.Nil{}, nil
 }
 
@@ -160,7 +168,7 @@ func _awk(evalCtx *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value
 			if err != nil {
 				return nil, err
 			}
-			if ok.Unwrap().(bool) {
+			if gendsl.Truthy(ok) {
 				_, err := p.Then.EvalWithEnv(localEnv)
 				if err != nil {
 					return nil, err
@@ -199,13 +207,13 @@ func EvalAWK(script string, input io.Reader) error {
 //   - (PATTERN {pattern} {action}) is the match-then part that tells the MiniAWK to execute {action} when a line of the input matches {pattern} (when {pattern} returns true).
 //     More than one (PATTERN ...) expression is allowed and they will be executed one by one.
 //     What's more, you can refer each column in a line by "$1" for first column, "$2" for second column, "$n" for n columns and $0 for the whole line.
-//     In additional, a (match {regex_pattern} {column}) expression is provided for {pattern}, and (printf {format} {arg}...) for {action}.
+//     In additional, a (match {regexp} {column}) expression is provided for {pattern}, and (printf {format} {arg}...) for {action}.
 func ExampleEvalExprWithData() {
 	scripts := `
 (awk 
 	(BEGIN (printf "Language    FirstAppearAt\n" )) 
 	; find out those language that matches ".*C.*", print their name the first appear time.
-	(PATTERN (match ".*C.*" $1) (printf "%-8s    %s\n" $1 $3))  
+	(PATTERN (match #rx".*C.*" $1) (printf "%-8s    %s\n" $1 $3))  
 )
 	`
 
@@ -20,6 +20,7 @@ type (
 		node    *node32
 		evalCtx *EvalCtx
 		pc      *ParseContext
+		value   Value // value spliced by `,@` in a quasiquote, node is nil for it
 	}
 
 	// ExprType are the type of an expression before it got evaluated
@@ -34,6 +35,8 @@ const (
 	ExprTypeExpr ExprType = 1 << iota
 	ExprTypeIdentifier
 	ExprTypeLiteral
+	ExprTypeQuote         // 'x, `x, ,x or ,@x
+	ExprTypeInterpolation // #f"hello ${name}"
 )
 
 func (e ExprType) String() string {
@@ -44,6 +47,10 @@ func (e ExprType) String() string {
 		return "ExprIdentifier"
 	case ExprTypeLiteral:
 		return "ExprLiteral"
+	case ExprTypeQuote:
+		return "ExprQuote"
+	case ExprTypeInterpolation:
+		return "ExprInterpolation"
 	}
 
 	return "ExprUnknown"
@@ -53,16 +60,23 @@ func getExprType(valueNode *node32) ExprType {
 	switch valueNode.pegRule {
 	case ruleExpression:
 		return ExprTypeExpr
-	case ruleIdentifier:
+	case ruleIdentifier, ruleIdentifierAttr:
 		return ExprTypeIdentifier
 	case ruleLiteral:
 		return ExprTypeLiteral
+	case ruleQuote, ruleQuasiquote, ruleUnquote, ruleUnquoteSplicing:
+		return ExprTypeQuote
+	case ruleInterpolation:
+		return ExprTypeInterpolation
 	}
 
 	panic("unsupported value type: " + valueNode.pegRule.String())
 }
 
 func parseExpression(c *ParseContext, evalCtx *EvalCtx, node *node32) (any, error) {
+	if vs, ok := c.holes[node]; ok { // expanded from a macro call
+		return evalFilled(c, node, vs[0], evalCtx)
+	}
 	cur := node.up
 	cur = cur.next // ignore the LPAR
 	// assert(cur.pegRule != ruleOperator)
@@ -88,6 +102,10 @@ func parseExpression(c *ParseContext, evalCtx *EvalCtx, node *node32) (any, erro
 			continue
 		case ruleValue:
 			node := cur.up
+			if vs, ok := c.holes[node]; ok && node.pegRule == ruleUnquoteSplicing {
+				operands = appendSpliced(operands, c, evalCtx, vs)
+				continue
+			}
 			operands = append(operands, newExpr(c, evalCtx, node))
 		case ruleOption:
 			id, val, err := parseOption(c, evalCtx, cur)
@@ -100,6 +118,11 @@ func parseExpression(c *ParseContext, evalCtx *EvalCtx, node *node32) (any, erro
 			panic("invalid node in an expression")
 		}
 	}
+	if evalCtx.tracer != nil {
+		if err := c.traceCall(node, evalCtx, op, operands, options); err != nil {
+			return nil, err
+		}
+	}
 	return op.Eval(evalCtx, operands, options)
 }
 
@@ -129,12 +152,25 @@ func newExpr(c *ParseContext, evalCtx *EvalCtx, node *node32) Expr {
 
 // Text returns the raw text of the expression.
 func (e Expr) Text() string {
+	if e.node == nil {
+		if s, ok := e.value.(Symbol); ok {
+			return s.Name()
+		}
+		return Repr(e.value)
+	}
 	return e.pc.nodeText(e.node)
 }
 
 // Type returns the raw type of an expression,
-// which can only be an expression[(X Y Z)], an identifier or a literal.
+// which can only be an expression[(X Y Z)], an identifier, a literal, a quote or an interpolated string.
+// A value spliced by `,@` is a literal, except that a Symbol is an identifier.
 func (e Expr) Type() ExprType {
+	if e.node == nil {
+		if _, ok := e.value.(Symbol); ok {
+			return ExprTypeIdentifier
+		}
+		return ExprTypeLiteral
+	}
 	return getExprType(e.node)
 }
 
@@ -174,6 +210,17 @@ func (e Expr) EvalWithOptions(opt EvalOpt) (Value, error) {
 	if env != nil {
 		evalCtx = NewEvalCtx(evalCtx, evalCtx.UserData, env)
 	}
+	if node == nil {
+		s, ok := e.value.(Symbol)
+		if !ok {
+			return e.value, nil
+		}
+		v, ok := evalCtx.Lookup(s.Name())
+		if !ok {
+			return nil, &UnboundedIdentifierError{ID: s.Name()}
+		}
+		return v, nil
+	}
 	v, err := pc.parseNode(node, evalCtx)
 	if err != nil {
 		return nil, err
@@ -228,23 +275,41 @@ func CheckNArgs(nargs string, evalFn ProcedureFn) ProcedureFn {
 type EvalCtx struct {
 	parent   *EvalCtx // EvalCtx from the outter scope, nil for top level scope
 	env      *Env     // env for current scope
+	tracer   Tracer   // tracer inherited from the outter scope, nil to disable tracing
 	UserData any      // UserData that is used across the entire script evaluation
 }
 
 // NewEvalCtx creates a new EvalCtx with `p` as the output scope EvalCtx(nil is allowed),
 // `userData` is argument used across the whole evaluation,
 // `env` as the env for current scope evaluation, nil is allowed here and an empty env will be created for it.
+// The tracer of `p` is inherited.
 func NewEvalCtx(p *EvalCtx, userData any, env *Env) *EvalCtx {
 	if env == nil {
 		env = NewEnv()
 	}
+	var tracer Tracer
+	if p != nil {
+		tracer = p.tracer
+	}
 	return &EvalCtx{
 		parent:   p,
 		env:      env,
+		tracer:   tracer,
 		UserData: userData,
 	}
 }
 
+// WithTracer sets a tracer to observe the evaluation in this scope and the inner ones, nil to disable tracing.
+func (e *EvalCtx) WithTracer(t Tracer) *EvalCtx {
+	e.tracer = t
+	return e
+}
+
+// Tracer returns the tracer of this scope, nil if not set.
+func (e *EvalCtx) Tracer() Tracer {
+	return e.tracer
+}
+
 func (e *EvalCtx) Derive(newEnv *Env) *EvalCtx {
 	return NewEvalCtx(e, e.UserData, newEnv)
 }
@@ -270,6 +335,16 @@ func (e *EvalCtx) Lookup(id string) (Value, bool) {
 	return e.parent.Lookup(id)
 }
 
+// LookupKey looks up the identifier that `key` refers to in the current env and the outter scopes,
+// see [gendsl.Env.WithKey].
+func (e *EvalCtx) LookupKey(key Value) (Value, bool) {
+	id, ok := keyName(key)
+	if !ok {
+		return nil, false
+	}
+	return e.Lookup(id)
+}
+
 // OutScopeEvalCtx returns [gendsl.EvalCtx] from the outter scope.
 func (e *EvalCtx) OutScopeEvalCtx() *EvalCtx {
 	return e.parent
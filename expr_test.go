@@ -2,12 +2,15 @@ package gendsl
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega"
 	"github.com/pkg/errors"
 )
 
@@ -102,138 +105,159 @@ var _ = Describe("Expr", func() {
 		)
 
 		It("can eval a simple integer", func() {
-			Expect(evalFn("10")).Should(BeIdenticalTo(Int(10)))
-			Expect(evalFn("-10")).Should(BeIdenticalTo(Int(-10)))
+			gomega.Expect(evalFn("10")).Should(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("-10")).Should(gomega.BeIdenticalTo(Int(-10)))
 
-			Expect(evalFn("0x10")).Should(BeIdenticalTo(Int(16)))
-			Expect(evalFn("-0x10")).Should(BeIdenticalTo(Int(-16)))
+			gomega.Expect(evalFn("0x10")).Should(gomega.BeIdenticalTo(Int(16)))
+			gomega.Expect(evalFn("-0x10")).Should(gomega.BeIdenticalTo(Int(-16)))
 		})
 
 		It("can eval a simple integer with 'u' suffix as uint", func() {
-			Expect(evalFn("10u")).Should(BeIdenticalTo(Uint(10)))
-			Expect(evalFn("10U")).Should(BeIdenticalTo(Uint(10)))
+			gomega.Expect(evalFn("10u")).Should(gomega.BeIdenticalTo(Uint(10)))
+			gomega.Expect(evalFn("10U")).Should(gomega.BeIdenticalTo(Uint(10)))
 
-			Expect(evalFn("0x10U")).Should(BeIdenticalTo(Uint(16)))
-			Expect(evalFn("0x10u")).Should(BeIdenticalTo(Uint(16)))
+			gomega.Expect(evalFn("0x10U")).Should(gomega.BeIdenticalTo(Uint(16)))
+			gomega.Expect(evalFn("0x10u")).Should(gomega.BeIdenticalTo(Uint(16)))
 		})
 
 		It("can eval a simple integer with '.0' suffix as float", func() {
-			Expect(evalFn("10.0")).Should(BeIdenticalTo(Float(10)))
-			Expect(evalFn("10.0")).Should(BeIdenticalTo(Float(10)))
+			gomega.Expect(evalFn("10.0")).Should(gomega.BeIdenticalTo(Float(10)))
+			gomega.Expect(evalFn("10.0")).Should(gomega.BeIdenticalTo(Float(10)))
 		})
 
 		It("can eval a simple float", func() {
-			Expect(evalFn("0.")).Should(BeIdenticalTo(Float(0)))
-			Expect(evalFn("74.40")).Should(BeIdenticalTo(Float(74.40)))
-
-			Expect(evalFn("1.e+0")).Should(BeIdenticalTo(Float(1)))
-			Expect(evalFn("6.67428e-2")).Should(BeIdenticalTo(Float(0.0667428)))
-			Expect(evalFn("1E6")).Should(BeIdenticalTo(Float(1000000)))
-			Expect(evalFn(".25")).Should(BeIdenticalTo(Float(0.25)))
-			Expect(evalFn(".12345E+5")).Should(BeIdenticalTo(Float(12345)))
-			Expect(evalFn("0.15e+0_2")).Should(BeIdenticalTo(Float(15.0)))
+			gomega.Expect(evalFn("0.")).Should(gomega.BeIdenticalTo(Float(0)))
+			gomega.Expect(evalFn("74.40")).Should(gomega.BeIdenticalTo(Float(74.40)))
+
+			gomega.Expect(evalFn("1.e+0")).Should(gomega.BeIdenticalTo(Float(1)))
+			gomega.Expect(evalFn("6.67428e-2")).Should(gomega.BeIdenticalTo(Float(0.0667428)))
+			gomega.Expect(evalFn("1E6")).Should(gomega.BeIdenticalTo(Float(1000000)))
+			gomega.Expect(evalFn(".25")).Should(gomega.BeIdenticalTo(Float(0.25)))
+			gomega.Expect(evalFn(".12345E+5")).Should(gomega.BeIdenticalTo(Float(12345)))
+			gomega.Expect(evalFn("0.15e+0_2")).Should(gomega.BeIdenticalTo(Float(15.0)))
 		})
 
 		It("can eval a nil literal", func() {
-			Expect(evalFn("nil")).Should(BeIdenticalTo(Nil{}))
-			Expect(EvalExpr("nil", testEnv.Clone().WithInt("nil", 10))).Should(BeIdenticalTo(Nil{}))
+			gomega.Expect(evalFn("nil")).Should(gomega.BeIdenticalTo(Nil{}))
+			gomega.Expect(EvalExpr("nil", testEnv.Clone().WithInt("nil", 10))).Should(gomega.BeIdenticalTo(Nil{}))
 		})
 
 		It("can eval a simple string", func() {
-			Expect(evalFn(`"hello"`)).Should(BeIdenticalTo(String("hello")))
-			Expect(evalFn(`"你好"`)).Should(BeIdenticalTo(String("你好")))
+			gomega.Expect(evalFn(`"hello"`)).Should(gomega.BeIdenticalTo(String("hello")))
+			gomega.Expect(evalFn(`"你好"`)).Should(gomega.BeIdenticalTo(String("你好")))
+
+			gomega.Expect(evalFn(`"'"`)).Should(gomega.BeIdenticalTo(String(`'`)))
+			gomega.Expect(evalFn(`"\""`)).Should(gomega.BeIdenticalTo(String(`"`)))
+			gomega.Expect(evalFn(`"\n"`)).Should(gomega.BeIdenticalTo(String("\n")))
+			gomega.Expect(evalFn(`"\r"`)).Should(gomega.BeIdenticalTo(String("\r")))
+
+			gomega.Expect(evalFn(`"\xc3"`)).Should(gomega.BeIdenticalTo(String([]byte{0xc3})))
+			gomega.Expect(evalFn(`"\x61"`)).Should(gomega.BeIdenticalTo(String("a")))
+			gomega.Expect(evalFn(`"\u65e5本\U00008a9e"`)).Should(gomega.BeIdenticalTo(String("日本語")))
+			gomega.Expect(evalFn(`"\\"`)).Should(gomega.BeIdenticalTo(String(`\`)))
+			gomega.Expect(evalFn(`"\\\\"`)).Should(gomega.BeIdenticalTo(String(`\\`)))
+			gomega.Expect(evalFn(`"\\\\\n"`)).Should(gomega.BeIdenticalTo(String(`\\` + "\n")))
+			gomega.Expect(evalFn(`"\\\n\\\n"`)).Should(gomega.BeIdenticalTo(String(`\` + "\n" + `\` + "\n")))
+		})
 
-			Expect(evalFn(`"'"`)).Should(BeIdenticalTo(String(`'`)))
-			Expect(evalFn(`"\""`)).Should(BeIdenticalTo(String(`"`)))
-			Expect(evalFn(`"\n"`)).Should(BeIdenticalTo(String("\n")))
-			Expect(evalFn(`"\r"`)).Should(BeIdenticalTo(String("\r")))
+		It("can eval a long string", func() {
+			gomega.Expect(evalFn(`"""hello"""`)).Should(gomega.BeIdenticalTo(String("hello")))
+			gomega.Expect(evalFn(`"""你好"""`)).Should(gomega.BeIdenticalTo(String("你好")))
+			gomega.Expect(evalFn(`"""line
+ break"""`)).Should(gomega.BeIdenticalTo(String("line\n break")))
+			gomega.Expect(evalFn(`"""\n"""`)).Should(gomega.BeIdenticalTo(String(`\n`)))
+			gomega.Expect(evalFn(`"""\xc3"""`)).Should(gomega.BeIdenticalTo(String(`\xc3`)))
+			gomega.Expect(evalFn(`"""\\\"""`)).Should(gomega.BeIdenticalTo(String(`\\\`)))
+			gomega.Expect(evalFn(`"""''"""`)).Should(gomega.BeIdenticalTo(String(`''`)))
+			gomega.Expect(evalFn(`(ARRAY """""" "")`)).
+				Should(gomega.BeEquivalentTo(&UserData{[]Value{String(""), String("")}}))
+			gomega.Expect(evalFn(`(ARRAY "" """""" "")`)).
+				Should(gomega.BeEquivalentTo(&UserData{[]Value{String(""), String(""), String("")}}))
+		})
 
-			Expect(evalFn(`"\xc3"`)).Should(BeIdenticalTo(String([]byte{0xc3})))
-			Expect(evalFn(`"\x61"`)).Should(BeIdenticalTo(String("a")))
-			Expect(evalFn(`"\u65e5本\U00008a9e"`)).Should(BeIdenticalTo(String("日本語")))
-			Expect(evalFn(`"\\"`)).Should(BeIdenticalTo(String(`\`)))
-			Expect(evalFn(`"\\\\"`)).Should(BeIdenticalTo(String(`\\`)))
-			Expect(evalFn(`"\\\\\n"`)).Should(BeIdenticalTo(String(`\\` + "\n")))
-			Expect(evalFn(`"\\\n\\\n"`)).Should(BeIdenticalTo(String(`\` + "\n" + `\` + "\n")))
+		It("can eval a long string with quotes", func() {
+			gomega.Expect(evalFn(`"""{"name": "bob"}"""`)).Should(gomega.BeIdenticalTo(String(`{"name": "bob"}`)))
+			gomega.Expect(evalFn(`"""SELECT * FROM t WHERE name = "a" OR name = ''"""`)).
+				Should(gomega.BeIdenticalTo(String(`SELECT * FROM t WHERE name = "a" OR name = ''`)))
+			gomega.Expect(evalFn(`"""say "hi""""`)).Should(gomega.BeIdenticalTo(String(`say "hi"`)))
+			gomega.Expect(evalFn(`""""quoted"""""`)).Should(gomega.BeIdenticalTo(String(`"quoted""`)))
+			gomega.Expect(evalFn(`"""a""b"""`)).Should(gomega.BeIdenticalTo(String(`a""b`)))
+			gomega.Expect(evalFn(`(ARRAY """a"b""" """c""")`)).
+				Should(gomega.BeEquivalentTo(&UserData{[]Value{String(`a"b`), String("c")}}))
 		})
 
-		It("can eval a long string", func() {
-			Expect(evalFn(`"""hello"""`)).Should(BeIdenticalTo(String("hello")))
-			Expect(evalFn(`"""你好"""`)).Should(BeIdenticalTo(String("你好")))
-			Expect(evalFn(`"""line
- break"""`)).Should(BeIdenticalTo(String("line\n break")))
-			Expect(evalFn(`"""\n"""`)).Should(BeIdenticalTo(String(`\n`)))
-			Expect(evalFn(`"""\xc3"""`)).Should(BeIdenticalTo(String(`\xc3`)))
-			Expect(evalFn(`"""\\\"""`)).Should(BeIdenticalTo(String(`\\\`)))
-			Expect(evalFn(`"""''"""`)).Should(BeIdenticalTo(String(`''`)))
-			Expect(evalFn(`(ARRAY """""" "")`)).
-				Should(BeEquivalentTo(&UserData{[]Value{String(""), String("")}}))
-			Expect(evalFn(`(ARRAY "" """""" "")`)).
-				Should(BeEquivalentTo(&UserData{[]Value{String(""), String(""), String("")}}))
+		It("can eval a heredoc", func() {
+			gomega.Expect(evalFn("#h\"\"\"\n    SELECT *\n      FROM t\n\n    WHERE x = \"1\"\n    \"\"\"")).
+				Should(gomega.BeIdenticalTo(String("SELECT *\n  FROM t\n\nWHERE x = \"1\"\n")))
+			gomega.Expect(evalFn("#h\"\"\"\r\n\t\ta\r\n\t\t\tb\"\"\"")).Should(gomega.BeIdenticalTo(String("a\r\n\tb")))
+			gomega.Expect(evalFn("#h\"\"\"  a\n b\"\"\"")).Should(gomega.BeIdenticalTo(String(" a\nb")))
+			gomega.Expect(evalFn("#h\"\"\" \"\"\"")).Should(gomega.BeIdenticalTo(String("")))
+			gomega.Expect(evalFn("#h\"\"\"\"\"\"")).Should(gomega.BeIdenticalTo(String("")))
+			gomega.Expect(evalFn("#h\"\"\"\n  \\n \"x\"\n  \"\"\"")).Should(gomega.BeIdenticalTo(String(`\n "x"` + "\n")))
 		})
 	})
 
 	Describe("Expr", func() {
 		It("can accept no argument", func() {
-			Expect(EvalExpr(`(PLUS)`, testEnv)).Should(BeEquivalentTo(0))
+			gomega.Expect(EvalExpr(`(PLUS)`, testEnv)).Should(gomega.BeEquivalentTo(0))
 		})
 
 		It("can eval an simple expr with literals as argument", func() {
-			Expect(EvalExpr(`(RETURN 10)`, testEnv)).Should(BeEquivalentTo(10))
-			Expect(EvalExpr(`(PLUS 1 2 3)`, testEnv)).Should(BeEquivalentTo(6))
+			gomega.Expect(EvalExpr(`(RETURN 10)`, testEnv)).Should(gomega.BeEquivalentTo(10))
+			gomega.Expect(EvalExpr(`(PLUS 1 2 3)`, testEnv)).Should(gomega.BeEquivalentTo(6))
 		})
 
 		It("can eval an simple expr with expr as argument", func() {
-			Expect(EvalExpr(`(RETURN (PLUS 1 2 3))`, testEnv)).Should(BeEquivalentTo(6))
-			Expect(EvalExpr(`(RETURN (PLUS 1 2 (PLUS 1 2)))`, testEnv)).Should(BeEquivalentTo(6))
+			gomega.Expect(EvalExpr(`(RETURN (PLUS 1 2 3))`, testEnv)).Should(gomega.BeEquivalentTo(6))
+			gomega.Expect(EvalExpr(`(RETURN (PLUS 1 2 (PLUS 1 2)))`, testEnv)).Should(gomega.BeEquivalentTo(6))
 		})
 
 		It("can check the expr type for arguments", func() {
 			check := func(evalCtx *EvalCtx,
 				args []Expr,
 				options map[string]Value) (Value, error) {
-				Expect(args[0].Type()).Should(BeEquivalentTo(ExprTypeLiteral))
-				Expect(args[1].Type()).Should(BeEquivalentTo(ExprTypeExpr))
-				Expect(args[2].Type()).Should(BeEquivalentTo(ExprTypeIdentifier))
+				gomega.Expect(args[0].Type()).Should(gomega.BeEquivalentTo(ExprTypeLiteral))
+				gomega.Expect(args[1].Type()).Should(gomega.BeEquivalentTo(ExprTypeExpr))
+				gomega.Expect(args[2].Type()).Should(gomega.BeEquivalentTo(ExprTypeIdentifier))
 				return Nil{}, nil
 			}
 			env := testEnv.Clone().WithProcedure("CHECK", Procedure{Eval: check}).
 				WithInt("bar", 10)
-			Expect(extractErr2(EvalExpr, `(CHECK "foo" (RETURN 1) bar)`, env)).
-				Should(BeNil())
+			gomega.Expect(extractErr2(EvalExpr, `(CHECK "foo" (RETURN 1) bar)`, env)).
+				Should(gomega.BeNil())
 		})
 
 		Describe("argument check", func() {
 			It("can check extract count of argument", func() {
 				var env = NewEnv().
 					WithProcedure("PLUS", Procedure{Eval: CheckNArgs("2", _plus)})
-				Expect(extractErr2(EvalExpr, `(PLUS 1 2 3)`, env)).Should(MatchError("expecting 2 argument(s), but got 3"))
-				Expect(EvalExpr(`(PLUS 1 2)`, env)).Should(BeEquivalentTo(3))
+				gomega.Expect(extractErr2(EvalExpr, `(PLUS 1 2 3)`, env)).Should(gomega.MatchError("expecting 2 argument(s), but got 3"))
+				gomega.Expect(EvalExpr(`(PLUS 1 2)`, env)).Should(gomega.BeEquivalentTo(3))
 
 			})
 
 			It("can check one or more argument", func() {
 				env := NewEnv().
 					WithProcedure("PLUS", Procedure{Eval: CheckNArgs("+", _plus)})
-				Expect(extractErr2(EvalExpr, `(PLUS )`, env)).Should(MatchError("expecting one or more argument, but got 0"))
-				Expect(EvalExpr(`(PLUS 1 2)`, env)).Should(BeEquivalentTo(3))
-				Expect(EvalExpr(`(PLUS 1)`, env)).Should(BeEquivalentTo(1))
+				gomega.Expect(extractErr2(EvalExpr, `(PLUS )`, env)).Should(gomega.MatchError("expecting one or more argument, but got 0"))
+				gomega.Expect(EvalExpr(`(PLUS 1 2)`, env)).Should(gomega.BeEquivalentTo(3))
+				gomega.Expect(EvalExpr(`(PLUS 1)`, env)).Should(gomega.BeEquivalentTo(1))
 			})
 
 			It("can check no or more argument", func() {
 				env := NewEnv().
 					WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)})
-				Expect(EvalExpr(`(PLUS )`, env)).Should(BeEquivalentTo(0))
-				Expect(EvalExpr(`(PLUS 1)`, env)).Should(BeEquivalentTo(1))
-				Expect(EvalExpr(`(PLUS 1 2)`, env)).Should(BeEquivalentTo(3))
+				gomega.Expect(EvalExpr(`(PLUS )`, env)).Should(gomega.BeEquivalentTo(0))
+				gomega.Expect(EvalExpr(`(PLUS 1)`, env)).Should(gomega.BeEquivalentTo(1))
+				gomega.Expect(EvalExpr(`(PLUS 1 2)`, env)).Should(gomega.BeEquivalentTo(3))
 			})
 
 			It("can check one or no argument", func() {
 				env := NewEnv().
 					WithProcedure("PLUS", Procedure{Eval: CheckNArgs("?", _plus)})
-				Expect(extractErr2(EvalExpr, `(PLUS 1 2 3)`, env)).Should(MatchError("expecting one or no argument, but got 3"))
-				Expect(EvalExpr(`(PLUS 1)`, env)).Should(BeEquivalentTo(1))
-				Expect(EvalExpr(`(PLUS)`, env)).Should(BeEquivalentTo(0))
+				gomega.Expect(extractErr2(EvalExpr, `(PLUS 1 2 3)`, env)).Should(gomega.MatchError("expecting one or no argument, but got 3"))
+				gomega.Expect(EvalExpr(`(PLUS 1)`, env)).Should(gomega.BeEquivalentTo(1))
+				gomega.Expect(EvalExpr(`(PLUS)`, env)).Should(gomega.BeEquivalentTo(0))
 			})
 
 		})
@@ -251,7 +275,7 @@ var _ = Describe("Expr", func() {
 		})
 
 		It("can declare literal option in a procedure", func() {
-			Expect(EvalExpr(`(OPTIONS #:foo "bar" #:one 1)`, env)).Should(BeEquivalentTo(&UserData{
+			gomega.Expect(EvalExpr(`(OPTIONS #:foo "bar" #:one 1)`, env)).Should(gomega.BeEquivalentTo(&UserData{
 				V: map[string]Value{
 					"foo": String("bar"),
 					"one": Int(1),
@@ -261,7 +285,7 @@ var _ = Describe("Expr", func() {
 
 		It("can declare value env in the option in a procedure", func() {
 			e := env.Clone().WithInt("ONE", 1)
-			Expect(EvalExpr(`(OPTIONS #:foo "bar" #:one ONE)`, e)).Should(BeEquivalentTo(&UserData{
+			gomega.Expect(EvalExpr(`(OPTIONS #:foo "bar" #:one ONE)`, e)).Should(gomega.BeEquivalentTo(&UserData{
 				V: map[string]Value{
 					"foo": String("bar"),
 					"one": Int(1),
@@ -286,17 +310,176 @@ var _ = Describe("Expr", func() {
 			}
 			// only allow two values
 			env = testEnv.Clone().WithProcedure("PLUS_N", p)
-			Expect(EvalExpr(`(PLUS_N #:N 2 10 20)`, env)).Should(BeIdenticalTo(Int(30)))
-			Expect(EvalExpr(`(PLUS_N #:N 2 (RETURN 10) 20)`, env)).Should(BeIdenticalTo(Int(30)))
-			Expect(EvalExpr(`(PLUS_N #:N 2 10 20 30 40 50)`, env)).Should(BeIdenticalTo(Int(30)))
-			Expect(EvalExpr(`(PLUS_N 10 20 #:N 2 30 40 50)`, env)).Should(BeIdenticalTo(Int(30)))
+			gomega.Expect(EvalExpr(`(PLUS_N #:N 2 10 20)`, env)).Should(gomega.BeIdenticalTo(Int(30)))
+			gomega.Expect(EvalExpr(`(PLUS_N #:N 2 (RETURN 10) 20)`, env)).Should(gomega.BeIdenticalTo(Int(30)))
+			gomega.Expect(EvalExpr(`(PLUS_N #:N 2 10 20 30 40 50)`, env)).Should(gomega.BeIdenticalTo(Int(30)))
+			gomega.Expect(EvalExpr(`(PLUS_N 10 20 #:N 2 30 40 50)`, env)).Should(gomega.BeIdenticalTo(Int(30)))
 		})
 
 		It("cannot use an procedure expression in option", func() {
-			Expect(extractErr2(EvalExpr, `(OPTIONS #:foo "bar" #:one (RETURN 1))`, env)).
-				Should(MatchError(ContainSubstring("parse error")))
+			gomega.Expect(extractErr2(EvalExpr, `(OPTIONS #:foo "bar" #:one (RETURN 1))`, env)).
+				Should(gomega.MatchError(gomega.ContainSubstring("parse error")))
+		})
+
+	})
+
+	Describe("Node", func() {
+		It("can inspect the syntax tree", func() {
+			pc, err := MakeParseContext("(PLUS #:n 1 foo.bar ; comment\n \"x\")")
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+			root := pc.Root()
+			gomega.Expect(root.Type()).Should(gomega.Equal(ExprTypeExpr))
+			gomega.Expect(root.Operator().Identifier()).Should(gomega.Equal("PLUS"))
+			gomega.Expect(root.Options()).Should(gomega.HaveLen(1))
+			gomega.Expect(root.Options()[0].Name).Should(gomega.Equal("n"))
+			gomega.Expect(root.Options()[0].Value.Literal()).Should(gomega.Equal(Int(1)))
+
+			args := root.Args()
+			gomega.Expect(args).Should(gomega.HaveLen(2))
+			gomega.Expect(args[0].Type()).Should(gomega.Equal(ExprTypeIdentifier))
+			gomega.Expect(args[0].Identifier()).Should(gomega.Equal("foo"))
+			gomega.Expect(args[0].Path()).Should(gomega.Equal([]string{"bar"}))
+			gomega.Expect(args[0].Text()).Should(gomega.Equal("foo.bar"))
+			gomega.Expect(args[0].Range()).Should(gomega.Equal(Range{
+				Begin: Position{Offset: 12, Line: 1, Symbol: 13},
+				End:   Position{Offset: 19, Line: 1, Symbol: 20},
+			}))
+			gomega.Expect(args[1].Literal()).Should(gomega.Equal(String("x")))
+			gomega.Expect(args[1].Range().Begin.Line).Should(gomega.Equal(2))
+
+			gomega.Expect(pc.Comments()).Should(gomega.Equal([]Comment{{
+				Text: "; comment",
+				Range: Range{
+					Begin: Position{Offset: 20, Line: 1, Symbol: 21},
+					End:   Position{Offset: 29, Line: 2, Symbol: 0},
+				},
+			}}))
+
+			visited := make([]string, 0)
+			Inspect(root, func(n Node) bool {
+				visited = append(visited, n.Text())
+				return true
+			})
+			gomega.Expect(visited).Should(gomega.Equal([]string{root.Text(), "PLUS", "1", "foo.bar", `"x"`}))
+		})
+
+		It("ignores the comments after an identifier", func() {
+			gomega.Expect(EvalExpr("(RETURN ; comment\n 1)", testEnv)).Should(gomega.Equal(Int(1)))
+		})
+
+		It("skips the block comments and the datum comments", func() {
+			for script, want := range map[string]Value{
+				"#| head |# (RETURN 1)":                           Int(1),
+				"(RETURN #| a\n #| nested |# (b |# 1)":            Int(1),
+				"(RETURN #;(PLUS 1\n  2) 3)":                      Int(3),
+				"(RETURN 1 #;2)":                                  Int(1),
+				"(PLUS 1 #; #;2 3 4)":                             Int(5),
+				"(PLUS #;x 1 #;'(x ,y) 2 #;#rx\"(\" #;`(x ,z) 3)": Int(6),
+			} {
+				gomega.Expect(EvalExpr(script, testEnv)).Should(gomega.Equal(want), script)
+			}
+			for _, script := range []string{"(RETURN #| 1)", "(RETURN 1 #;)", "(RETURN |# 1)"} {
+				_, err := EvalExpr(script, testEnv)
+				gomega.Expect(err).Should(gomega.HaveOccurred(), script)
+			}
+		})
+
+		It("keeps the block comments and the datum comments", func() {
+			pc, err := MakeParseContext("(PLUS #| a #| b |# |# 1 #;(PLUS 2 ; two\n 3) ; after\n #;4)")
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			texts := make([]string, 0)
+			for _, c := range pc.Comments() {
+				texts = append(texts, c.Text)
+			}
+			gomega.Expect(texts).Should(gomega.Equal([]string{"#| a #| b |# |#", "#;(PLUS 2 ; two\n 3)", "; after", "#;4"}))
+			gomega.Expect(pc.Comments()[1].Range).Should(gomega.Equal(Range{
+				Begin: Position{Offset: 24, Line: 1, Symbol: 25},
+				End:   Position{Offset: 43, Line: 2, Symbol: 4},
+			}))
+			gomega.Expect(pc.Root().Args()).Should(gomega.HaveLen(1))
+		})
+
+		It("reports the position of a syntax error", func() {
+			_, err := MakeParseContext("(RETURN\n 1")
+			var se *SyntaxError
+			gomega.Expect(errors.As(err, &se)).Should(gomega.BeTrue())
+			gomega.Expect(se.BeginLine).Should(gomega.Equal(2))
+		})
+	})
+
+	Describe("Repr", func() {
+		It("can print literal values that can be parsed back", func() {
+			for _, v := range []Value{Int(-10), Uint(10), Float(1), Float(0.25), Float(1e100),
+				String("a\"b\n"), Bool(true), Bool(false), Nil{}} {
+				gomega.Expect(EvalExpr(Repr(v), testEnv)).Should(gomega.BeIdenticalTo(v))
+			}
+		})
+
+		It("can print non-literal values", func() {
+			gomega.Expect(Repr(Procedure{Eval: _plus})).Should(gomega.Equal("#<procedure>"))
+			gomega.Expect(Repr(&UserData{V: 1})).Should(gomega.Equal("#<userdata 1>"))
+		})
+	})
+
+	Describe("Tracer", func() {
+		eval := func(script string, t Tracer) (Value, error) {
+			pc, err := MakeParseContext(script)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			return pc.Eval(NewEvalCtx(nil, nil, testEnv).WithTracer(t))
+		}
+
+		It("can trace the nodes in the evaluation", func() {
+			t := &recordTracer{}
+			gomega.Expect(eval(`(DEFINE "foo" 1 (PLUS foo 2))`, t)).Should(gomega.Equal(Int(3)))
+			gomega.Expect(t.events).Should(gomega.Equal([]string{
+				"enter DEFINE", "enter \"foo\"", "exit \"foo\" = \"foo\"", "enter 1", "exit 1 = 1",
+				"enter PLUS", "enter foo", "exit foo = 1", "enter 2", "exit 2 = 2", "exit PLUS = 3",
+				"exit DEFINE = 3",
+			}))
+			gomega.Expect(t.exits[0].Kind).Should(gomega.Equal(ExprTypeLiteral))
+			gomega.Expect(t.exits[2].Kind).Should(gomega.Equal(ExprTypeIdentifier))
+			gomega.Expect(t.exits[2].Range.Begin.Symbol).Should(gomega.Equal(23))
+			gomega.Expect(t.exits[5].Duration).Should(gomega.BeNumerically(">", 0))
+		})
+
+		It("can trace the errors", func() {
+			t := &recordTracer{}
+			_, err := eval(`(PLUS 1 bar)`, t)
+			gomega.Expect(err).Should(gomega.HaveOccurred())
+			gomega.Expect(t.exits).Should(gomega.HaveLen(3))
+			gomega.Expect(t.exits[1].Err).Should(gomega.BeAssignableToTypeOf(&UnboundedIdentifierError{}))
+			gomega.Expect(t.exits[2].Err).Should(gomega.HaveOccurred())
+			gomega.Expect(t.exits[2].Value).Should(gomega.BeNil())
 		})
 
+		It("can write an indented text trace", func() {
+			buf := &strings.Builder{}
+			gomega.Expect(eval(`(PLUS 1 (PLUS 2 3))`, NewTextTracer(buf))).Should(gomega.Equal(Int(6)))
+			trace := regexp.MustCompile(` \(.+?\)\n`).ReplaceAllString(buf.String(), "\n")
+			gomega.Expect(trace).Should(gomega.Equal(`-> (PLUS 1 (PLUS 2 3)) 1:1
+  1 = 1
+  -> (PLUS 2 3) 1:9
+    2 = 2
+    3 = 3
+  <- PLUS = 5
+<- PLUS = 6
+`))
+		})
+
+		It("can write a JSON lines trace", func() {
+			buf := &strings.Builder{}
+			gomega.Expect(eval(`(PLUS 1)`, NewJSONTracer(buf))).Should(gomega.Equal(Int(1)))
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			gomega.Expect(lines).Should(gomega.HaveLen(4))
+
+			var ev map[string]any
+			gomega.Expect(json.Unmarshal([]byte(lines[2]), &ev)).Should(gomega.Succeed())
+			gomega.Expect(ev).Should(gomega.HaveKeyWithValue("event", "exit"))
+			gomega.Expect(ev).Should(gomega.HaveKeyWithValue("depth", gomega.BeNumerically("==", 1)))
+			gomega.Expect(ev).Should(gomega.HaveKeyWithValue("kind", "ExprLiteral"))
+			gomega.Expect(ev).Should(gomega.HaveKeyWithValue("value", "1"))
+		})
 	})
 
 	Describe("env", func() {
@@ -310,48 +493,48 @@ var _ = Describe("Expr", func() {
 		)
 
 		It("can evaluate an identifier", func() {
-			Expect(evalFn("foo", "foo", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("FOO", "FOO", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("@foo", "@foo", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("$foo", "$foo", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("$$", "$$", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("$-", "$-", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("@-", "@-", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("$%", "$%", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("^%", "^%", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("#f", "", Int(10))).To(BeIdenticalTo(Bool(false)))
-			Expect(evalFn("#t", "", Int(10))).To(BeIdenticalTo(Bool(true)))
-			Expect(evalFn("<>", "<>", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("|>", "|>", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("_?|>", "_?|>", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("(RETURN $foo)", "$foo", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("(RETURN $$ )", "$$", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("(RETURN _foo)", "_foo", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("(RETURN foo_)", "foo_", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("(RETURN foo-bar)", "foo-bar", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("(RETURN !q@w$r%t^y&u*u)", "!q@w$r%t^y&u*u", Int(10))).To(BeIdenticalTo(Int(10)))
-			Expect(evalFn("(RETURN !@$%^&*)", "!@$%^&*", Int(10))).To(BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("foo", "foo", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("FOO", "FOO", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("@foo", "@foo", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("$foo", "$foo", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("$$", "$$", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("$-", "$-", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("@-", "@-", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("$%", "$%", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("^%", "^%", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("#f", "", Int(10))).To(gomega.BeIdenticalTo(Bool(false)))
+			gomega.Expect(evalFn("#t", "", Int(10))).To(gomega.BeIdenticalTo(Bool(true)))
+			gomega.Expect(evalFn("<>", "<>", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("|>", "|>", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("_?|>", "_?|>", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("(RETURN $foo)", "$foo", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("(RETURN $$ )", "$$", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("(RETURN _foo)", "_foo", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("(RETURN foo_)", "foo_", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("(RETURN foo-bar)", "foo-bar", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("(RETURN !q@w$r%t^y&u*u)", "!q@w$r%t^y&u*u", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
+			gomega.Expect(evalFn("(RETURN !@$%^&*)", "!@$%^&*", Int(10))).To(gomega.BeIdenticalTo(Int(10)))
 		})
 
 		It("can get attributes of an identifier", func() {
-			Expect(evalFn("foo.bar", "foo", &UserData{
+			gomega.Expect(evalFn("foo.bar", "foo", &UserData{
 				V: Map{"bar": Int(10)},
-			})).To(BeIdenticalTo(Int(10)))
+			})).To(gomega.BeIdenticalTo(Int(10)))
 
-			Expect(evalFn("foo.bar.$eww", "foo", &UserData{
+			gomega.Expect(evalFn("foo.bar.$eww", "foo", &UserData{
 				V: Map{"bar": &UserData{V: Map{"$eww": Int(10)}}},
-			})).To(BeIdenticalTo(Int(10)))
+			})).To(gomega.BeIdenticalTo(Int(10)))
 
 			_, err := evalFn("foo.bar.$error", "foo", &UserData{
 				V: Map{"bar": &UserData{V: Map{"$eww": Int(10)}}},
 			})
-			Expect(err).To(MatchError(ContainSubstring("index($error) not found")))
+			gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("index($error) not found")))
 		})
 
 		It("can refer to a value with its id", func() {
 			env := NewEnv().WithInt("FOO", Int(10)).
 				WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)})
-			Expect(EvalExpr("(PLUS FOO 10)", env)).Should(BeEquivalentTo(20))
+			gomega.Expect(EvalExpr("(PLUS FOO 10)", env)).Should(gomega.BeEquivalentTo(20))
 		})
 
 		It("can refer to a value with its id in its parent's env", func() {
@@ -363,7 +546,7 @@ var _ = Describe("Expr", func() {
 				(PLUS FOO BAR)
 			)
 			`
-			Expect(EvalExpr(script, env)).Should(BeEquivalentTo(20))
+			gomega.Expect(EvalExpr(script, env)).Should(gomega.BeEquivalentTo(20))
 
 			// look up from grandparent's env
 			script = `
@@ -372,7 +555,7 @@ var _ = Describe("Expr", func() {
 			    (PLUS FOO BAR ONE))
 			)
 			`
-			Expect(EvalExpr(script, env)).Should(BeEquivalentTo(21))
+			gomega.Expect(EvalExpr(script, env)).Should(gomega.BeEquivalentTo(21))
 		})
 
 		It("can set an value to an env in a procedure", func() {
@@ -380,7 +563,7 @@ var _ = Describe("Expr", func() {
 			(DEFINE "foo" 20
 				(RETURN foo))
 			`
-			Expect(EvalExpr(expr, testEnv)).Should(BeEquivalentTo(20))
+			gomega.Expect(EvalExpr(expr, testEnv)).Should(gomega.BeEquivalentTo(20))
 		})
 
 		It("can throw unbounded variable error outside its scope", func() {
@@ -392,9 +575,9 @@ var _ = Describe("Expr", func() {
 			)
 			`
 			err := extractErr2(EvalExpr, expr, testEnv)
-			if Expect(err).ShouldNot(BeNil()) {
-				Expect(err).Should(BeAssignableToTypeOf(&UnboundedIdentifierError{}))
-				Expect(err).Should(MatchError(ContainSubstring("unbounded")))
+			if gomega.Expect(err).ShouldNot(gomega.BeNil()) {
+				gomega.Expect(err).Should(gomega.BeAssignableToTypeOf(&UnboundedIdentifierError{}))
+				gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("unbounded")))
 			}
 		})
 
@@ -404,11 +587,26 @@ var _ = Describe("Expr", func() {
 				(DEFINE "foo" "bar" (RETURN foo))
 			)
 			`
-			Expect(EvalExpr(expr, testEnv)).Should(BeEquivalentTo(String("bar")))
+			gomega.Expect(EvalExpr(expr, testEnv)).Should(gomega.BeEquivalentTo(String("bar")))
 		})
 	})
 })
 
+// recordTracer records the names of the nodes and the values they produce.
+type recordTracer struct {
+	events []string
+	exits  []TraceEvent
+}
+
+func (t *recordTracer) EnterNode(ev TraceEvent) {
+	t.events = append(t.events, "enter "+ev.Name)
+}
+
+func (t *recordTracer) ExitNode(ev TraceEvent) {
+	t.events = append(t.events, "exit "+ev.Name+" = "+traceValue(ev.Value))
+	t.exits = append(t.exits, ev)
+}
+
 func extractErr2[X, Y, T any](fn func(X, Y) (T, error), x X, y Y) error {
 	_, err := fn(x, y)
 	return err
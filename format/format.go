@@ -0,0 +1,233 @@
+// Package format implements the standard formatting of scripts.
+//
+// An expression is printed in one line if it fits in the line width,
+// otherwise its operator stays after the '(' and each of its options and arguments
+// is printed in a new line with two more spaces of indentation:
+//
+//	(json
+//	  (kv "language" (array "c" "c++"))
+//	  (kv "typing" "static")) ; comment
+//
+// Comments are kept, a comment that follows an element in the same line stays in that line.
+package format
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ccbhj/gendsl"
+)
+
+// LineWidth is the width that a line is expected to fit in.
+const LineWidth = 80
+
+// Source formats the script `src`.
+// An [gendsl.SyntaxError] is returned if `src` cannot be parsed.
+func Source(src string) (string, error) {
+	pc, err := gendsl.MakeParseContext(src)
+	if err != nil {
+		return "", err
+	}
+
+	text := []rune(src)
+	p := &printer{
+		comments:   pc.Comments(),
+		lineStarts: []int{0},
+		lineStart:  true,
+	}
+	for i, r := range text {
+		if r == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+
+	root := pc.Root()
+	p.node(root, 0)
+	p.trailingComment(root.Range().End.Offset, len(text))
+	p.ownLineComments(len(text)+1, 0)
+	return strings.TrimRight(p.buf.String(), " \n") + "\n", nil
+}
+
+type printer struct {
+	buf        strings.Builder
+	col        int
+	comments   []gendsl.Comment // comments that are not printed yet
+	lineStarts []int
+	inComment  bool // whether the current line ends with a comment
+	lineStart  bool // whether nothing but indentation is written in the current line
+}
+
+// element is an operator, option or argument of an expression.
+type element struct {
+	begin, end int
+	print      func(p *printer, indent int)
+	flat       func() (string, bool)
+}
+
+func (p *printer) write(s string) {
+	p.buf.WriteString(s)
+	p.lineStart = false
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		p.col = len([]rune(s[i+1:]))
+	} else {
+		p.col += len([]rune(s))
+	}
+}
+
+func (p *printer) newline(indent int) {
+	p.buf.WriteByte('\n')
+	p.buf.WriteString(strings.Repeat(" ", indent))
+	p.col = indent
+	p.inComment = false
+	p.lineStart = true
+}
+
+func (p *printer) line(offset int) int {
+	return sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > offset })
+}
+
+// ownLineComments prints the comments before `offset` in their own lines.
+func (p *printer) ownLineComments(offset int, indent int) {
+	for len(p.comments) > 0 && p.comments[0].Range.Begin.Offset < offset {
+		if !p.lineStart {
+			p.newline(indent)
+		}
+		p.write(p.comments[0].Text)
+		p.comments = p.comments[1:]
+		p.inComment = true
+	}
+}
+
+// trailingComment prints the comment before `limit` that in the same line as the text ended at `end`.
+func (p *printer) trailingComment(end, limit int) {
+	if len(p.comments) == 0 {
+		return
+	}
+	c := p.comments[0]
+	if c.Range.Begin.Offset >= limit || p.line(c.Range.Begin.Offset) != p.line(end-1) {
+		return
+	}
+	p.write(" " + c.Text)
+	p.comments = p.comments[1:]
+	p.inComment = true
+}
+
+func (p *printer) hasComments(begin, end int) bool {
+	for _, c := range p.comments {
+		if c.Range.Begin.Offset >= begin && c.Range.Begin.Offset < end {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *printer) node(n gendsl.Node, indent int) {
+	p.ownLineComments(n.Range().Begin.Offset, indent)
+	if p.inComment {
+		p.newline(indent)
+	}
+	if n.Type() == gendsl.ExprTypeQuote {
+		prefix := quotePrefix(n)
+		p.write(prefix)
+		p.node(n.Quoted(), indent+len(prefix))
+		return
+	}
+	if n.Type() != gendsl.ExprTypeExpr {
+		p.write(n.Text())
+		return
+	}
+	if flat, ok := p.flat(n); ok && p.col+len([]rune(flat)) <= LineWidth {
+		p.write(flat)
+		return
+	}
+
+	elems := p.elements(n)
+	end := n.Range().End.Offset
+	p.write("(")
+	for i, e := range elems {
+		if i > 0 {
+			p.ownLineComments(e.begin, indent+2)
+			if !p.lineStart {
+				p.newline(indent + 2)
+			}
+		}
+		e.print(p, indent+2)
+		limit := end
+		if i+1 < len(elems) {
+			limit = elems[i+1].begin
+		}
+		p.trailingComment(e.end, limit)
+	}
+	p.ownLineComments(end, indent+2)
+	if p.inComment {
+		p.newline(indent)
+	}
+	p.write(")")
+}
+
+// flat returns the text of n in one line, it reports false if n cannot be printed in one line.
+func (p *printer) flat(n gendsl.Node) (string, bool) {
+	r := n.Range()
+	if p.hasComments(r.Begin.Offset, r.End.Offset) {
+		return "", false
+	}
+	if n.Type() == gendsl.ExprTypeQuote {
+		s, ok := p.flat(n.Quoted())
+		return quotePrefix(n) + s, ok
+	}
+	if n.Type() != gendsl.ExprTypeExpr {
+		return n.Text(), !strings.ContainsAny(n.Text(), "\r\n")
+	}
+
+	elems := p.elements(n)
+	texts := make([]string, 0, len(elems))
+	for _, e := range elems {
+		s, ok := e.flat()
+		if !ok {
+			return "", false
+		}
+		texts = append(texts, s)
+	}
+	return "(" + strings.Join(texts, " ") + ")", true
+}
+
+func (p *printer) elements(n gendsl.Node) []element {
+	op := n.Operator()
+	ret := []element{p.nodeElement(op)}
+	for _, opt := range n.Options() {
+		opt := opt
+		ret = append(ret, element{
+			begin: opt.Range.Begin.Offset,
+			end:   opt.Range.End.Offset,
+			print: func(p *printer, indent int) {
+				p.write("#:" + opt.Name + " " + opt.Value.Text())
+			},
+			flat: func() (string, bool) {
+				return "#:" + opt.Name + " " + opt.Value.Text(), !strings.ContainsAny(opt.Value.Text(), "\r\n")
+			},
+		})
+	}
+	for _, arg := range n.Args() {
+		ret = append(ret, p.nodeElement(arg))
+	}
+	sort.SliceStable(ret[1:], func(i, j int) bool {
+		return ret[i+1].begin < ret[j+1].begin
+	})
+	return ret
+}
+
+func (p *printer) nodeElement(n gendsl.Node) element {
+	r := n.Range()
+	return element{
+		begin: r.Begin.Offset,
+		end:   r.End.Offset,
+		print: func(p *printer, indent int) { p.node(n, indent) },
+		flat:  func() (string, bool) { return p.flat(n) },
+	}
+}
+
+// quotePrefix returns the ', `, , or ,@ of a quote without the spaces after it.
+func quotePrefix(n gendsl.Node) string {
+	text := []rune(n.Text())
+	return strings.TrimSpace(string(text[:n.Quoted().Range().Begin.Offset-n.Range().Begin.Offset]))
+}
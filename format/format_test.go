@@ -0,0 +1,84 @@
+package format
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+)
+
+var _ = Describe("Source", func() {
+	It("can print a short expression in one line", func() {
+		Expect(Source("  (PLUS   1\n 2 #:n   3\n foo.bar)")).Should(Equal("(PLUS 1 2 #:n 3 foo.bar)\n"))
+		Expect(Source(`"foo"`)).Should(Equal("\"foo\"\n"))
+	})
+
+	It("can break a long expression into lines", func() {
+		src := `(json (kv "language" (array "c" "c++" "javascript" "elixir" "python" "ruby")) (kv "typing" "static"))`
+		Expect(Source(src)).Should(Equal(`(json
+  (kv "language" (array "c" "c++" "javascript" "elixir" "python" "ruby"))
+  (kv "typing" "static"))
+`))
+	})
+
+	It("can keep the comments", func() {
+		src := `; head
+(json ; after operator
+ (if (later-than $NOW 2012) ; condition
+   (kv "language" "go")) ; else
+ ; own line
+ (kv "typing" "static")
+ ; before close
+ ) ; tail
+`
+		Expect(Source(src)).Should(Equal(`; head
+(json ; after operator
+  (if
+    (later-than $NOW 2012) ; condition
+    (kv "language" "go")) ; else
+  ; own line
+  (kv "typing" "static")
+  ; before close
+) ; tail
+`))
+	})
+
+	It("can keep the block comments and the datum comments", func() {
+		src := "#| head |#\n(json #;(kv \"a\" 1)\n (kv \"typing\" #| inline |# \"static\"))"
+		Expect(Source(src)).Should(Equal(`#| head |#
+(json #;(kv "a" 1)
+  (kv
+    "typing" #| inline |#
+    "static"))
+`))
+	})
+
+	It("can print the quotes", func() {
+		Expect(Source("(eval ' ( PLUS  1 ,x   ,@xs))")).Should(Equal("(eval '(PLUS 1 ,x ,@xs))\n"))
+		src := "`(json (kv \"language\" (array \"c\" \"c++\" \"javascript\" \"elixir\" \"python\")) ,(kv \"typing\" typing))"
+		Expect(Source(src)).Should(Equal("`(json\n" +
+			"   (kv \"language\" (array \"c\" \"c++\" \"javascript\" \"elixir\" \"python\"))\n" +
+			"   ,(kv \"typing\" typing))\n"))
+	})
+
+	It("returns the same script when formatting a formatted one", func() {
+		for _, src := range []string{
+			"(a #:b \"\"\"x\ny\"\"\" c)",
+			"(a ; c1\n ; c2\n b (c d ; c3\n))\n",
+			"(a '(b ; c1\n c) `x)\n",
+			"(a #| c1\n #| c2 |# |# b #;(c\n d) e)\n",
+		} {
+			once, err := Source(src)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(Source(once)).Should(Equal(once))
+			Expect(strings.Count(once, ";")).Should(Equal(strings.Count(src, ";")))
+		}
+	})
+
+	It("reports syntax errors", func() {
+		_, err := Source("(a")
+		Expect(err).Should(BeAssignableToTypeOf(&gendsl.SyntaxError{}))
+	})
+})
@@ -23,7 +23,16 @@ const (
 	ruleOperator
 	ruleOption
 	ruleValue
+	ruleQuote
+	ruleQuasiquote
+	ruleUnquoteSplicing
+	ruleUnquote
+	ruleInterpolation
+	ruleInterpolationHole
+	ruleInterpolationChar
 	ruleSpacing
+	ruleBlockComment
+	ruleDatumComment
 	ruleIdentifier
 	ruleIdentifierPrefix
 	ruleIdentifierChar
@@ -32,6 +41,14 @@ const (
 	ruleLiteral
 	ruleNilLiteral
 	ruleBoolLiteral
+	ruleKeywordLiteral
+	ruleTimeLiteral
+	ruleDurationLiteral
+	ruleDurationUnit
+	ruleBytesLiteral
+	ruleRegexpLiteral
+	ruleDecimalLiteral
+	ruleBigIntLiteral
 	ruleFloatLiteral
 	ruleExponent
 	ruleIntegerLiteral
@@ -60,7 +77,16 @@ var rul3s = [...]string{
 	"Operator",
 	"Option",
 	"Value",
+	"Quote",
+	"Quasiquote",
+	"UnquoteSplicing",
+	"Unquote",
+	"Interpolation",
+	"InterpolationHole",
+	"InterpolationChar",
 	"Spacing",
+	"BlockComment",
+	"DatumComment",
 	"Identifier",
 	"IdentifierPrefix",
 	"IdentifierChar",
@@ -69,6 +95,14 @@ var rul3s = [...]string{
 	"Literal",
 	"NilLiteral",
 	"BoolLiteral",
+	"KeywordLiteral",
+	"TimeLiteral",
+	"DurationLiteral",
+	"DurationUnit",
+	"BytesLiteral",
+	"RegexpLiteral",
+	"DecimalLiteral",
+	"BigIntLiteral",
 	"FloatLiteral",
 	"Exponent",
 	"IntegerLiteral",
@@ -202,7 +236,7 @@ func (t *tokens32) Tokens() []token32 {
 type parser struct {
 	Buffer string
 	buffer []rune
-	rules  [34]func() bool
+	rules  [51]func() bool
 	parse  func(rule ...int) error
 	reset  func()
 	Pretty bool
@@ -416,7 +450,7 @@ func (p *parser) Init(options ...func(*parser) error) error {
 		nil,
 		/* 3 Option <- <('#' ':' Identifier (Literal / Identifier) Spacing)> */
 		nil,
-		/* 4 Value <- <((Expression / Literal / IdentifierAttr / Identifier) Spacing)> */
+		/* 4 Value <- <((UnquoteSplicing / Expression / Interpolation / Literal / IdentifierAttr / ((&(',') Unquote) | (&('`') Quasiquote) | (&('\'') Quote) | (&('!' | '$' | '%' | '&' | '*' | '<' | '>' | '?' | '@' | 'A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z' | '^' | '_' | 'a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z' | '|' | '~') Identifier))) Spacing)> */
 		func() bool {
 			position7, tokenIndex7 := position, tokenIndex
 			{
@@ -425,152 +459,332 @@ func (p *parser) Init(options ...func(*parser) error) error {
 					position9, tokenIndex9 := position, tokenIndex
 					{
 						position11 := position
+						if buffer[position] != rune(',') {
+							goto l10
+						}
+						position++
+						if buffer[position] != rune('@') {
+							goto l10
+						}
+						position++
+						if !_rules[ruleValue]() {
+							goto l10
+						}
+						add(ruleUnquoteSplicing, position11)
+					}
+					goto l9
+				l10:
+					position, tokenIndex = position9, tokenIndex9
+					{
+						position13 := position
 						{
-							position12 := position
+							position14 := position
 							if !_rules[ruleSpacing]() {
-								goto l10
+								goto l12
 							}
 							if buffer[position] != rune('(') {
-								goto l10
+								goto l12
 							}
 							position++
 							if !_rules[ruleSpacing]() {
-								goto l10
+								goto l12
 							}
-							add(ruleLPAR, position12)
+							add(ruleLPAR, position14)
 						}
 						{
-							position13 := position
+							position15 := position
 							if !_rules[ruleIdentifier]() {
-								goto l10
+								goto l12
 							}
 							{
-								position14, tokenIndex14 := position, tokenIndex
+								position16, tokenIndex16 := position, tokenIndex
 								if !_rules[ruleSpacing]() {
-									goto l14
+									goto l16
 								}
-								goto l15
-							l14:
-								position, tokenIndex = position14, tokenIndex14
+								goto l17
+							l16:
+								position, tokenIndex = position16, tokenIndex16
 							}
-						l15:
-							add(ruleOperator, position13)
+						l17:
+							add(ruleOperator, position15)
 						}
-					l16:
+					l18:
 						{
-							position17, tokenIndex17 := position, tokenIndex
+							position19, tokenIndex19 := position, tokenIndex
 							{
-								position18, tokenIndex18 := position, tokenIndex
+								position20, tokenIndex20 := position, tokenIndex
 								{
-									position20 := position
+									position22 := position
 									if buffer[position] != rune('#') {
-										goto l19
+										goto l21
 									}
 									position++
 									if buffer[position] != rune(':') {
-										goto l19
+										goto l21
 									}
 									position++
 									if !_rules[ruleIdentifier]() {
-										goto l19
+										goto l21
 									}
 									{
-										position21, tokenIndex21 := position, tokenIndex
+										position23, tokenIndex23 := position, tokenIndex
 										if !_rules[ruleLiteral]() {
-											goto l22
+											goto l24
 										}
-										goto l21
-									l22:
-										position, tokenIndex = position21, tokenIndex21
+										goto l23
+									l24:
+										position, tokenIndex = position23, tokenIndex23
 										if !_rules[ruleIdentifier]() {
-											goto l19
+											goto l21
 										}
 									}
-								l21:
+								l23:
 									if !_rules[ruleSpacing]() {
-										goto l19
+										goto l21
 									}
-									add(ruleOption, position20)
+									add(ruleOption, position22)
 								}
-								goto l18
-							l19:
-								position, tokenIndex = position18, tokenIndex18
+								goto l20
+							l21:
+								position, tokenIndex = position20, tokenIndex20
 								if !_rules[ruleValue]() {
-									goto l17
+									goto l19
 								}
 							}
-						l18:
-							goto l16
-						l17:
-							position, tokenIndex = position17, tokenIndex17
+						l20:
+							goto l18
+						l19:
+							position, tokenIndex = position19, tokenIndex19
 						}
 						{
-							position23 := position
+							position25 := position
 							if !_rules[ruleSpacing]() {
-								goto l10
+								goto l12
 							}
 							if buffer[position] != rune(')') {
-								goto l10
+								goto l12
 							}
 							position++
 							if !_rules[ruleSpacing]() {
-								goto l10
+								goto l12
 							}
-							add(ruleRPAR, position23)
+							add(ruleRPAR, position25)
 						}
-						add(ruleExpression, position11)
+						add(ruleExpression, position13)
 					}
 					goto l9
-				l10:
+				l12:
+					position, tokenIndex = position9, tokenIndex9
+					{
+						position27 := position
+						if buffer[position] != rune('#') {
+							goto l26
+						}
+						position++
+						if buffer[position] != rune('f') {
+							goto l26
+						}
+						position++
+						if buffer[position] != rune('"') {
+							goto l26
+						}
+						position++
+					l28:
+						{
+							position29, tokenIndex29 := position, tokenIndex
+							{
+								position30, tokenIndex30 := position, tokenIndex
+								{
+									position32 := position
+									if buffer[position] != rune('$') {
+										goto l31
+									}
+									position++
+									if buffer[position] != rune('{') {
+										goto l31
+									}
+									position++
+									if !_rules[ruleSpacing]() {
+										goto l31
+									}
+									if !_rules[ruleValue]() {
+										goto l31
+									}
+									if buffer[position] != rune('}') {
+										goto l31
+									}
+									position++
+									add(ruleInterpolationHole, position32)
+								}
+								goto l30
+							l31:
+								position, tokenIndex = position30, tokenIndex30
+								{
+									position33 := position
+									{
+										position34, tokenIndex34 := position, tokenIndex
+										if buffer[position] != rune('\\') {
+											goto l35
+										}
+										position++
+										if !matchDot() {
+											goto l35
+										}
+										goto l34
+									l35:
+										position, tokenIndex = position34, tokenIndex34
+										{
+											position36, tokenIndex36 := position, tokenIndex
+											{
+												switch buffer[position] {
+												case '\\':
+													if buffer[position] != rune('\\') {
+														goto l36
+													}
+													position++
+												case '\n':
+													if buffer[position] != rune('\n') {
+														goto l36
+													}
+													position++
+												default:
+													if buffer[position] != rune('"') {
+														goto l36
+													}
+													position++
+												}
+											}
+
+											goto l29
+										l36:
+											position, tokenIndex = position36, tokenIndex36
+										}
+										{
+											position38, tokenIndex38 := position, tokenIndex
+											if buffer[position] != rune('$') {
+												goto l38
+											}
+											position++
+											if buffer[position] != rune('{') {
+												goto l38
+											}
+											position++
+											goto l29
+										l38:
+											position, tokenIndex = position38, tokenIndex38
+										}
+										if !matchDot() {
+											goto l29
+										}
+									}
+								l34:
+									add(ruleInterpolationChar, position33)
+								}
+							}
+						l30:
+							goto l28
+						l29:
+							position, tokenIndex = position29, tokenIndex29
+						}
+						if buffer[position] != rune('"') {
+							goto l26
+						}
+						position++
+						add(ruleInterpolation, position27)
+					}
+					goto l9
+				l26:
 					position, tokenIndex = position9, tokenIndex9
 					if !_rules[ruleLiteral]() {
-						goto l24
+						goto l39
 					}
 					goto l9
-				l24:
+				l39:
 					position, tokenIndex = position9, tokenIndex9
 					{
-						position26 := position
+						position41 := position
 						if !_rules[ruleIdentifier]() {
-							goto l25
+							goto l40
 						}
 						{
-							position29 := position
+							position44 := position
 							if buffer[position] != rune('.') {
-								goto l25
+								goto l40
 							}
 							position++
 							if !_rules[ruleIdentifier]() {
-								goto l25
+								goto l40
 							}
-							add(ruleAttrPath, position29)
+							add(ruleAttrPath, position44)
 						}
-					l27:
+					l42:
 						{
-							position28, tokenIndex28 := position, tokenIndex
+							position43, tokenIndex43 := position, tokenIndex
 							{
-								position30 := position
+								position45 := position
 								if buffer[position] != rune('.') {
-									goto l28
+									goto l43
 								}
 								position++
 								if !_rules[ruleIdentifier]() {
-									goto l28
+									goto l43
 								}
-								add(ruleAttrPath, position30)
+								add(ruleAttrPath, position45)
 							}
-							goto l27
-						l28:
-							position, tokenIndex = position28, tokenIndex28
+							goto l42
+						l43:
+							position, tokenIndex = position43, tokenIndex43
 						}
-						add(ruleIdentifierAttr, position26)
+						add(ruleIdentifierAttr, position41)
 					}
 					goto l9
-				l25:
+				l40:
 					position, tokenIndex = position9, tokenIndex9
-					if !_rules[ruleIdentifier]() {
-						goto l7
+					{
+						switch buffer[position] {
+						case ',':
+							{
+								position47 := position
+								if buffer[position] != rune(',') {
+									goto l7
+								}
+								position++
+								if !_rules[ruleValue]() {
+									goto l7
+								}
+								add(ruleUnquote, position47)
+							}
+						case '`':
+							{
+								position48 := position
+								if buffer[position] != rune('`') {
+									goto l7
+								}
+								position++
+								if !_rules[ruleValue]() {
+									goto l7
+								}
+								add(ruleQuasiquote, position48)
+							}
+						case '\'':
+							{
+								position49 := position
+								if buffer[position] != rune('\'') {
+									goto l7
+								}
+								position++
+								if !_rules[ruleValue]() {
+									goto l7
+								}
+								add(ruleQuote, position49)
+							}
+						default:
+							if !_rules[ruleIdentifier]() {
+								goto l7
+							}
+						}
 					}
+
 				}
 			l9:
 				if !_rules[ruleSpacing]() {
@@ -583,1158 +797,1927 @@ func (p *parser) Init(options ...func(*parser) error) error {
 			position, tokenIndex = position7, tokenIndex7
 			return false
 		},
-		/* 5 Spacing <- <(((&('\n') '\n') | (&('\r') '\r') | (&('\t') '\t') | (&(' ') ' '))+ / (';' (!('\r' / '\n') .)* ('\r' / '\n')))*> */
+		/* 5 Quote <- <('\'' Value)> */
+		nil,
+		/* 6 Quasiquote <- <('`' Value)> */
+		nil,
+		/* 7 UnquoteSplicing <- <(',' '@' Value)> */
+		nil,
+		/* 8 Unquote <- <(',' Value)> */
+		nil,
+		/* 9 Interpolation <- <('#' 'f' '"' (InterpolationHole / InterpolationChar)* '"')> */
+		nil,
+		/* 10 InterpolationHole <- <('$' '{' Spacing Value '}')> */
+		nil,
+		/* 11 InterpolationChar <- <(('\\' .) / (!((&('\\') '\\') | (&('\n') '\n') | (&('"') '"')) !('$' '{') .))> */
+		nil,
+		/* 12 Spacing <- <(BlockComment / ((&('#') DatumComment) | (&(';') (';' (!('\r' / '\n') .)* ('\r' / '\n'))) | (&('\t' | '\n' | '\r' | ' ') ((&('\n') '\n') | (&('\r') '\r') | (&('\t') '\t') | (&(' ') ' '))+)))*> */
 		func() bool {
 			{
-				position32 := position
-			l33:
+				position58 := position
+			l59:
 				{
-					position34, tokenIndex34 := position, tokenIndex
+					position60, tokenIndex60 := position, tokenIndex
 					{
-						position35, tokenIndex35 := position, tokenIndex
-						{
-							switch buffer[position] {
-							case '\n':
-								if buffer[position] != rune('\n') {
-									goto l36
-								}
-								position++
-							case '\r':
-								if buffer[position] != rune('\r') {
-									goto l36
-								}
-								position++
-							case '\t':
-								if buffer[position] != rune('\t') {
-									goto l36
-								}
-								position++
-							default:
-								if buffer[position] != rune(' ') {
-									goto l36
-								}
-								position++
-							}
+						position61, tokenIndex61 := position, tokenIndex
+						if !_rules[ruleBlockComment]() {
+							goto l62
 						}
-
-					l37:
+						goto l61
+					l62:
+						position, tokenIndex = position61, tokenIndex61
 						{
-							position38, tokenIndex38 := position, tokenIndex
-							{
-								switch buffer[position] {
-								case '\n':
-									if buffer[position] != rune('\n') {
-										goto l38
+							switch buffer[position] {
+							case '#':
+								{
+									position64 := position
+									if buffer[position] != rune('#') {
+										goto l60
 									}
 									position++
-								case '\r':
-									if buffer[position] != rune('\r') {
-										goto l38
+									if buffer[position] != rune(';') {
+										goto l60
 									}
 									position++
-								case '\t':
-									if buffer[position] != rune('\t') {
-										goto l38
+									if !_rules[ruleSpacing]() {
+										goto l60
 									}
-									position++
-								default:
-									if buffer[position] != rune(' ') {
-										goto l38
+									if !_rules[ruleValue]() {
+										goto l60
 									}
-									position++
+									add(ruleDatumComment, position64)
 								}
-							}
-
-							goto l37
-						l38:
-							position, tokenIndex = position38, tokenIndex38
-						}
-						goto l35
-					l36:
-						position, tokenIndex = position35, tokenIndex35
-						if buffer[position] != rune(';') {
-							goto l34
-						}
-						position++
-					l41:
-						{
-							position42, tokenIndex42 := position, tokenIndex
-							{
-								position43, tokenIndex43 := position, tokenIndex
+							case ';':
+								if buffer[position] != rune(';') {
+									goto l60
+								}
+								position++
+							l65:
 								{
-									position44, tokenIndex44 := position, tokenIndex
+									position66, tokenIndex66 := position, tokenIndex
+									{
+										position67, tokenIndex67 := position, tokenIndex
+										{
+											position68, tokenIndex68 := position, tokenIndex
+											if buffer[position] != rune('\r') {
+												goto l69
+											}
+											position++
+											goto l68
+										l69:
+											position, tokenIndex = position68, tokenIndex68
+											if buffer[position] != rune('\n') {
+												goto l67
+											}
+											position++
+										}
+									l68:
+										goto l66
+									l67:
+										position, tokenIndex = position67, tokenIndex67
+									}
+									if !matchDot() {
+										goto l66
+									}
+									goto l65
+								l66:
+									position, tokenIndex = position66, tokenIndex66
+								}
+								{
+									position70, tokenIndex70 := position, tokenIndex
 									if buffer[position] != rune('\r') {
-										goto l45
+										goto l71
 									}
 									position++
-									goto l44
-								l45:
-									position, tokenIndex = position44, tokenIndex44
+									goto l70
+								l71:
+									position, tokenIndex = position70, tokenIndex70
 									if buffer[position] != rune('\n') {
-										goto l43
+										goto l60
 									}
 									position++
 								}
-							l44:
-								goto l42
-							l43:
-								position, tokenIndex = position43, tokenIndex43
-							}
-							if !matchDot() {
-								goto l42
+							l70:
+								break
+							default:
+								{
+									switch buffer[position] {
+									case '\n':
+										if buffer[position] != rune('\n') {
+											goto l60
+										}
+										position++
+									case '\r':
+										if buffer[position] != rune('\r') {
+											goto l60
+										}
+										position++
+									case '\t':
+										if buffer[position] != rune('\t') {
+											goto l60
+										}
+										position++
+									default:
+										if buffer[position] != rune(' ') {
+											goto l60
+										}
+										position++
+									}
+								}
+
+							l72:
+								{
+									position73, tokenIndex73 := position, tokenIndex
+									{
+										switch buffer[position] {
+										case '\n':
+											if buffer[position] != rune('\n') {
+												goto l73
+											}
+											position++
+										case '\r':
+											if buffer[position] != rune('\r') {
+												goto l73
+											}
+											position++
+										case '\t':
+											if buffer[position] != rune('\t') {
+												goto l73
+											}
+											position++
+										default:
+											if buffer[position] != rune(' ') {
+												goto l73
+											}
+											position++
+										}
+									}
+
+									goto l72
+								l73:
+									position, tokenIndex = position73, tokenIndex73
+								}
 							}
-							goto l41
-						l42:
-							position, tokenIndex = position42, tokenIndex42
 						}
+
+					}
+				l61:
+					goto l59
+				l60:
+					position, tokenIndex = position60, tokenIndex60
+				}
+				add(ruleSpacing, position58)
+			}
+			return true
+		},
+		/* 13 BlockComment <- <('#' '|' (BlockComment / (!('|' '#') .))* ('|' '#'))> */
+		func() bool {
+			position76, tokenIndex76 := position, tokenIndex
+			{
+				position77 := position
+				if buffer[position] != rune('#') {
+					goto l76
+				}
+				position++
+				if buffer[position] != rune('|') {
+					goto l76
+				}
+				position++
+			l78:
+				{
+					position79, tokenIndex79 := position, tokenIndex
+					{
+						position80, tokenIndex80 := position, tokenIndex
+						if !_rules[ruleBlockComment]() {
+							goto l81
+						}
+						goto l80
+					l81:
+						position, tokenIndex = position80, tokenIndex80
 						{
-							position46, tokenIndex46 := position, tokenIndex
-							if buffer[position] != rune('\r') {
-								goto l47
+							position82, tokenIndex82 := position, tokenIndex
+							if buffer[position] != rune('|') {
+								goto l82
 							}
 							position++
-							goto l46
-						l47:
-							position, tokenIndex = position46, tokenIndex46
-							if buffer[position] != rune('\n') {
-								goto l34
+							if buffer[position] != rune('#') {
+								goto l82
 							}
 							position++
+							goto l79
+						l82:
+							position, tokenIndex = position82, tokenIndex82
+						}
+						if !matchDot() {
+							goto l79
 						}
-					l46:
 					}
-				l35:
-					goto l33
-				l34:
-					position, tokenIndex = position34, tokenIndex34
+				l80:
+					goto l78
+				l79:
+					position, tokenIndex = position79, tokenIndex79
+				}
+				if buffer[position] != rune('|') {
+					goto l76
 				}
-				add(ruleSpacing, position32)
+				position++
+				if buffer[position] != rune('#') {
+					goto l76
+				}
+				position++
+				add(ruleBlockComment, position77)
 			}
 			return true
+		l76:
+			position, tokenIndex = position76, tokenIndex76
+			return false
 		},
-		/* 6 Identifier <- <(!BoolLiteral IdentifierPrefix IdentifierChar* Spacing)> */
+		/* 14 DatumComment <- <('#' ';' Spacing Value)> */
+		nil,
+		/* 15 Identifier <- <(!BoolLiteral IdentifierPrefix IdentifierChar* Spacing)> */
 		func() bool {
-			position48, tokenIndex48 := position, tokenIndex
+			position84, tokenIndex84 := position, tokenIndex
 			{
-				position49 := position
+				position85 := position
 				{
-					position50, tokenIndex50 := position, tokenIndex
+					position86, tokenIndex86 := position, tokenIndex
 					if !_rules[ruleBoolLiteral]() {
-						goto l50
+						goto l86
 					}
-					goto l48
-				l50:
-					position, tokenIndex = position50, tokenIndex50
+					goto l84
+				l86:
+					position, tokenIndex = position86, tokenIndex86
 				}
 				{
-					position51 := position
+					position87 := position
 					{
-						position52, tokenIndex52 := position, tokenIndex
+						position88, tokenIndex88 := position, tokenIndex
 						{
-							position54 := position
+							position90 := position
 							{
 								switch buffer[position] {
 								case '_':
 									if buffer[position] != rune('_') {
-										goto l53
+										goto l89
 									}
 									position++
 								case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 									if c := buffer[position]; c < rune('A') || c > rune('Z') {
-										goto l53
+										goto l89
 									}
 									position++
 								default:
 									if c := buffer[position]; c < rune('a') || c > rune('z') {
-										goto l53
+										goto l89
 									}
 									position++
 								}
 							}
 
-							add(ruleLetter, position54)
+							add(ruleLetter, position90)
 						}
-						goto l52
-					l53:
-						position, tokenIndex = position52, tokenIndex52
+						goto l88
+					l89:
+						position, tokenIndex = position88, tokenIndex88
 						{
 							switch buffer[position] {
 							case '>':
 								if buffer[position] != rune('>') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '<':
 								if buffer[position] != rune('<') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '|':
 								if buffer[position] != rune('|') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '?':
 								if buffer[position] != rune('?') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '_':
 								if buffer[position] != rune('_') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '*':
 								if buffer[position] != rune('*') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '&':
 								if buffer[position] != rune('&') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '^':
 								if buffer[position] != rune('^') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '%':
 								if buffer[position] != rune('%') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '$':
 								if buffer[position] != rune('$') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '@':
 								if buffer[position] != rune('@') {
-									goto l48
+									goto l84
 								}
 								position++
 							case '!':
 								if buffer[position] != rune('!') {
-									goto l48
+									goto l84
 								}
 								position++
 							default:
 								if buffer[position] != rune('~') {
-									goto l48
+									goto l84
 								}
 								position++
 							}
 						}
 
 					}
-				l52:
-					add(ruleIdentifierPrefix, position51)
+				l88:
+					add(ruleIdentifierPrefix, position87)
+				}
+			l93:
+				{
+					position94, tokenIndex94 := position, tokenIndex
+					if !_rules[ruleIdentifierChar]() {
+						goto l94
+					}
+					goto l93
+				l94:
+					position, tokenIndex = position94, tokenIndex94
+				}
+				if !_rules[ruleSpacing]() {
+					goto l84
+				}
+				add(ruleIdentifier, position85)
+			}
+			return true
+		l84:
+			position, tokenIndex = position84, tokenIndex84
+			return false
+		},
+		/* 16 IdentifierPrefix <- <(Letter / ((&('>') '>') | (&('<') '<') | (&('|') '|') | (&('?') '?') | (&('_') '_') | (&('*') '*') | (&('&') '&') | (&('^') '^') | (&('%') '%') | (&('$') '$') | (&('@') '@') | (&('!') '!') | (&('~') '~')))> */
+		nil,
+		/* 17 IdentifierChar <- <(LetterOrDigit / ((&('>') '>') | (&('<') '<') | (&('|') '|') | (&('?') '?') | (&('_') '_') | (&('*') '*') | (&('&') '&') | (&('^') '^') | (&('%') '%') | (&('$') '$') | (&('@') '@') | (&('!') '!') | (&('~') '~')) / '-')> */
+		func() bool {
+			position96, tokenIndex96 := position, tokenIndex
+			{
+				position97 := position
+				{
+					position98, tokenIndex98 := position, tokenIndex
+					if !_rules[ruleLetterOrDigit]() {
+						goto l99
+					}
+					goto l98
+				l99:
+					position, tokenIndex = position98, tokenIndex98
+					{
+						switch buffer[position] {
+						case '>':
+							if buffer[position] != rune('>') {
+								goto l100
+							}
+							position++
+						case '<':
+							if buffer[position] != rune('<') {
+								goto l100
+							}
+							position++
+						case '|':
+							if buffer[position] != rune('|') {
+								goto l100
+							}
+							position++
+						case '?':
+							if buffer[position] != rune('?') {
+								goto l100
+							}
+							position++
+						case '_':
+							if buffer[position] != rune('_') {
+								goto l100
+							}
+							position++
+						case '*':
+							if buffer[position] != rune('*') {
+								goto l100
+							}
+							position++
+						case '&':
+							if buffer[position] != rune('&') {
+								goto l100
+							}
+							position++
+						case '^':
+							if buffer[position] != rune('^') {
+								goto l100
+							}
+							position++
+						case '%':
+							if buffer[position] != rune('%') {
+								goto l100
+							}
+							position++
+						case '$':
+							if buffer[position] != rune('$') {
+								goto l100
+							}
+							position++
+						case '@':
+							if buffer[position] != rune('@') {
+								goto l100
+							}
+							position++
+						case '!':
+							if buffer[position] != rune('!') {
+								goto l100
+							}
+							position++
+						default:
+							if buffer[position] != rune('~') {
+								goto l100
+							}
+							position++
+						}
+					}
+
+					goto l98
+				l100:
+					position, tokenIndex = position98, tokenIndex98
+					if buffer[position] != rune('-') {
+						goto l96
+					}
+					position++
 				}
-			l57:
+			l98:
+				add(ruleIdentifierChar, position97)
+			}
+			return true
+		l96:
+			position, tokenIndex = position96, tokenIndex96
+			return false
+		},
+		/* 18 IdentifierAttr <- <(Identifier AttrPath+)> */
+		nil,
+		/* 19 AttrPath <- <('.' Identifier)> */
+		nil,
+		/* 20 Literal <- <((TimeLiteral / DurationLiteral / BytesLiteral / RegexpLiteral / DecimalLiteral / BigIntLiteral / FloatLiteral / LongStringLiteral / ((&('#') BoolLiteral) | (&('"') StringLiteral) | (&(':') KeywordLiteral) | (&('n') NilLiteral) | (&('+' | '-' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') IntegerLiteral))) Spacing)> */
+		func() bool {
+			position104, tokenIndex104 := position, tokenIndex
+			{
+				position105 := position
 				{
-					position58, tokenIndex58 := position, tokenIndex
+					position106, tokenIndex106 := position, tokenIndex
+					{
+						position108 := position
+						if buffer[position] != rune('#') {
+							goto l107
+						}
+						position++
+						if buffer[position] != rune('i') {
+							goto l107
+						}
+						position++
+						if buffer[position] != rune('n') {
+							goto l107
+						}
+						position++
+						if buffer[position] != rune('s') {
+							goto l107
+						}
+						position++
+						if buffer[position] != rune('t') {
+							goto l107
+						}
+						position++
+						if !_rules[ruleSpacing]() {
+							goto l107
+						}
+						if !_rules[ruleStringLiteral]() {
+							goto l107
+						}
+						add(ruleTimeLiteral, position108)
+					}
+					goto l106
+				l107:
+					position, tokenIndex = position106, tokenIndex106
 					{
-						position59 := position
+						position110 := position
 						{
-							position60, tokenIndex60 := position, tokenIndex
-							if !_rules[ruleLetterOrDigit]() {
-								goto l61
+							position111, tokenIndex111 := position, tokenIndex
+							{
+								position113, tokenIndex113 := position, tokenIndex
+								if buffer[position] != rune('+') {
+									goto l114
+								}
+								position++
+								goto l113
+							l114:
+								position, tokenIndex = position113, tokenIndex113
+								if buffer[position] != rune('-') {
+									goto l111
+								}
+								position++
+							}
+						l113:
+							goto l112
+						l111:
+							position, tokenIndex = position111, tokenIndex111
+						}
+					l112:
+						if !_rules[ruleDigits]() {
+							goto l109
+						}
+						{
+							position117, tokenIndex117 := position, tokenIndex
+							if buffer[position] != rune('.') {
+								goto l117
+							}
+							position++
+							if !_rules[ruleDigits]() {
+								goto l117
 							}
-							goto l60
-						l61:
-							position, tokenIndex = position60, tokenIndex60
 							{
-								switch buffer[position] {
-								case '>':
-									if buffer[position] != rune('>') {
-										goto l62
-									}
-									position++
-								case '<':
-									if buffer[position] != rune('<') {
-										goto l62
-									}
-									position++
-								case '|':
-									if buffer[position] != rune('|') {
-										goto l62
-									}
-									position++
-								case '?':
-									if buffer[position] != rune('?') {
-										goto l62
-									}
-									position++
-								case '_':
-									if buffer[position] != rune('_') {
-										goto l62
-									}
-									position++
-								case '*':
-									if buffer[position] != rune('*') {
-										goto l62
-									}
-									position++
-								case '&':
-									if buffer[position] != rune('&') {
-										goto l62
-									}
-									position++
-								case '^':
-									if buffer[position] != rune('^') {
-										goto l62
+								position119, tokenIndex119 := position, tokenIndex
+								if buffer[position] != rune('m') {
+									goto l119
+								}
+								position++
+								{
+									position120, tokenIndex120 := position, tokenIndex
+									if buffer[position] != rune('s') {
+										goto l120
 									}
 									position++
-								case '%':
-									if buffer[position] != rune('%') {
-										goto l62
+									goto l119
+								l120:
+									position, tokenIndex = position120, tokenIndex120
+								}
+								goto l117
+							l119:
+								position, tokenIndex = position119, tokenIndex119
+							}
+							goto l118
+						l117:
+							position, tokenIndex = position117, tokenIndex117
+						}
+					l118:
+						if !_rules[ruleDurationUnit]() {
+							goto l109
+						}
+					l115:
+						{
+							position116, tokenIndex116 := position, tokenIndex
+							if !_rules[ruleDigits]() {
+								goto l116
+							}
+							{
+								position121, tokenIndex121 := position, tokenIndex
+								if buffer[position] != rune('.') {
+									goto l121
+								}
+								position++
+								if !_rules[ruleDigits]() {
+									goto l121
+								}
+								{
+									position123, tokenIndex123 := position, tokenIndex
+									if buffer[position] != rune('m') {
+										goto l123
 									}
 									position++
-								case '$':
-									if buffer[position] != rune('$') {
-										goto l62
+									{
+										position124, tokenIndex124 := position, tokenIndex
+										if buffer[position] != rune('s') {
+											goto l124
+										}
+										position++
+										goto l123
+									l124:
+										position, tokenIndex = position124, tokenIndex124
 									}
-									position++
-								case '@':
-									if buffer[position] != rune('@') {
-										goto l62
+									goto l121
+								l123:
+									position, tokenIndex = position123, tokenIndex123
+								}
+								goto l122
+							l121:
+								position, tokenIndex = position121, tokenIndex121
+							}
+						l122:
+							if !_rules[ruleDurationUnit]() {
+								goto l116
+							}
+							goto l115
+						l116:
+							position, tokenIndex = position116, tokenIndex116
+						}
+						{
+							position125, tokenIndex125 := position, tokenIndex
+							if !_rules[ruleIdentifierChar]() {
+								goto l125
+							}
+							goto l109
+						l125:
+							position, tokenIndex = position125, tokenIndex125
+						}
+						add(ruleDurationLiteral, position110)
+					}
+					goto l106
+				l109:
+					position, tokenIndex = position106, tokenIndex106
+					{
+						position127 := position
+						if buffer[position] != rune('#') {
+							goto l126
+						}
+						position++
+						{
+							position128, tokenIndex128 := position, tokenIndex
+							if buffer[position] != rune('x') {
+								goto l129
+							}
+							position++
+							goto l128
+						l129:
+							position, tokenIndex = position128, tokenIndex128
+							if buffer[position] != rune('b') {
+								goto l126
+							}
+							position++
+							if buffer[position] != rune('6') {
+								goto l126
+							}
+							position++
+							if buffer[position] != rune('4') {
+								goto l126
+							}
+							position++
+						}
+					l128:
+						if buffer[position] != rune('"') {
+							goto l126
+						}
+						position++
+					l130:
+						{
+							position131, tokenIndex131 := position, tokenIndex
+							{
+								position132, tokenIndex132 := position, tokenIndex
+								if buffer[position] != rune('"') {
+									goto l132
+								}
+								position++
+								goto l131
+							l132:
+								position, tokenIndex = position132, tokenIndex132
+							}
+							if !matchDot() {
+								goto l131
+							}
+							goto l130
+						l131:
+							position, tokenIndex = position131, tokenIndex131
+						}
+						if buffer[position] != rune('"') {
+							goto l126
+						}
+						position++
+						add(ruleBytesLiteral, position127)
+					}
+					goto l106
+				l126:
+					position, tokenIndex = position106, tokenIndex106
+					{
+						position134 := position
+						if buffer[position] != rune('#') {
+							goto l133
+						}
+						position++
+						if buffer[position] != rune('r') {
+							goto l133
+						}
+						position++
+						if buffer[position] != rune('x') {
+							goto l133
+						}
+						position++
+						if buffer[position] != rune('"') {
+							goto l133
+						}
+						position++
+					l135:
+						{
+							position136, tokenIndex136 := position, tokenIndex
+							{
+								position137, tokenIndex137 := position, tokenIndex
+								if buffer[position] != rune('\\') {
+									goto l138
+								}
+								position++
+								if !matchDot() {
+									goto l138
+								}
+								goto l137
+							l138:
+								position, tokenIndex = position137, tokenIndex137
+								{
+									position139, tokenIndex139 := position, tokenIndex
+									if buffer[position] != rune('"') {
+										goto l139
 									}
 									position++
-								case '!':
-									if buffer[position] != rune('!') {
-										goto l62
+									goto l136
+								l139:
+									position, tokenIndex = position139, tokenIndex139
+								}
+								if !matchDot() {
+									goto l136
+								}
+							}
+						l137:
+							goto l135
+						l136:
+							position, tokenIndex = position136, tokenIndex136
+						}
+						if buffer[position] != rune('"') {
+							goto l133
+						}
+						position++
+						add(ruleRegexpLiteral, position134)
+					}
+					goto l106
+				l133:
+					position, tokenIndex = position106, tokenIndex106
+					{
+						position141 := position
+						{
+							position142, tokenIndex142 := position, tokenIndex
+							{
+								position144, tokenIndex144 := position, tokenIndex
+								if buffer[position] != rune('+') {
+									goto l145
+								}
+								position++
+								goto l144
+							l145:
+								position, tokenIndex = position144, tokenIndex144
+								if buffer[position] != rune('-') {
+									goto l142
+								}
+								position++
+							}
+						l144:
+							goto l143
+						l142:
+							position, tokenIndex = position142, tokenIndex142
+						}
+					l143:
+						{
+							position146, tokenIndex146 := position, tokenIndex
+							{
+								position148, tokenIndex148 := position, tokenIndex
+								if !_rules[ruleDigits]() {
+									goto l149
+								}
+								if buffer[position] != rune('.') {
+									goto l149
+								}
+								position++
+								{
+									position150, tokenIndex150 := position, tokenIndex
+									if !_rules[ruleDigits]() {
+										goto l150
 									}
-									position++
-								default:
-									if buffer[position] != rune('~') {
-										goto l62
+									goto l151
+								l150:
+									position, tokenIndex = position150, tokenIndex150
+								}
+							l151:
+								goto l148
+							l149:
+								position, tokenIndex = position148, tokenIndex148
+								if buffer[position] != rune('.') {
+									goto l147
+								}
+								position++
+								if !_rules[ruleDigits]() {
+									goto l147
+								}
+							}
+						l148:
+							{
+								position152, tokenIndex152 := position, tokenIndex
+								if !_rules[ruleExponent]() {
+									goto l152
+								}
+								goto l153
+							l152:
+								position, tokenIndex = position152, tokenIndex152
+							}
+						l153:
+							goto l146
+						l147:
+							position, tokenIndex = position146, tokenIndex146
+							if !_rules[ruleDigits]() {
+								goto l140
+							}
+							if !_rules[ruleExponent]() {
+								goto l140
+							}
+						}
+					l146:
+						if buffer[position] != rune('m') {
+							goto l140
+						}
+						position++
+						{
+							position154, tokenIndex154 := position, tokenIndex
+							if !_rules[ruleDurationUnit]() {
+								goto l154
+							}
+							goto l140
+						l154:
+							position, tokenIndex = position154, tokenIndex154
+						}
+						add(ruleDecimalLiteral, position141)
+					}
+					goto l106
+				l140:
+					position, tokenIndex = position106, tokenIndex106
+					{
+						position156 := position
+						{
+							position157, tokenIndex157 := position, tokenIndex
+							{
+								position159, tokenIndex159 := position, tokenIndex
+								if buffer[position] != rune('+') {
+									goto l160
+								}
+								position++
+								goto l159
+							l160:
+								position, tokenIndex = position159, tokenIndex159
+								if buffer[position] != rune('-') {
+									goto l157
+								}
+								position++
+							}
+						l159:
+							goto l158
+						l157:
+							position, tokenIndex = position157, tokenIndex157
+						}
+					l158:
+						{
+							position161, tokenIndex161 := position, tokenIndex
+							if buffer[position] != rune('0') {
+								goto l162
+							}
+							position++
+							{
+								position163, tokenIndex163 := position, tokenIndex
+								if buffer[position] != rune('x') {
+									goto l164
+								}
+								position++
+								goto l163
+							l164:
+								position, tokenIndex = position163, tokenIndex163
+								if buffer[position] != rune('X') {
+									goto l162
+								}
+								position++
+							}
+						l163:
+							if !_rules[ruleHexDigit]() {
+								goto l162
+							}
+						l165:
+							{
+								position166, tokenIndex166 := position, tokenIndex
+							l167:
+								{
+									position168, tokenIndex168 := position, tokenIndex
+									if buffer[position] != rune('_') {
+										goto l168
 									}
 									position++
+									goto l167
+								l168:
+									position, tokenIndex = position168, tokenIndex168
+								}
+								if !_rules[ruleHexDigit]() {
+									goto l166
 								}
+								goto l165
+							l166:
+								position, tokenIndex = position166, tokenIndex166
 							}
-
-							goto l60
-						l62:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('-') {
-								goto l58
+							goto l161
+						l162:
+							position, tokenIndex = position161, tokenIndex161
+							if !_rules[ruleDigits]() {
+								goto l155
 							}
-							position++
 						}
-					l60:
-						add(ruleIdentifierChar, position59)
+					l161:
+						if buffer[position] != rune('n') {
+							goto l155
+						}
+						position++
+						{
+							position169, tokenIndex169 := position, tokenIndex
+							if !_rules[ruleDurationUnit]() {
+								goto l169
+							}
+							goto l155
+						l169:
+							position, tokenIndex = position169, tokenIndex169
+						}
+						add(ruleBigIntLiteral, position156)
 					}
-					goto l57
-				l58:
-					position, tokenIndex = position58, tokenIndex58
-				}
-				if !_rules[ruleSpacing]() {
-					goto l48
-				}
-				add(ruleIdentifier, position49)
-			}
-			return true
-		l48:
-			position, tokenIndex = position48, tokenIndex48
-			return false
-		},
-		/* 7 IdentifierPrefix <- <(Letter / ((&('>') '>') | (&('<') '<') | (&('|') '|') | (&('?') '?') | (&('_') '_') | (&('*') '*') | (&('&') '&') | (&('^') '^') | (&('%') '%') | (&('$') '$') | (&('@') '@') | (&('!') '!') | (&('~') '~')))> */
-		nil,
-		/* 8 IdentifierChar <- <(LetterOrDigit / ((&('>') '>') | (&('<') '<') | (&('|') '|') | (&('?') '?') | (&('_') '_') | (&('*') '*') | (&('&') '&') | (&('^') '^') | (&('%') '%') | (&('$') '$') | (&('@') '@') | (&('!') '!') | (&('~') '~')) / '-')> */
-		nil,
-		/* 9 IdentifierAttr <- <(Identifier AttrPath+)> */
-		nil,
-		/* 10 AttrPath <- <('.' Identifier)> */
-		nil,
-		/* 11 Literal <- <((FloatLiteral / LongStringLiteral / ((&('#') BoolLiteral) | (&('"') StringLiteral) | (&('n') NilLiteral) | (&('+' | '-' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') IntegerLiteral))) Spacing)> */
-		func() bool {
-			position68, tokenIndex68 := position, tokenIndex
-			{
-				position69 := position
-				{
-					position70, tokenIndex70 := position, tokenIndex
+					goto l106
+				l155:
+					position, tokenIndex = position106, tokenIndex106
 					{
-						position72 := position
+						position171 := position
 						{
-							position73, tokenIndex73 := position, tokenIndex
+							position172, tokenIndex172 := position, tokenIndex
 							{
-								position75, tokenIndex75 := position, tokenIndex
+								position174, tokenIndex174 := position, tokenIndex
 								if buffer[position] != rune('+') {
-									goto l76
+									goto l175
 								}
 								position++
-								goto l75
-							l76:
-								position, tokenIndex = position75, tokenIndex75
+								goto l174
+							l175:
+								position, tokenIndex = position174, tokenIndex174
 								if buffer[position] != rune('-') {
-									goto l73
+									goto l172
 								}
 								position++
 							}
-						l75:
-							goto l74
-						l73:
-							position, tokenIndex = position73, tokenIndex73
+						l174:
+							goto l173
+						l172:
+							position, tokenIndex = position172, tokenIndex172
 						}
-					l74:
+					l173:
 						{
-							position77, tokenIndex77 := position, tokenIndex
+							position176, tokenIndex176 := position, tokenIndex
 							if !_rules[ruleDigits]() {
-								goto l78
+								goto l177
 							}
 							if buffer[position] != rune('.') {
-								goto l78
+								goto l177
 							}
 							position++
 							{
-								position79, tokenIndex79 := position, tokenIndex
+								position178, tokenIndex178 := position, tokenIndex
 								if !_rules[ruleDigits]() {
-									goto l79
+									goto l178
 								}
-								goto l80
-							l79:
-								position, tokenIndex = position79, tokenIndex79
+								goto l179
+							l178:
+								position, tokenIndex = position178, tokenIndex178
 							}
-						l80:
+						l179:
 							{
-								position81, tokenIndex81 := position, tokenIndex
+								position180, tokenIndex180 := position, tokenIndex
 								if !_rules[ruleExponent]() {
-									goto l81
+									goto l180
 								}
-								goto l82
-							l81:
-								position, tokenIndex = position81, tokenIndex81
+								goto l181
+							l180:
+								position, tokenIndex = position180, tokenIndex180
 							}
-						l82:
-							goto l77
-						l78:
-							position, tokenIndex = position77, tokenIndex77
+						l181:
+							goto l176
+						l177:
+							position, tokenIndex = position176, tokenIndex176
 							if !_rules[ruleDigits]() {
-								goto l83
+								goto l182
 							}
 							if !_rules[ruleExponent]() {
-								goto l83
+								goto l182
 							}
-							goto l77
-						l83:
-							position, tokenIndex = position77, tokenIndex77
+							goto l176
+						l182:
+							position, tokenIndex = position176, tokenIndex176
 							if buffer[position] != rune('.') {
-								goto l71
+								goto l170
 							}
 							position++
 							if !_rules[ruleDigits]() {
-								goto l71
+								goto l170
 							}
 							{
-								position84, tokenIndex84 := position, tokenIndex
+								position183, tokenIndex183 := position, tokenIndex
 								if !_rules[ruleExponent]() {
-									goto l84
+									goto l183
 								}
-								goto l85
-							l84:
-								position, tokenIndex = position84, tokenIndex84
+								goto l184
+							l183:
+								position, tokenIndex = position183, tokenIndex183
+							}
+						l184:
+						}
+					l176:
+						{
+							position185, tokenIndex185 := position, tokenIndex
+							if !_rules[ruleDurationUnit]() {
+								goto l185
 							}
-						l85:
+							goto l170
+						l185:
+							position, tokenIndex = position185, tokenIndex185
 						}
-					l77:
-						add(ruleFloatLiteral, position72)
+						add(ruleFloatLiteral, position171)
 					}
-					goto l70
-				l71:
-					position, tokenIndex = position70, tokenIndex70
+					goto l106
+				l170:
+					position, tokenIndex = position106, tokenIndex106
 					{
-						position87 := position
+						position187 := position
+						{
+							position188, tokenIndex188 := position, tokenIndex
+							if buffer[position] != rune('#') {
+								goto l188
+							}
+							position++
+							if buffer[position] != rune('h') {
+								goto l188
+							}
+							position++
+							goto l189
+						l188:
+							position, tokenIndex = position188, tokenIndex188
+						}
+					l189:
 						if buffer[position] != rune('"') {
-							goto l86
+							goto l186
 						}
 						position++
 						if buffer[position] != rune('"') {
-							goto l86
+							goto l186
 						}
 						position++
 						if buffer[position] != rune('"') {
-							goto l86
+							goto l186
 						}
 						position++
-					l88:
+					l190:
 						{
-							position89, tokenIndex89 := position, tokenIndex
+							position191, tokenIndex191 := position, tokenIndex
 							{
-								position90 := position
+								position192 := position
 								{
-									position91, tokenIndex91 := position, tokenIndex
+									position193, tokenIndex193 := position, tokenIndex
 									if buffer[position] != rune('"') {
-										goto l91
+										goto l193
 									}
 									position++
-									goto l89
-								l91:
-									position, tokenIndex = position91, tokenIndex91
+									if buffer[position] != rune('"') {
+										goto l193
+									}
+									position++
+									if buffer[position] != rune('"') {
+										goto l193
+									}
+									position++
+									{
+										position194, tokenIndex194 := position, tokenIndex
+										if buffer[position] != rune('"') {
+											goto l194
+										}
+										position++
+										goto l193
+									l194:
+										position, tokenIndex = position194, tokenIndex194
+									}
+									goto l191
+								l193:
+									position, tokenIndex = position193, tokenIndex193
 								}
 								if !matchDot() {
-									goto l89
+									goto l191
 								}
-								add(ruleLongStringChar, position90)
+								add(ruleLongStringChar, position192)
 							}
-							goto l88
-						l89:
-							position, tokenIndex = position89, tokenIndex89
+							goto l190
+						l191:
+							position, tokenIndex = position191, tokenIndex191
 						}
 						if buffer[position] != rune('"') {
-							goto l86
+							goto l186
 						}
 						position++
 						if buffer[position] != rune('"') {
-							goto l86
+							goto l186
 						}
 						position++
 						if buffer[position] != rune('"') {
-							goto l86
+							goto l186
 						}
 						position++
-						add(ruleLongStringLiteral, position87)
+						add(ruleLongStringLiteral, position187)
 					}
-					goto l70
-				l86:
-					position, tokenIndex = position70, tokenIndex70
+					goto l106
+				l186:
+					position, tokenIndex = position106, tokenIndex106
 					{
 						switch buffer[position] {
 						case '#':
 							if !_rules[ruleBoolLiteral]() {
-								goto l68
+								goto l104
 							}
 						case '"':
+							if !_rules[ruleStringLiteral]() {
+								goto l104
+							}
+						case ':':
 							{
-								position93 := position
-								if buffer[position] != rune('"') {
-									goto l68
+								position196 := position
+								if buffer[position] != rune(':') {
+									goto l104
 								}
 								position++
-							l94:
+								if !_rules[ruleIdentifierChar]() {
+									goto l104
+								}
+							l197:
 								{
-									position95, tokenIndex95 := position, tokenIndex
-									{
-										position96 := position
-										{
-											position97, tokenIndex97 := position, tokenIndex
-											{
-												position99 := position
-												{
-													position100, tokenIndex100 := position, tokenIndex
-													if buffer[position] != rune('\\') {
-														goto l101
-													}
-													position++
-													if buffer[position] != rune('u') {
-														goto l101
-													}
-													position++
-													if !_rules[ruleHexDigit]() {
-														goto l101
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l101
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l101
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l101
-													}
-													goto l100
-												l101:
-													position, tokenIndex = position100, tokenIndex100
-													if buffer[position] != rune('\\') {
-														goto l98
-													}
-													position++
-													if buffer[position] != rune('U') {
-														goto l98
-													}
-													position++
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-													if !_rules[ruleHexDigit]() {
-														goto l98
-													}
-												}
-											l100:
-												add(ruleUChar, position99)
-											}
-											goto l97
-										l98:
-											position, tokenIndex = position97, tokenIndex97
-											{
-												position103 := position
-												if buffer[position] != rune('\\') {
-													goto l102
-												}
-												position++
-												{
-													switch buffer[position] {
-													case '\'':
-														if buffer[position] != rune('\'') {
-															goto l102
-														}
-														position++
-													case '"':
-														if buffer[position] != rune('"') {
-															goto l102
-														}
-														position++
-													case '\\':
-														if buffer[position] != rune('\\') {
-															goto l102
-														}
-														position++
-													case 'v':
-														if buffer[position] != rune('v') {
-															goto l102
-														}
-														position++
-													case 't':
-														if buffer[position] != rune('t') {
-															goto l102
-														}
-														position++
-													case 'r':
-														if buffer[position] != rune('r') {
-															goto l102
-														}
-														position++
-													case 'n':
-														if buffer[position] != rune('n') {
-															goto l102
-														}
-														position++
-													case 'f':
-														if buffer[position] != rune('f') {
-															goto l102
-														}
-														position++
-													case 'b':
-														if buffer[position] != rune('b') {
-															goto l102
-														}
-														position++
-													default:
-														if buffer[position] != rune('a') {
-															goto l102
-														}
-														position++
-													}
-												}
-
-												add(ruleEscape, position103)
-											}
-											goto l97
-										l102:
-											position, tokenIndex = position97, tokenIndex97
-											{
-												position106 := position
-												if buffer[position] != rune('\\') {
-													goto l105
-												}
-												position++
-												if buffer[position] != rune('x') {
-													goto l105
-												}
-												position++
-												if !_rules[ruleHexDigit]() {
-													goto l105
-												}
-												if !_rules[ruleHexDigit]() {
-													goto l105
-												}
-												add(ruleHexByte, position106)
-											}
-											goto l97
-										l105:
-											position, tokenIndex = position97, tokenIndex97
-											{
-												position107, tokenIndex107 := position, tokenIndex
-												{
-													switch buffer[position] {
-													case '\\':
-														if buffer[position] != rune('\\') {
-															goto l107
-														}
-														position++
-													case '\n':
-														if buffer[position] != rune('\n') {
-															goto l107
-														}
-														position++
-													default:
-														if buffer[position] != rune('"') {
-															goto l107
-														}
-														position++
-													}
-												}
-
-												goto l95
-											l107:
-												position, tokenIndex = position107, tokenIndex107
-											}
-											if !matchDot() {
-												goto l95
-											}
-										}
-									l97:
-										add(ruleStringChar, position96)
+									position198, tokenIndex198 := position, tokenIndex
+									if !_rules[ruleIdentifierChar]() {
+										goto l198
 									}
-									goto l94
-								l95:
-									position, tokenIndex = position95, tokenIndex95
+									goto l197
+								l198:
+									position, tokenIndex = position198, tokenIndex198
 								}
-								if buffer[position] != rune('"') {
-									goto l68
+								{
+									position199, tokenIndex199 := position, tokenIndex
+									if buffer[position] != rune(':') {
+										goto l199
+									}
+									position++
+									goto l104
+								l199:
+									position, tokenIndex = position199, tokenIndex199
 								}
-								position++
-								add(ruleStringLiteral, position93)
+								add(ruleKeywordLiteral, position196)
 							}
 						case 'n':
 							{
-								position109 := position
+								position200 := position
 								if buffer[position] != rune('n') {
-									goto l68
+									goto l104
 								}
 								position++
 								if buffer[position] != rune('i') {
-									goto l68
+									goto l104
 								}
 								position++
 								if buffer[position] != rune('l') {
-									goto l68
+									goto l104
 								}
 								position++
-								add(ruleNilLiteral, position109)
+								add(ruleNilLiteral, position200)
 							}
 						default:
 							{
-								position110 := position
+								position201 := position
 								{
-									position111, tokenIndex111 := position, tokenIndex
+									position202, tokenIndex202 := position, tokenIndex
 									{
-										position113, tokenIndex113 := position, tokenIndex
+										position204, tokenIndex204 := position, tokenIndex
 										if buffer[position] != rune('+') {
-											goto l114
+											goto l205
 										}
 										position++
-										goto l113
-									l114:
-										position, tokenIndex = position113, tokenIndex113
+										goto l204
+									l205:
+										position, tokenIndex = position204, tokenIndex204
 										if buffer[position] != rune('-') {
-											goto l111
+											goto l202
 										}
 										position++
 									}
-								l113:
-									goto l112
-								l111:
-									position, tokenIndex = position111, tokenIndex111
+								l204:
+									goto l203
+								l202:
+									position, tokenIndex = position202, tokenIndex202
 								}
-							l112:
+							l203:
 								{
-									position115, tokenIndex115 := position, tokenIndex
+									position206, tokenIndex206 := position, tokenIndex
 									if buffer[position] != rune('0') {
-										goto l116
+										goto l207
 									}
 									position++
 									{
-										position117, tokenIndex117 := position, tokenIndex
+										position208, tokenIndex208 := position, tokenIndex
 										if buffer[position] != rune('x') {
-											goto l118
+											goto l209
 										}
 										position++
-										goto l117
-									l118:
-										position, tokenIndex = position117, tokenIndex117
+										goto l208
+									l209:
+										position, tokenIndex = position208, tokenIndex208
 										if buffer[position] != rune('X') {
-											goto l116
+											goto l207
 										}
 										position++
 									}
-								l117:
+								l208:
 									{
-										position119 := position
+										position210 := position
 										{
-											position120, tokenIndex120 := position, tokenIndex
+											position211, tokenIndex211 := position, tokenIndex
 											if !_rules[ruleHexDigit]() {
-												goto l121
+												goto l212
 											}
-										l122:
+										l213:
 											{
-												position123, tokenIndex123 := position, tokenIndex
-											l124:
+												position214, tokenIndex214 := position, tokenIndex
+											l215:
 												{
-													position125, tokenIndex125 := position, tokenIndex
+													position216, tokenIndex216 := position, tokenIndex
 													if buffer[position] != rune('_') {
-														goto l125
+														goto l216
 													}
 													position++
-													goto l124
-												l125:
-													position, tokenIndex = position125, tokenIndex125
+													goto l215
+												l216:
+													position, tokenIndex = position216, tokenIndex216
 												}
 												if !_rules[ruleHexDigit]() {
-													goto l123
+													goto l214
 												}
-												goto l122
-											l123:
-												position, tokenIndex = position123, tokenIndex123
+												goto l213
+											l214:
+												position, tokenIndex = position214, tokenIndex214
 											}
-											goto l120
-										l121:
-											position, tokenIndex = position120, tokenIndex120
+											goto l211
+										l212:
+											position, tokenIndex = position211, tokenIndex211
 											if buffer[position] != rune('0') {
-												goto l116
+												goto l207
 											}
 											position++
 										}
-									l120:
-										add(ruleHexNumeral, position119)
+									l211:
+										add(ruleHexNumeral, position210)
 									}
-									goto l115
-								l116:
-									position, tokenIndex = position115, tokenIndex115
+									goto l206
+								l207:
+									position, tokenIndex = position206, tokenIndex206
 									{
-										position126 := position
+										position217 := position
 										{
-											position127, tokenIndex127 := position, tokenIndex
+											position218, tokenIndex218 := position, tokenIndex
 											if c := buffer[position]; c < rune('1') || c > rune('9') {
-												goto l128
+												goto l219
 											}
 											position++
-										l129:
+										l220:
 											{
-												position130, tokenIndex130 := position, tokenIndex
-											l131:
+												position221, tokenIndex221 := position, tokenIndex
+											l222:
 												{
-													position132, tokenIndex132 := position, tokenIndex
+													position223, tokenIndex223 := position, tokenIndex
 													if buffer[position] != rune('_') {
-														goto l132
+														goto l223
 													}
 													position++
-													goto l131
-												l132:
-													position, tokenIndex = position132, tokenIndex132
+													goto l222
+												l223:
+													position, tokenIndex = position223, tokenIndex223
 												}
 												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l130
+													goto l221
 												}
 												position++
-												goto l129
-											l130:
-												position, tokenIndex = position130, tokenIndex130
+												goto l220
+											l221:
+												position, tokenIndex = position221, tokenIndex221
 											}
-											goto l127
-										l128:
-											position, tokenIndex = position127, tokenIndex127
+											goto l218
+										l219:
+											position, tokenIndex = position218, tokenIndex218
 											if buffer[position] != rune('0') {
-												goto l68
+												goto l104
 											}
 											position++
 										}
-									l127:
-										add(ruleDecimalNumeral, position126)
+									l218:
+										add(ruleDecimalNumeral, position217)
 									}
 								}
-							l115:
+							l206:
 								{
-									position133, tokenIndex133 := position, tokenIndex
+									position224, tokenIndex224 := position, tokenIndex
 									{
-										position135, tokenIndex135 := position, tokenIndex
+										position226, tokenIndex226 := position, tokenIndex
 										if buffer[position] != rune('u') {
-											goto l136
+											goto l227
 										}
 										position++
-										goto l135
-									l136:
-										position, tokenIndex = position135, tokenIndex135
+										goto l226
+									l227:
+										position, tokenIndex = position226, tokenIndex226
 										if buffer[position] != rune('U') {
-											goto l133
+											goto l224
 										}
 										position++
 									}
-								l135:
-									goto l134
-								l133:
-									position, tokenIndex = position133, tokenIndex133
+								l226:
+									goto l225
+								l224:
+									position, tokenIndex = position224, tokenIndex224
+								}
+							l225:
+								{
+									position228, tokenIndex228 := position, tokenIndex
+									if !_rules[ruleDurationUnit]() {
+										goto l228
+									}
+									goto l104
+								l228:
+									position, tokenIndex = position228, tokenIndex228
 								}
-							l134:
-								add(ruleIntegerLiteral, position110)
+								add(ruleIntegerLiteral, position201)
 							}
 						}
 					}
 
 				}
-			l70:
+			l106:
 				if !_rules[ruleSpacing]() {
-					goto l68
+					goto l104
 				}
-				add(ruleLiteral, position69)
+				add(ruleLiteral, position105)
 			}
 			return true
-		l68:
-			position, tokenIndex = position68, tokenIndex68
+		l104:
+			position, tokenIndex = position104, tokenIndex104
 			return false
 		},
-		/* 12 NilLiteral <- <('n' 'i' 'l')> */
+		/* 21 NilLiteral <- <('n' 'i' 'l')> */
 		nil,
-		/* 13 BoolLiteral <- <((('#' 'f') / ('#' 't')) !LetterOrDigit)> */
+		/* 22 BoolLiteral <- <((('#' 'f') / ('#' 't')) !LetterOrDigit)> */
 		func() bool {
-			position138, tokenIndex138 := position, tokenIndex
+			position230, tokenIndex230 := position, tokenIndex
 			{
-				position139 := position
+				position231 := position
 				{
-					position140, tokenIndex140 := position, tokenIndex
+					position232, tokenIndex232 := position, tokenIndex
 					if buffer[position] != rune('#') {
-						goto l141
+						goto l233
 					}
 					position++
 					if buffer[position] != rune('f') {
-						goto l141
+						goto l233
 					}
 					position++
-					goto l140
-				l141:
-					position, tokenIndex = position140, tokenIndex140
+					goto l232
+				l233:
+					position, tokenIndex = position232, tokenIndex232
 					if buffer[position] != rune('#') {
-						goto l138
+						goto l230
 					}
 					position++
 					if buffer[position] != rune('t') {
-						goto l138
+						goto l230
 					}
 					position++
 				}
-			l140:
+			l232:
 				{
-					position142, tokenIndex142 := position, tokenIndex
+					position234, tokenIndex234 := position, tokenIndex
 					if !_rules[ruleLetterOrDigit]() {
-						goto l142
+						goto l234
+					}
+					goto l230
+				l234:
+					position, tokenIndex = position234, tokenIndex234
+				}
+				add(ruleBoolLiteral, position231)
+			}
+			return true
+		l230:
+			position, tokenIndex = position230, tokenIndex230
+			return false
+		},
+		/* 23 KeywordLiteral <- <(':' IdentifierChar+ !':')> */
+		nil,
+		/* 24 TimeLiteral <- <('#' 'i' 'n' 's' 't' Spacing StringLiteral)> */
+		nil,
+		/* 25 DurationLiteral <- <(('+' / '-')? (Digits ('.' Digits !('m' !'s'))? DurationUnit)+ !IdentifierChar)> */
+		nil,
+		/* 26 DurationUnit <- <(('m' 's') / ((&('h') 'h') | (&('m') 'm') | (&('s') 's') | (&('µ') ('µ' 's')) | (&('u') ('u' 's')) | (&('n') ('n' 's'))))> */
+		func() bool {
+			position238, tokenIndex238 := position, tokenIndex
+			{
+				position239 := position
+				{
+					position240, tokenIndex240 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l241
+					}
+					position++
+					if buffer[position] != rune('s') {
+						goto l241
+					}
+					position++
+					goto l240
+				l241:
+					position, tokenIndex = position240, tokenIndex240
+					{
+						switch buffer[position] {
+						case 'h':
+							if buffer[position] != rune('h') {
+								goto l238
+							}
+							position++
+						case 'm':
+							if buffer[position] != rune('m') {
+								goto l238
+							}
+							position++
+						case 's':
+							if buffer[position] != rune('s') {
+								goto l238
+							}
+							position++
+						case 'µ':
+							if buffer[position] != rune('µ') {
+								goto l238
+							}
+							position++
+							if buffer[position] != rune('s') {
+								goto l238
+							}
+							position++
+						case 'u':
+							if buffer[position] != rune('u') {
+								goto l238
+							}
+							position++
+							if buffer[position] != rune('s') {
+								goto l238
+							}
+							position++
+						default:
+							if buffer[position] != rune('n') {
+								goto l238
+							}
+							position++
+							if buffer[position] != rune('s') {
+								goto l238
+							}
+							position++
+						}
 					}
-					goto l138
-				l142:
-					position, tokenIndex = position142, tokenIndex142
+
 				}
-				add(ruleBoolLiteral, position139)
+			l240:
+				add(ruleDurationUnit, position239)
 			}
 			return true
-		l138:
-			position, tokenIndex = position138, tokenIndex138
+		l238:
+			position, tokenIndex = position238, tokenIndex238
 			return false
 		},
-		/* 14 FloatLiteral <- <(('+' / '-')? ((Digits '.' Digits? Exponent?) / (Digits Exponent) / ('.' Digits Exponent?)))> */
+		/* 27 BytesLiteral <- <('#' ('x' / ('b' '6' '4')) '"' (!'"' .)* '"')> */
+		nil,
+		/* 28 RegexpLiteral <- <('#' 'r' 'x' '"' (('\\' .) / (!'"' .))* '"')> */
+		nil,
+		/* 29 DecimalLiteral <- <(('+' / '-')? ((((Digits '.' Digits?) / ('.' Digits)) Exponent?) / (Digits Exponent)) 'm' !DurationUnit)> */
+		nil,
+		/* 30 BigIntLiteral <- <(('+' / '-')? (('0' ('x' / 'X') HexDigit ('_'* HexDigit)*) / Digits) 'n' !DurationUnit)> */
+		nil,
+		/* 31 FloatLiteral <- <(('+' / '-')? ((Digits '.' Digits? Exponent?) / (Digits Exponent) / ('.' Digits Exponent?)) !DurationUnit)> */
 		nil,
-		/* 15 Exponent <- <(('e' / 'E') ('+' / '-')? Digits)> */
+		/* 32 Exponent <- <(('e' / 'E') ('+' / '-')? Digits)> */
 		func() bool {
-			position144, tokenIndex144 := position, tokenIndex
+			position248, tokenIndex248 := position, tokenIndex
 			{
-				position145 := position
+				position249 := position
 				{
-					position146, tokenIndex146 := position, tokenIndex
+					position250, tokenIndex250 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l147
+						goto l251
 					}
 					position++
-					goto l146
-				l147:
-					position, tokenIndex = position146, tokenIndex146
+					goto l250
+				l251:
+					position, tokenIndex = position250, tokenIndex250
 					if buffer[position] != rune('E') {
-						goto l144
+						goto l248
 					}
 					position++
 				}
-			l146:
+			l250:
 				{
-					position148, tokenIndex148 := position, tokenIndex
+					position252, tokenIndex252 := position, tokenIndex
 					{
-						position150, tokenIndex150 := position, tokenIndex
+						position254, tokenIndex254 := position, tokenIndex
 						if buffer[position] != rune('+') {
-							goto l151
+							goto l255
 						}
 						position++
-						goto l150
-					l151:
-						position, tokenIndex = position150, tokenIndex150
+						goto l254
+					l255:
+						position, tokenIndex = position254, tokenIndex254
 						if buffer[position] != rune('-') {
-							goto l148
+							goto l252
 						}
 						position++
 					}
-				l150:
-					goto l149
-				l148:
-					position, tokenIndex = position148, tokenIndex148
+				l254:
+					goto l253
+				l252:
+					position, tokenIndex = position252, tokenIndex252
 				}
-			l149:
+			l253:
 				if !_rules[ruleDigits]() {
-					goto l144
+					goto l248
 				}
-				add(ruleExponent, position145)
+				add(ruleExponent, position249)
 			}
 			return true
-		l144:
-			position, tokenIndex = position144, tokenIndex144
+		l248:
+			position, tokenIndex = position248, tokenIndex248
 			return false
 		},
-		/* 16 IntegerLiteral <- <(('+' / '-')? (('0' ('x' / 'X') HexNumeral) / DecimalNumeral) ('u' / 'U')?)> */
+		/* 33 IntegerLiteral <- <(('+' / '-')? (('0' ('x' / 'X') HexNumeral) / DecimalNumeral) ('u' / 'U')? !DurationUnit)> */
 		nil,
-		/* 17 HexNumeral <- <((HexDigit ('_'* HexDigit)*) / '0')> */
+		/* 34 HexNumeral <- <((HexDigit ('_'* HexDigit)*) / '0')> */
 		nil,
-		/* 18 DecimalNumeral <- <(([1-9] ('_'* [0-9])*) / '0')> */
+		/* 35 DecimalNumeral <- <(([1-9] ('_'* [0-9])*) / '0')> */
 		nil,
-		/* 19 LongStringLiteral <- <('"' '"' '"' LongStringChar* ('"' '"' '"'))> */
+		/* 36 LongStringLiteral <- <(('#' 'h')? ('"' '"' '"') LongStringChar* ('"' '"' '"'))> */
 		nil,
-		/* 20 LongStringChar <- <(!'"' .)> */
+		/* 37 LongStringChar <- <(!('"' '"' '"' !'"') .)> */
 		nil,
-		/* 21 StringLiteral <- <('"' StringChar* '"')> */
-		nil,
-		/* 22 StringChar <- <(UChar / Escape / HexByte / (!((&('\\') '\\') | (&('\n') '\n') | (&('"') '"')) .))> */
+		/* 38 StringLiteral <- <('"' StringChar* '"')> */
+		func() bool {
+			position261, tokenIndex261 := position, tokenIndex
+			{
+				position262 := position
+				if buffer[position] != rune('"') {
+					goto l261
+				}
+				position++
+			l263:
+				{
+					position264, tokenIndex264 := position, tokenIndex
+					{
+						position265 := position
+						{
+							position266, tokenIndex266 := position, tokenIndex
+							{
+								position268 := position
+								{
+									position269, tokenIndex269 := position, tokenIndex
+									if buffer[position] != rune('\\') {
+										goto l270
+									}
+									position++
+									if buffer[position] != rune('u') {
+										goto l270
+									}
+									position++
+									if !_rules[ruleHexDigit]() {
+										goto l270
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l270
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l270
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l270
+									}
+									goto l269
+								l270:
+									position, tokenIndex = position269, tokenIndex269
+									if buffer[position] != rune('\\') {
+										goto l267
+									}
+									position++
+									if buffer[position] != rune('U') {
+										goto l267
+									}
+									position++
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+									if !_rules[ruleHexDigit]() {
+										goto l267
+									}
+								}
+							l269:
+								add(ruleUChar, position268)
+							}
+							goto l266
+						l267:
+							position, tokenIndex = position266, tokenIndex266
+							{
+								position272 := position
+								if buffer[position] != rune('\\') {
+									goto l271
+								}
+								position++
+								{
+									switch buffer[position] {
+									case '\'':
+										if buffer[position] != rune('\'') {
+											goto l271
+										}
+										position++
+									case '"':
+										if buffer[position] != rune('"') {
+											goto l271
+										}
+										position++
+									case '\\':
+										if buffer[position] != rune('\\') {
+											goto l271
+										}
+										position++
+									case 'v':
+										if buffer[position] != rune('v') {
+											goto l271
+										}
+										position++
+									case 't':
+										if buffer[position] != rune('t') {
+											goto l271
+										}
+										position++
+									case 'r':
+										if buffer[position] != rune('r') {
+											goto l271
+										}
+										position++
+									case 'n':
+										if buffer[position] != rune('n') {
+											goto l271
+										}
+										position++
+									case 'f':
+										if buffer[position] != rune('f') {
+											goto l271
+										}
+										position++
+									case 'b':
+										if buffer[position] != rune('b') {
+											goto l271
+										}
+										position++
+									default:
+										if buffer[position] != rune('a') {
+											goto l271
+										}
+										position++
+									}
+								}
+
+								add(ruleEscape, position272)
+							}
+							goto l266
+						l271:
+							position, tokenIndex = position266, tokenIndex266
+							{
+								position275 := position
+								if buffer[position] != rune('\\') {
+									goto l274
+								}
+								position++
+								if buffer[position] != rune('x') {
+									goto l274
+								}
+								position++
+								if !_rules[ruleHexDigit]() {
+									goto l274
+								}
+								if !_rules[ruleHexDigit]() {
+									goto l274
+								}
+								add(ruleHexByte, position275)
+							}
+							goto l266
+						l274:
+							position, tokenIndex = position266, tokenIndex266
+							{
+								position276, tokenIndex276 := position, tokenIndex
+								{
+									switch buffer[position] {
+									case '\\':
+										if buffer[position] != rune('\\') {
+											goto l276
+										}
+										position++
+									case '\n':
+										if buffer[position] != rune('\n') {
+											goto l276
+										}
+										position++
+									default:
+										if buffer[position] != rune('"') {
+											goto l276
+										}
+										position++
+									}
+								}
+
+								goto l264
+							l276:
+								position, tokenIndex = position276, tokenIndex276
+							}
+							if !matchDot() {
+								goto l264
+							}
+						}
+					l266:
+						add(ruleStringChar, position265)
+					}
+					goto l263
+				l264:
+					position, tokenIndex = position264, tokenIndex264
+				}
+				if buffer[position] != rune('"') {
+					goto l261
+				}
+				position++
+				add(ruleStringLiteral, position262)
+			}
+			return true
+		l261:
+			position, tokenIndex = position261, tokenIndex261
+			return false
+		},
+		/* 39 StringChar <- <(UChar / Escape / HexByte / (!((&('\\') '\\') | (&('\n') '\n') | (&('"') '"')) .))> */
 		nil,
-		/* 23 HexByte <- <('\\' 'x' HexDigit HexDigit)> */
+		/* 40 HexByte <- <('\\' 'x' HexDigit HexDigit)> */
 		nil,
-		/* 24 UChar <- <(('\\' 'u' HexDigit HexDigit HexDigit HexDigit) / ('\\' 'U' HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit))> */
+		/* 41 UChar <- <(('\\' 'u' HexDigit HexDigit HexDigit HexDigit) / ('\\' 'U' HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit))> */
 		nil,
-		/* 25 LetterOrDigit <- <((&('_') '_') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))> */
+		/* 42 LetterOrDigit <- <((&('_') '_') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))> */
 		func() bool {
-			position161, tokenIndex161 := position, tokenIndex
+			position281, tokenIndex281 := position, tokenIndex
 			{
-				position162 := position
+				position282 := position
 				{
 					switch buffer[position] {
 					case '_':
 						if buffer[position] != rune('_') {
-							goto l161
+							goto l281
 						}
 						position++
 					case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l161
+							goto l281
 						}
 						position++
 					case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l161
+							goto l281
 						}
 						position++
 					default:
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l161
+							goto l281
 						}
 						position++
 					}
 				}
 
-				add(ruleLetterOrDigit, position162)
+				add(ruleLetterOrDigit, position282)
 			}
 			return true
-		l161:
-			position, tokenIndex = position161, tokenIndex161
+		l281:
+			position, tokenIndex = position281, tokenIndex281
 			return false
 		},
-		/* 26 Letter <- <((&('_') '_') | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))> */
+		/* 43 Letter <- <((&('_') '_') | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))> */
 		nil,
-		/* 27 Digits <- <([0-9] ('_'* [0-9])*)> */
+		/* 44 Digits <- <([0-9] ('_'* [0-9])*)> */
 		func() bool {
-			position165, tokenIndex165 := position, tokenIndex
+			position285, tokenIndex285 := position, tokenIndex
 			{
-				position166 := position
+				position286 := position
 				if c := buffer[position]; c < rune('0') || c > rune('9') {
-					goto l165
+					goto l285
 				}
 				position++
-			l167:
+			l287:
 				{
-					position168, tokenIndex168 := position, tokenIndex
-				l169:
+					position288, tokenIndex288 := position, tokenIndex
+				l289:
 					{
-						position170, tokenIndex170 := position, tokenIndex
+						position290, tokenIndex290 := position, tokenIndex
 						if buffer[position] != rune('_') {
-							goto l170
+							goto l290
 						}
 						position++
-						goto l169
-					l170:
-						position, tokenIndex = position170, tokenIndex170
+						goto l289
+					l290:
+						position, tokenIndex = position290, tokenIndex290
 					}
 					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l168
+						goto l288
 					}
 					position++
-					goto l167
-				l168:
-					position, tokenIndex = position168, tokenIndex168
+					goto l287
+				l288:
+					position, tokenIndex = position288, tokenIndex288
 				}
-				add(ruleDigits, position166)
+				add(ruleDigits, position286)
 			}
 			return true
-		l165:
-			position, tokenIndex = position165, tokenIndex165
+		l285:
+			position, tokenIndex = position285, tokenIndex285
 			return false
 		},
-		/* 28 Escape <- <('\\' ((&('\'') '\'') | (&('"') '"') | (&('\\') '\\') | (&('v') 'v') | (&('t') 't') | (&('r') 'r') | (&('n') 'n') | (&('f') 'f') | (&('b') 'b') | (&('a') 'a')))> */
+		/* 45 Escape <- <('\\' ((&('\'') '\'') | (&('"') '"') | (&('\\') '\\') | (&('v') 'v') | (&('t') 't') | (&('r') 'r') | (&('n') 'n') | (&('f') 'f') | (&('b') 'b') | (&('a') 'a')))> */
 		nil,
-		/* 29 HexDigit <- <((&('a' | 'b' | 'c' | 'd' | 'e' | 'f') [a-f]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F') [A-F]) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]))> */
+		/* 46 HexDigit <- <((&('a' | 'b' | 'c' | 'd' | 'e' | 'f') [a-f]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F') [A-F]) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]))> */
 		func() bool {
-			position172, tokenIndex172 := position, tokenIndex
+			position292, tokenIndex292 := position, tokenIndex
 			{
-				position173 := position
+				position293 := position
 				{
 					switch buffer[position] {
 					case 'a', 'b', 'c', 'd', 'e', 'f':
 						if c := buffer[position]; c < rune('a') || c > rune('f') {
-							goto l172
+							goto l292
 						}
 						position++
 					case 'A', 'B', 'C', 'D', 'E', 'F':
 						if c := buffer[position]; c < rune('A') || c > rune('F') {
-							goto l172
+							goto l292
 						}
 						position++
 					default:
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l172
+							goto l292
 						}
 						position++
 					}
 				}
 
-				add(ruleHexDigit, position173)
+				add(ruleHexDigit, position293)
 			}
 			return true
-		l172:
-			position, tokenIndex = position172, tokenIndex172
+		l292:
+			position, tokenIndex = position292, tokenIndex292
 			return false
 		},
-		/* 30 LPAR <- <(Spacing '(' Spacing)> */
+		/* 47 LPAR <- <(Spacing '(' Spacing)> */
 		nil,
-		/* 31 RPAR <- <(Spacing ')' Spacing)> */
+		/* 48 RPAR <- <(Spacing ')' Spacing)> */
 		nil,
-		/* 32 EOT <- <!.> */
+		/* 49 EOT <- <!.> */
 		nil,
 	}
 	p.rules = _rules
@@ -0,0 +1,101 @@
+package gendsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// parseInterpolation evaluates an interpolated string like #f"hello ${user.name}",
+// the values embedded are evaluated in the current EvalCtx and joined with the text by display.
+func parseInterpolation(c *ParseContext, evalCtx *EvalCtx, node *node32) (any, error) {
+	var sb strings.Builder
+	for cur := node.up; cur != nil; {
+		if cur.pegRule == ruleInterpolationChar {
+			begin := cur
+			for cur.next != nil && cur.next.pegRule == ruleInterpolationChar {
+				cur = cur.next
+			}
+			text, err := unescapeInterpolation(string(c.p.buffer[begin.begin:cur.end]))
+			if err != nil {
+				return nil, evalErrorf(c, begin, "invalid interpolated string: %s", err)
+			}
+			sb.WriteString(text)
+			cur = cur.next
+			continue
+		}
+		if cur.pegRule == ruleInterpolationHole {
+			v, err := c.parseNode(skipSpacing(cur.up), evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			sb.WriteString(display(filledValue(v)))
+		}
+		cur = cur.next
+	}
+	return String(sb.String()), nil
+}
+
+// interpolationHoles returns the Value nodes embedded in an interpolated string.
+func interpolationHoles(node *node32) []*node32 {
+	var ret []*node32
+	for cur := node.up; cur != nil; cur = cur.next {
+		if cur.pegRule == ruleInterpolationHole {
+			ret = append(ret, skipSpacing(cur.up))
+		}
+	}
+	return ret
+}
+
+// unescapeInterpolation reads the text in an interpolated string, which has the escapes of a string literal and \$ for a '$'.
+func unescapeInterpolation(s string) (string, error) {
+	var sb strings.Builder
+	for len(s) > 0 {
+		if strings.HasPrefix(s, `\$`) {
+			sb.WriteByte('$')
+			s = s[2:]
+			continue
+		}
+		r, multibyte, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return "", err
+		}
+		if r < utf8.RuneSelf || !multibyte {
+			sb.WriteByte(byte(r))
+		} else {
+			sb.WriteRune(r)
+		}
+		s = tail
+	}
+	return sb.String(), nil
+}
+
+// display returns the text of a value embedded in an interpolated string,
+// which is the value itself for strings and symbols, the plain text for numbers, times and durations, and the repr for others.
+func display(v Value) string {
+	switch v := v.(type) {
+	case String:
+		return string(v)
+	case Symbol:
+		return v.Name()
+	case Int:
+		return strconv.FormatInt(int64(v), 10)
+	case Uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case Float:
+		return strconv.FormatFloat(float64(v), 'g', -1, 64)
+	case BigInt:
+		return v.String()
+	case Decimal:
+		return v.String()
+	case Time:
+		return time.Time(v).Format(time.RFC3339Nano)
+	case Duration:
+		return time.Duration(v).String()
+	case *UserData:
+		return fmt.Sprint(v.V)
+	}
+	return Repr(v)
+}
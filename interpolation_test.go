@@ -0,0 +1,83 @@
+package gendsl
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Interpolation", func() {
+	var testEnv *Env
+	BeforeEach(func() {
+		testEnv = NewEnv().
+			WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)}).
+			WithString("name", "bob").
+			WithInt("n", 2).
+			WithUserData("user", &UserData{V: Map{"name": String("alice")}})
+	})
+
+	It("evaluates the values embedded", func() {
+		for expr, want := range map[string]string{
+			`#f"hello ${name}"`: "hello bob",
+			`#f"hello ${user.name}, you have ${(PLUS n 1)} items"`: "hello alice, you have 3 items",
+			`#f"${ n }${n}"`:      "22",
+			`#f""`:                "",
+			`#f"${#f"${name}!"}"`: "bob!",
+			`#f"${1.5} ${2.50m} ${10n} ${30m} ${nil} ${'x}"`: "1.5 2.50 10 30m0s nil x",
+			`#f"${#inst "2024-01-02"}"`:                      "2024-01-02T00:00:00Z",
+			`#f"a\tb\"c\$d \${name} $name {name}"`:           "a\tb\"c$d ${name} $name {name}",
+		} {
+			v, err := EvalExpr(expr, testEnv)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v).Should(gomega.Equal(String(want)), expr)
+		}
+
+		_, err := EvalExpr(`#f"${name"`, testEnv)
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+
+	It("evaluates the values in the current EvalCtx", func() {
+		v, err := EvalExpr(`#f"${when}"`, testEnv.WithTime("when", Time(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(String("2024-01-02T03:04:05Z")))
+
+		pc, err := MakeParseContext(`#f"${name} has ${n}"`)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		v, err = pc.Eval(NewEvalCtx(nil, nil, NewEnv().WithString("name", "carol").WithInt("n", 1)))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(String("carol has 1")))
+	})
+
+	It("displays nil for a procedure that returns nil", func() {
+		env := testEnv.WithProcedure("NILP", Procedure{Eval: func(*EvalCtx, []Expr, map[string]Value) (Value, error) {
+			return nil, nil
+		}})
+		v, err := EvalExpr(`#f"a ${(NILP)}"`, env)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(String("a nil")))
+	})
+
+	It("reports the positions inside the string", func() {
+		_, err := EvalExpr("(PLUS 1\n  #f\"hello ${(PLUS 1 foo)}\")", testEnv)
+		var ue *UnboundedIdentifierError
+		gomega.Expect(errors.As(err, &ue)).Should(gomega.BeTrue())
+		gomega.Expect(ue.ID).Should(gomega.Equal("foo"))
+		gomega.Expect(ue.BeginLine).Should(gomega.Equal(2))
+		gomega.Expect(ue.BeginSym).Should(gomega.Equal(22))
+	})
+
+	It("can be inspected as a node", func() {
+		pc, err := MakeParseContext(`#f"${user.name} ${(PLUS n 1)}"`)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		root := pc.Root()
+		gomega.Expect(root.Type()).Should(gomega.Equal(ExprTypeInterpolation))
+		children := root.Children()
+		gomega.Expect(children).Should(gomega.HaveLen(2))
+		gomega.Expect(children[0].Type()).Should(gomega.Equal(ExprTypeIdentifier))
+		gomega.Expect(children[0].Identifier()).Should(gomega.Equal("user"))
+		gomega.Expect(children[0].Path()).Should(gomega.Equal([]string{"name"}))
+		gomega.Expect(children[1].Operator().Identifier()).Should(gomega.Equal("PLUS"))
+	})
+})
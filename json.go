@@ -0,0 +1,187 @@
+package gendsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Object is a JSON object decoded by [gendsl.FromJSON], it is held by a UserData and its fields can be selected like obj.name.
+type Object map[string]Value
+
+var (
+	_ Selector = Object(nil)
+	_ Equaler  = Object(nil)
+	_ Hasher   = Object(nil)
+)
+
+// Select returns the field `key` of the object.
+func (o Object) Select(key string) (Value, bool) {
+	v, ok := o[key]
+	return v, ok
+}
+
+// Equal reports whether `other` is a UserData of an Object with the same keys and the values equal by [gendsl.Equal].
+func (o Object) Equal(other Value) bool {
+	p, ok := userData[Object](other)
+	if !ok || len(o) != len(p) {
+		return false
+	}
+	for k, v := range o {
+		w, ok := p[k]
+		if !ok || !Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns the hash of the object regardless of the order of the keys, a value that cannot be hashed is skipped.
+func (o Object) Hash() uint64 {
+	var sum uint64
+	for k, v := range o {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(k))
+		if err := writeHash(h, nilToNil(v)); err != nil {
+			continue
+		}
+		sum += h.Sum64()
+	}
+	return sum
+}
+
+// MarshalJSON encodes the Int as a JSON number.
+func (i Int) MarshalJSON() ([]byte, error) { return strconv.AppendInt(nil, int64(i), 10), nil }
+
+// MarshalJSON encodes the Uint as a JSON number.
+func (u Uint) MarshalJSON() ([]byte, error) { return strconv.AppendUint(nil, uint64(u), 10), nil }
+
+// MarshalJSON encodes the Float as a JSON number, NaN and infinities cannot be encoded.
+func (f Float) MarshalJSON() ([]byte, error) { return json.Marshal(float64(f)) }
+
+// MarshalJSON encodes the String as a JSON string.
+func (s String) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
+
+// MarshalJSON encodes the Bool as true or false.
+func (b Bool) MarshalJSON() ([]byte, error) { return json.Marshal(bool(b)) }
+
+// MarshalJSON encodes Nil as null.
+func (Nil) MarshalJSON() ([]byte, error) { return []byte("null"), nil }
+
+// MarshalJSON encodes the BigInt as a JSON number of all its digits.
+func (b BigInt) MarshalJSON() ([]byte, error) { return []byte(b.String()), nil }
+
+// MarshalJSON encodes the Decimal as a JSON number of all its digits like 2.50.
+func (d Decimal) MarshalJSON() ([]byte, error) { return []byte(d.String()), nil }
+
+// MarshalJSON encodes the Time as a string in the format of RFC 3339.
+func (t Time) MarshalJSON() ([]byte, error) { return time.Time(t).MarshalJSON() }
+
+// MarshalJSON encodes the Duration as a string like "1h30m0s".
+func (d Duration) MarshalJSON() ([]byte, error) { return json.Marshal(time.Duration(d).String()) }
+
+// MarshalJSON encodes the Bytes as a base64 string, the same as []byte is encoded.
+func (b Bytes) MarshalJSON() ([]byte, error) { return json.Marshal([]byte(b)) }
+
+// MarshalJSON encodes the Symbol as a string of its name.
+func (s Symbol) MarshalJSON() ([]byte, error) { return json.Marshal(s.Name()) }
+
+// MarshalJSON encodes the Keyword as a string of its name without the leading ':'.
+func (k Keyword) MarshalJSON() ([]byte, error) { return json.Marshal(k.Name()) }
+
+// MarshalJSON encodes the Regexp as a string of its pattern.
+func (r Regexp) MarshalJSON() ([]byte, error) { return json.Marshal(r.re.String()) }
+
+// MarshalJSON encodes the Code as a string of its text.
+func (c Code) MarshalJSON() ([]byte, error) { return json.Marshal(c.Text()) }
+
+// MarshalJSON returns an error since a procedure cannot be encoded.
+func (Procedure) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("cannot marshal procedure to JSON")
+}
+
+// MarshalJSON encodes the V of the UserData, so that V can decide how it is encoded by implementing [json.Marshaler].
+func (u *UserData) MarshalJSON() ([]byte, error) {
+	if u == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(u.V)
+}
+
+// FromJSON decodes a JSON value into the closest Value:
+//   - null is Nil, a boolean is Bool and a string is String.
+//   - a number is an Int if it is an integer that fits in int64, a BigInt if it is a larger integer, or a Float otherwise.
+//   - an array is a UserData of []Value.
+//   - an object is a UserData of [gendsl.Object].
+//
+// Bytes, Times and Durations are encoded as strings, so they are decoded as Strings.
+func FromJSON(data []byte) (Value, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, errors.WithMessage(err, "invalid JSON")
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: unexpected data after the top-level value")
+	}
+	return fromJSON(raw)
+}
+
+func fromJSON(raw any) (Value, error) {
+	switch x := raw.(type) {
+	case nil:
+		return Nil{}, nil
+	case bool:
+		return Bool(x), nil
+	case string:
+		return String(x), nil
+	case json.Number:
+		return jsonNumber(x)
+	case []any:
+		vs := make([]Value, 0, len(x))
+		for _, elem := range x {
+			v, err := fromJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, v)
+		}
+		return &UserData{V: vs}, nil
+	case map[string]any:
+		obj := make(Object, len(x))
+		for k, elem := range x {
+			v, err := fromJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = v
+		}
+		return &UserData{V: obj}, nil
+	}
+	return nil, errors.Errorf("unexpected JSON value of %T", raw)
+}
+
+func jsonNumber(n json.Number) (Value, error) {
+	s := string(n)
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := n.Int64(); err == nil {
+			return Int(i), nil
+		}
+		if i, ok := new(big.Int).SetString(s, 10); ok {
+			return NewBigInt(i), nil
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, errors.Errorf("invalid JSON number %s, it overflows float64", s)
+	}
+	return Float(f), nil
+}
@@ -0,0 +1,107 @@
+package gendsl
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+type point struct{ X, Y int }
+
+func (p point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.X, p.Y})
+}
+
+var _ = Describe("JSON", func() {
+	It("marshals the values", func() {
+		code, err := EvalExpr(`'(PLUS 1 2)`, NewEnv())
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		for v, want := range map[Value]string{
+			Int(-1):                               `-1`,
+			Uint(math.MaxUint64):                  `18446744073709551615`,
+			Float(1.5):                            `1.5`,
+			String("a\"b"):                        `"a\"b"`,
+			Bool(true):                            `true`,
+			Nil{}:                                 `null`,
+			bigInt("123456789012345678901234567"): `123456789012345678901234567`,
+			decimal("-2.50"):                      `-2.50`,
+			Time(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)): `"2024-01-02T03:04:05Z"`,
+			Duration(90 * time.Minute):                        `"1h30m0s"`,
+			NewSymbol("foo"):                                  `"foo"`,
+			NewKeyword("red"):                                 `"red"`,
+			NewRegexp(regexp.MustCompile(`\d+`)):              `"\\d+"`,
+			code:                                              `"(PLUS 1 2)"`,
+			&UserData{V: point{1, 2}}:                         `[1,2]`,
+			&UserData{V: []Value{Int(1), String("a"), Nil{}}}: `[1,"a",null]`,
+			&UserData{V: map[string]Value{"b": Float(0.5), "a": Bool(false)}}: `{"a":false,"b":0.5}`,
+		} {
+			b, err := json.Marshal(v)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), Repr(v))
+			gomega.Expect(string(b)).Should(gomega.Equal(want), Repr(v))
+		}
+		b, err := json.Marshal(Bytes{0xde, 0xad})
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(string(b)).Should(gomega.Equal(`"3q0="`))
+
+		_, err = json.Marshal(Procedure{})
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+		_, err = json.Marshal(Float(math.NaN()))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+
+	It("decodes JSON into the closest values", func() {
+		for text, want := range map[string]Value{
+			`null`:                Nil{},
+			`true`:                Bool(true),
+			`"a"`:                 String("a"),
+			`-12`:                 Int(-12),
+			`1.5`:                 Float(1.5),
+			`1e3`:                 Float(1000),
+			`9223372036854775808`: bigInt("9223372036854775808"),
+			` [1, "a", [null]] `:  &UserData{V: []Value{Int(1), String("a"), &UserData{V: []Value{Nil{}}}}},
+			`{"a": {"b": [2.5]}}`: &UserData{V: Object{"a": &UserData{V: Object{"b": &UserData{V: []Value{Float(2.5)}}}}}},
+			`{}`:                  &UserData{V: Object{}},
+		} {
+			v, err := FromJSON([]byte(text))
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), text)
+			gomega.Expect(v).Should(gomega.Equal(want), text)
+		}
+
+		for _, text := range []string{``, `{`, `1 2`, `[1,]`, `1e999`} {
+			_, err := FromJSON([]byte(text))
+			gomega.Expect(err).Should(gomega.HaveOccurred(), text)
+		}
+	})
+
+	It("decodes the objects that can be selected and compared", func() {
+		data := []byte(`{"user": {"name": "bob", "age": 30}, "tags": ["a", "b"]}`)
+		v, err := FromJSON(data)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		ret, err := EvalExpr(`input.user.name`, NewEnv().WithUserData("input", v.(*UserData)))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(ret).Should(gomega.Equal(String("bob")))
+
+		b, err := json.Marshal(v)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(b).Should(gomega.MatchJSON(data))
+		w, err := FromJSON(b)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(Equal(v, w)).Should(gomega.BeTrue())
+		h1, err := Hash(v)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		h2, err := Hash(w)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(h1).Should(gomega.Equal(h2))
+
+		w, err = FromJSON([]byte(`{"user": {"name": "bob", "age": 30.0}, "tags": ["a", "b"]}`))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(Equal(v, w)).Should(gomega.BeTrue())
+		w, err = FromJSON([]byte(`{"user": {"name": "bob"}, "tags": ["a", "b"]}`))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(Equal(v, w)).Should(gomega.BeFalse())
+	})
+})
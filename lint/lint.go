@@ -0,0 +1,154 @@
+// Package lint reviews scripts before they are evaluated.
+//
+// A [lint.Rule] visits the syntax tree of a script and reports [lint.Finding]s at the nodes it complains about.
+// Some rules are provided in this package and you can write your own:
+//
+//	linter := lint.New(env, append(lint.DefaultRules(), lint.UnreachableBranch(map[string]lint.Conditional{
+//		"IF": {Cond: 0, Then: 1, Else: 2},
+//	}))...)
+//	findings, err := linter.Lint(script)
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	// Severity tells how serious a finding is.
+	Severity int
+
+	// Finding is a problem found in a script.
+	Finding struct {
+		Rule     string // name of the rule that reports the finding
+		Severity Severity
+		Range    gendsl.Range
+		Message  string
+	}
+
+	// Rule checks a script by visiting its syntax tree.
+	Rule interface {
+		// Name returns the name of the rule, which should be unique.
+		Name() string
+		// Visitor returns the visitor to walk the syntax tree of a script, the script is skipped if it returns nil.
+		Visitor(pass *Pass) Visitor
+	}
+
+	// Visitor visits the nodes in the syntax tree.
+	Visitor interface {
+		// Visit is called for each node,
+		// the children of the node are visited by the returned Visitor unless it is nil.
+		Visit(n gendsl.Node) Visitor
+	}
+
+	// VisitorFunc is a Visitor that visits all the nodes with the same function.
+	VisitorFunc func(n gendsl.Node)
+
+	// Pass holds the information of the script being checked by a rule.
+	Pass struct {
+		Env    *gendsl.Env // env that the script is evaluated with, never nil
+		Script *gendsl.ParseContext
+
+		rule     Rule
+		findings *[]Finding
+	}
+
+	// Linter checks scripts with a set of rules.
+	Linter struct {
+		env   *gendsl.Env
+		rules []Rule
+	}
+)
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s (%s)",
+		f.Range.Begin.Line, f.Range.Begin.Symbol, f.Severity, f.Message, f.Rule)
+}
+
+// Visit calls f(n) and keeps visiting the children with f.
+func (f VisitorFunc) Visit(n gendsl.Node) Visitor {
+	f(n)
+	return f
+}
+
+// Report reports a finding at `r` for the rule.
+func (p *Pass) Report(r gendsl.Range, severity Severity, f string, args ...any) {
+	*p.findings = append(*p.findings, Finding{
+		Rule:     p.rule.Name(),
+		Severity: severity,
+		Range:    r,
+		Message:  fmt.Sprintf(f, args...),
+	})
+}
+
+// Walk traverses the tree of `n` in depth-first order with `v`.
+func Walk(v Visitor, n gendsl.Node) {
+	if n.IsZero() {
+		return
+	}
+	if v = v.Visit(n); v == nil {
+		return
+	}
+	for _, child := range n.Children() {
+		Walk(v, child)
+	}
+}
+
+// New creates a Linter with `rules`, `env` is the env that the scripts are evaluated with, nil is allowed.
+func New(env *gendsl.Env, rules ...Rule) *Linter {
+	if env == nil {
+		env = gendsl.NewEnv()
+	}
+	return &Linter{env: env, rules: rules}
+}
+
+// Lint parses `script` and checks it, the findings are sorted by their positions.
+// An [gendsl.SyntaxError] is returned if the script cannot be parsed.
+func (l *Linter) Lint(script string) ([]Finding, error) {
+	pc, err := gendsl.MakeParseContext(script)
+	if err != nil {
+		return nil, err
+	}
+	return l.LintContext(pc), nil
+}
+
+// LintContext checks a parsed script, the findings are sorted by their positions.
+func (l *Linter) LintContext(pc *gendsl.ParseContext) []Finding {
+	findings := make([]Finding, 0)
+	for _, rule := range l.rules {
+		pass := &Pass{
+			Env:      l.env,
+			Script:   pc,
+			rule:     rule,
+			findings: &findings,
+		}
+		if v := rule.Visitor(pass); v != nil {
+			Walk(v, pc.Root())
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Range.Begin.Offset < findings[j].Range.Begin.Offset
+	})
+	return findings
+}
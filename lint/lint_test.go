@@ -0,0 +1,108 @@
+package lint
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+)
+
+var _ = Describe("Lint", func() {
+	var (
+		nop = gendsl.Procedure{
+			Eval: func(_ *gendsl.EvalCtx, _ []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+				return gendsl.Nil{}, nil
+			},
+		}
+		env *gendsl.Env
+	)
+
+	BeforeEach(func() {
+		let := nop
+		let.Binder = gendsl.BindArg(0, 2)
+		old := nop
+		old.Deprecated = "use NOP instead"
+		env = gendsl.NewEnv().
+			WithProcedure("NOP", nop).
+			WithProcedure("IF", nop).
+			WithProcedure("LET", let).
+			WithProcedure("OLD", old).
+			WithInt("x", 1)
+	})
+
+	lint := func(script string, rules ...Rule) []Finding {
+		findings, err := New(env, rules...).Lint(script)
+		Expect(err).ShouldNot(HaveOccurred())
+		return findings
+	}
+
+	It("can report duplicate options", func() {
+		findings := lint(`(NOP #:a 1 #:b 2 #:a 3)`, DuplicateOption())
+		Expect(findings).Should(HaveLen(1))
+		Expect(findings[0].Rule).Should(Equal("duplicate-option"))
+		Expect(findings[0].Range.Begin.Symbol).Should(Equal(6))
+		Expect(findings[0].String()).Should(Equal(
+			"1:6: warning: option #:a is overridden by the one at line 1 symbol 18 (duplicate-option)"))
+	})
+
+	It("can report shadowed bindings", func() {
+		findings := lint(`(LET x 2 (NOP x))`, ShadowedBinding())
+		Expect(findings).Should(HaveLen(1))
+		Expect(findings[0].Message).Should(Equal("x shadows the one in the env"))
+		Expect(findings[0].Range.Begin.Symbol).Should(Equal(6))
+
+		Expect(lint(`(LET y 2 (NOP y))`, ShadowedBinding())).Should(BeEmpty())
+	})
+
+	It("can report deprecated procedures", func() {
+		findings := lint(`(NOP (OLD))`, DeprecatedProcedure())
+		Expect(findings).Should(HaveLen(1))
+		Expect(findings[0].Message).Should(Equal("OLD is deprecated: use NOP instead"))
+	})
+
+	It("can report deep nesting", func() {
+		findings := lint(`(NOP (NOP (NOP (NOP))) (NOP))`, DeepNesting(2))
+		Expect(findings).Should(HaveLen(1))
+		Expect(findings[0].Range.Begin.Symbol).Should(Equal(11))
+		Expect(lint(`(NOP (NOP))`, DeepNesting(2))).Should(BeEmpty())
+	})
+
+	It("can report unreachable branches", func() {
+		rule := UnreachableBranch(map[string]Conditional{"IF": {Cond: 0, Then: 1, Else: 2}})
+		findings := lint(`(IF #t (NOP) (OLD))`, rule)
+		Expect(findings).Should(HaveLen(1))
+		Expect(findings[0].Range.Begin.Symbol).Should(Equal(14))
+		Expect(findings[0].Message).Should(ContainSubstring("always true"))
+
+		findings = lint(`(IF nil 1 2)`, rule)
+		Expect(findings).Should(HaveLen(1))
+		Expect(findings[0].Range.Begin.Symbol).Should(Equal(9))
+
+		Expect(lint(`(IF x 1 2)`, rule)).Should(BeEmpty())
+	})
+
+	It("can sort the findings of different rules", func() {
+		findings := lint(`(NOP #:a 1 #:a 2 (OLD))`, DefaultRules()...)
+		Expect(findings).Should(HaveLen(2))
+		Expect(findings[0].Rule).Should(Equal("duplicate-option"))
+		Expect(findings[1].Rule).Should(Equal("deprecated-procedure"))
+	})
+
+	It("can run custom rules", func() {
+		rule := NewRule("no-x", func(pass *Pass) Visitor {
+			return VisitorFunc(func(n gendsl.Node) {
+				if n.Type() == gendsl.ExprTypeIdentifier && n.Identifier() == "x" {
+					pass.Report(n.Range(), SeverityInfo, "found x")
+				}
+			})
+		})
+		findings := lint(`(NOP x (NOP x))`, rule)
+		Expect(findings).Should(HaveLen(2))
+		Expect(findings[1].Severity).Should(Equal(SeverityInfo))
+	})
+
+	It("returns syntax errors", func() {
+		_, err := New(env).Lint(`(NOP`)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,159 @@
+package lint
+
+import (
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	rule struct {
+		name    string
+		visitor func(pass *Pass) Visitor
+	}
+
+	// Conditional describes a procedure like (IF {cond} {then} {else}) by the indexes of its arguments.
+	Conditional struct {
+		Cond, Then, Else int
+		// Truthy reports whether a condition is considered true, [gendsl.Truthy] by default.
+		Truthy func(gendsl.Value) bool
+	}
+
+	// nestingVisitor tracks the depth of the expressions.
+	nestingVisitor struct {
+		pass  *Pass
+		max   int
+		depth int
+	}
+)
+
+// DefaultMaxDepth is the max depth of nested expressions allowed in [lint.DefaultRules].
+const DefaultMaxDepth = 8
+
+// NewRule creates a Rule with its name and a function that returns the visitor for a script.
+func NewRule(name string, visitor func(pass *Pass) Visitor) Rule {
+	return rule{name: name, visitor: visitor}
+}
+
+func (r rule) Name() string               { return r.name }
+func (r rule) Visitor(pass *Pass) Visitor { return r.visitor(pass) }
+
+// DefaultRules returns the rules that need no configuration.
+func DefaultRules() []Rule {
+	return []Rule{
+		DuplicateOption(),
+		ShadowedBinding(),
+		DeprecatedProcedure(),
+		DeepNesting(DefaultMaxDepth),
+	}
+}
+
+// DuplicateOption reports the options declared more than once in an expression,
+// only the last one takes effect during evaluation.
+func DuplicateOption() Rule {
+	return NewRule("duplicate-option", func(pass *Pass) Visitor {
+		return VisitorFunc(func(n gendsl.Node) {
+			last := make(map[string]gendsl.OptionNode)
+			opts := n.Options()
+			for i := len(opts) - 1; i >= 0; i-- {
+				opt := opts[i]
+				if winner, ok := last[opt.Name]; ok {
+					pass.Report(opt.Range, SeverityWarning, "option #:%s is overridden by the one at line %d symbol %d",
+						opt.Name, winner.Range.Begin.Line, winner.Range.Begin.Symbol)
+					continue
+				}
+				last[opt.Name] = opt
+			}
+		})
+	})
+}
+
+// ShadowedBinding reports the identifiers defined by the script that shadow the ones in the env.
+// The identifiers are found by [gendsl.Procedure].Binder.
+func ShadowedBinding() Rule {
+	return NewRule("shadowed-binding", func(pass *Pass) Visitor {
+		return VisitorFunc(func(n gendsl.Node) {
+			for _, b := range pass.Env.Bindings(n) {
+				if b.Def.IsZero() {
+					continue
+				}
+				if _, ok := pass.Env.Lookup(b.Name); ok {
+					pass.Report(b.Def.Range(), SeverityWarning, "%s shadows the one in the env", b.Name)
+				}
+			}
+		})
+	})
+}
+
+// DeprecatedProcedure reports the use of the procedures with [gendsl.Procedure].Deprecated set.
+func DeprecatedProcedure() Rule {
+	return NewRule("deprecated-procedure", func(pass *Pass) Visitor {
+		return VisitorFunc(func(n gendsl.Node) {
+			if n.Type() != gendsl.ExprTypeExpr {
+				return
+			}
+			op := n.Operator()
+			v, ok := pass.Env.Lookup(op.Identifier())
+			if !ok {
+				return
+			}
+			if p, ok := v.(gendsl.Procedure); ok && p.Deprecated != "" {
+				pass.Report(op.Range(), SeverityWarning, "%s is deprecated: %s", op.Identifier(), p.Deprecated)
+			}
+		})
+	})
+}
+
+// DeepNesting reports the expressions nested deeper than `max`, the top level expression is at depth 1.
+func DeepNesting(max int) Rule {
+	return NewRule("deep-nesting", func(pass *Pass) Visitor {
+		return nestingVisitor{pass: pass, max: max}
+	})
+}
+
+func (v nestingVisitor) Visit(n gendsl.Node) Visitor {
+	if n.Type() != gendsl.ExprTypeExpr {
+		return nil
+	}
+	v.depth++
+	if v.depth > v.max {
+		v.pass.Report(n.Range(), SeverityWarning, "expression is nested deeper than %d", v.max)
+		return nil // the inner ones are not reported again
+	}
+	return v
+}
+
+// UnreachableBranch reports the branches that can never be evaluated
+// since the conditions of the `conds` are literals, `conds` are keyed by procedure names.
+func UnreachableBranch(conds map[string]Conditional) Rule {
+	return NewRule("unreachable-branch", func(pass *Pass) Visitor {
+		return VisitorFunc(func(n gendsl.Node) {
+			if n.Type() != gendsl.ExprTypeExpr {
+				return
+			}
+			c, ok := conds[n.Operator().Identifier()]
+			if !ok {
+				return
+			}
+			args := n.Args()
+			if c.Cond >= len(args) || args[c.Cond].Type() != gendsl.ExprTypeLiteral {
+				return
+			}
+			cond, err := args[c.Cond].Literal()
+			if err != nil {
+				return
+			}
+
+			truthy := c.Truthy
+			if truthy == nil {
+				truthy = gendsl.Truthy
+			}
+			unreachable, always := c.Else, "true"
+			if !truthy(cond) {
+				unreachable, always = c.Then, "false"
+			}
+			if unreachable < len(args) {
+				pass.Report(args[unreachable].Range(), SeverityWarning,
+					"branch is unreachable since the condition is always %s", always)
+			}
+		})
+	})
+}
@@ -0,0 +1,240 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	// document is a script opened in the editor.
+	document struct {
+		uri        string
+		version    int
+		text       []rune
+		lineStarts []int
+		analysis   *analysis
+	}
+
+	// analysis is the result of the static analysis of a script.
+	analysis struct {
+		pc          *gendsl.ParseContext // nil if the script cannot be parsed
+		diagnostics []Diagnostic
+		refs        map[gendsl.Node]gendsl.Binding // identifiers to the bindings they refer to
+		scopes      []scopeRange
+	}
+
+	// scopeRange is the range of the text where a scope is visible.
+	scopeRange struct {
+		begin, end int
+		scope      *gendsl.Scope
+	}
+)
+
+func newDocument(uri string, version int, text string) *document {
+	d := &document{uri: uri, version: version}
+	d.setText(text)
+	return d
+}
+
+func (d *document) setText(text string) {
+	d.text = []rune(text)
+	d.lineStarts = []int{0}
+	for i, r := range d.text {
+		if r == '\n' {
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+	d.analysis = nil
+}
+
+// position translates a rune offset into an LSP position whose character counts in UTF-16.
+func (d *document) position(offset int) Position {
+	if offset > len(d.text) {
+		offset = len(d.text)
+	}
+	line := sort.Search(len(d.lineStarts), func(i int) bool { return d.lineStarts[i] > offset }) - 1
+	start := d.lineStarts[line]
+	return Position{Line: line, Character: len(utf16.Encode(d.text[start:offset]))}
+}
+
+// offset translates an LSP position into a rune offset.
+func (d *document) offset(pos Position) int {
+	if pos.Line >= len(d.lineStarts) {
+		return len(d.text)
+	}
+	o, units := d.lineStarts[pos.Line], 0
+	for ; o < len(d.text) && d.text[o] != '\n' && units < pos.Character; o++ {
+		units += len(utf16.Encode([]rune{d.text[o]}))
+	}
+	return o
+}
+
+func (d *document) rangeOf(r gendsl.Range) Range {
+	return Range{Start: d.position(r.Begin.Offset), End: d.position(r.End.Offset)}
+}
+
+// analyze parses and checks the document with `cfg`.
+func (d *document) analyze(cfg *Config) *analysis {
+	if d.analysis != nil {
+		return d.analysis
+	}
+	a := &analysis{refs: make(map[gendsl.Node]gendsl.Binding)}
+	d.analysis = a
+
+	pc, err := gendsl.MakeParseContext(string(d.text))
+	if err != nil {
+		a.diagnostics = append(a.diagnostics, d.syntaxDiagnostic(err))
+		return a
+	}
+	a.pc = pc
+	w := &gendsl.Walker{
+		Env: cfg.Env,
+		Enter: func(n gendsl.Node, sc *gendsl.Scope) {
+			if sc.Node() == n {
+				r := n.Range()
+				a.scopes = append(a.scopes, scopeRange{r.Begin.Offset, r.End.Offset, sc})
+			}
+			switch n.Type() {
+			case gendsl.ExprTypeLiteral:
+				if _, err := n.Literal(); err != nil {
+					a.report(d, n.Range(), SeverityError, err.Error())
+				}
+			case gendsl.ExprTypeIdentifier:
+				a.resolve(d, cfg, n, sc)
+			}
+		},
+		Bind: func(_ gendsl.Node, bindings []gendsl.Binding) {
+			for _, b := range bindings {
+				if !b.Def.IsZero() {
+					a.refs[b.Def] = b
+				}
+			}
+		},
+	}
+	w.Walk(pc.Root(), gendsl.NewScope())
+	return a
+}
+
+func (d *document) syntaxDiagnostic(err error) Diagnostic {
+	var se *gendsl.SyntaxError
+	r := Range{}
+	if errors.As(err, &se) {
+		r.Start = d.position(d.lineOffset(se.BeginLine, se.BeginSym))
+		r.End = d.position(d.lineOffset(se.EndLine, se.EndSym))
+	}
+	return Diagnostic{
+		Range:    r,
+		Severity: SeverityError,
+		Source:   source,
+		Message:  strings.TrimSpace(err.Error()),
+	}
+}
+
+// lineOffset translates a line and a symbol in errors into a rune offset.
+func (d *document) lineOffset(line, symbol int) int {
+	if line < 1 {
+		return 0
+	}
+	if line > len(d.lineStarts) {
+		return len(d.text)
+	}
+	if symbol > 0 {
+		symbol--
+	}
+	return d.lineStarts[line-1] + symbol
+}
+
+func (a *analysis) resolve(d *document, cfg *Config, n gendsl.Node, sc *gendsl.Scope) {
+	id := n.Identifier()
+	if b, ok := sc.Lookup(id); ok {
+		a.refs[n] = b
+		return
+	}
+	if cfg.Env != nil {
+		if _, ok := cfg.Env.Lookup(id); ok {
+			return
+		}
+	}
+	a.report(d, n.Range(), SeverityWarning, fmt.Sprintf("unbounded variable: %s", id))
+}
+
+func (a *analysis) report(d *document, r gendsl.Range, severity int, msg string) {
+	a.diagnostics = append(a.diagnostics, Diagnostic{
+		Range:    d.rangeOf(r),
+		Severity: severity,
+		Source:   source,
+		Message:  msg,
+	})
+}
+
+// scopeAt returns the innermost scope visible at `offset`.
+func (a *analysis) scopeAt(offset int) *gendsl.Scope {
+	var (
+		ret  = gendsl.NewScope()
+		size = -1
+	)
+	for _, sr := range a.scopes {
+		if sr.begin <= offset && offset <= sr.end && (size < 0 || sr.end-sr.begin < size) {
+			ret, size = sr.scope, sr.end-sr.begin
+		}
+	}
+	return ret
+}
+
+// nodeAt returns the innermost identifier or literal node at `offset`.
+func (a *analysis) nodeAt(offset int) (gendsl.Node, bool) {
+	if a.pc == nil {
+		return gendsl.Node{}, false
+	}
+	var ret gendsl.Node
+	gendsl.Inspect(a.pc.Root(), func(n gendsl.Node) bool {
+		r := n.Range()
+		if offset < r.Begin.Offset || offset > r.End.Offset {
+			return false
+		}
+		if n.Type() != gendsl.ExprTypeExpr {
+			ret = n
+		}
+		return true
+	})
+	return ret, !ret.IsZero()
+}
+
+// optionAt returns the option whose name is at `offset` and the expression it belongs to.
+func (a *analysis) optionAt(offset int) (gendsl.OptionNode, gendsl.Node, bool) {
+	if a.pc == nil {
+		return gendsl.OptionNode{}, gendsl.Node{}, false
+	}
+	var (
+		ret  gendsl.OptionNode
+		call gendsl.Node
+	)
+	gendsl.Inspect(a.pc.Root(), func(n gendsl.Node) bool {
+		for _, opt := range n.Options() {
+			if opt.Range.Begin.Offset <= offset && offset < opt.Value.Range().Begin.Offset {
+				ret, call = opt, n
+			}
+		}
+		return true
+	})
+	return ret, call, !call.IsZero()
+}
+
+// describe returns the information about a binding for hover and completion.
+func describe(b gendsl.Binding) string {
+	if b.Value != nil {
+		return b.Value.Type().String() + " = " + gendsl.Repr(b.Value)
+	}
+	if !b.Def.IsZero() {
+		r := b.Def.Range()
+		return "defined at line " + strconv.Itoa(r.Begin.Line)
+	}
+	return ""
+}
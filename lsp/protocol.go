@@ -0,0 +1,157 @@
+package lsp
+
+import "encoding/json"
+
+// The subset of the Language Server Protocol used by the server,
+// see https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/
+
+type (
+	message struct {
+		JSONRPC string           `json:"jsonrpc"`
+		ID      *json.RawMessage `json:"id,omitempty"`
+		Method  string           `json:"method,omitempty"`
+		Params  json.RawMessage  `json:"params,omitempty"`
+		Result  json.RawMessage  `json:"result,omitempty"`
+		Error   *responseError   `json:"error,omitempty"`
+	}
+
+	responseError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	Position struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	}
+
+	Range struct {
+		Start Position `json:"start"`
+		End   Position `json:"end"`
+	}
+
+	Location struct {
+		URI   string `json:"uri"`
+		Range Range  `json:"range"`
+	}
+
+	Diagnostic struct {
+		Range    Range  `json:"range"`
+		Severity int    `json:"severity"`
+		Source   string `json:"source"`
+		Message  string `json:"message"`
+	}
+
+	TextDocumentItem struct {
+		URI        string `json:"uri"`
+		LanguageID string `json:"languageId"`
+		Version    int    `json:"version"`
+		Text       string `json:"text"`
+	}
+
+	TextDocumentIdentifier struct {
+		URI string `json:"uri"`
+	}
+
+	VersionedTextDocumentIdentifier struct {
+		URI     string `json:"uri"`
+		Version int    `json:"version"`
+	}
+
+	TextDocumentContentChangeEvent struct {
+		Range *Range `json:"range,omitempty"`
+		Text  string `json:"text"`
+	}
+
+	DidOpenTextDocumentParams struct {
+		TextDocument TextDocumentItem `json:"textDocument"`
+	}
+
+	DidChangeTextDocumentParams struct {
+		TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+		ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+	}
+
+	DidCloseTextDocumentParams struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+	}
+
+	TextDocumentPositionParams struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+		Position     Position               `json:"position"`
+	}
+
+	DocumentFormattingParams struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+	}
+
+	PublishDiagnosticsParams struct {
+		URI         string       `json:"uri"`
+		Version     int          `json:"version"`
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}
+
+	CompletionItem struct {
+		Label         string         `json:"label"`
+		Kind          int            `json:"kind,omitempty"`
+		Detail        string         `json:"detail,omitempty"`
+		Documentation *MarkupContent `json:"documentation,omitempty"`
+	}
+
+	CompletionList struct {
+		IsIncomplete bool             `json:"isIncomplete"`
+		Items        []CompletionItem `json:"items"`
+	}
+
+	MarkupContent struct {
+		Kind  string `json:"kind"`
+		Value string `json:"value"`
+	}
+
+	Hover struct {
+		Contents MarkupContent `json:"contents"`
+		Range    *Range        `json:"range,omitempty"`
+	}
+
+	TextEdit struct {
+		Range   Range  `json:"range"`
+		NewText string `json:"newText"`
+	}
+
+	CompletionOptions struct {
+		TriggerCharacters []string `json:"triggerCharacters,omitempty"`
+	}
+
+	ServerCapabilities struct {
+		TextDocumentSync           int                `json:"textDocumentSync"`
+		CompletionProvider         *CompletionOptions `json:"completionProvider,omitempty"`
+		HoverProvider              bool               `json:"hoverProvider"`
+		DefinitionProvider         bool               `json:"definitionProvider"`
+		DocumentFormattingProvider bool               `json:"documentFormattingProvider"`
+	}
+
+	ServerInfo struct {
+		Name string `json:"name"`
+	}
+
+	InitializeResult struct {
+		Capabilities ServerCapabilities `json:"capabilities"`
+		ServerInfo   ServerInfo         `json:"serverInfo"`
+	}
+)
+
+const (
+	SeverityError   = 1
+	SeverityWarning = 2
+
+	CompletionKindFunction = 3
+	CompletionKindVariable = 6
+	CompletionKindProperty = 10
+
+	textDocumentSyncFull = 1
+
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
@@ -0,0 +1,422 @@
+// Package lsp implements a Language Server Protocol server for DSLs built with gendsl.
+//
+// The server speaks LSP over a pair of streams(usually the stdio) and provides:
+//   - diagnostics of syntax errors, invalid literals and unbounded identifiers
+//   - completion of identifiers and options
+//   - hover documents for identifiers and options
+//   - go-to-definition for the identifiers defined by the script
+//   - document formatting
+//
+// Since each DSL has its own procedures, the host program supplies them by [lsp.Config]:
+//
+//	env := gendsl.NewEnv().WithProcedure("LET", gendsl.Procedure{
+//		Eval:   letOp,
+//		Binder: gendsl.BindArg(0, 2), // (LET {name} {value} {body})
+//	})
+//	server := lsp.NewServer(lsp.Config{Env: env})
+//	err := server.Serve(os.Stdin, os.Stdout)
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ccbhj/gendsl"
+	"github.com/ccbhj/gendsl/format"
+	"github.com/ccbhj/gendsl/internal/wire"
+)
+
+const source = "gendsl"
+
+type (
+	// Config specifies what the DSL looks like.
+	Config struct {
+		// Env is the env that the scripts are evaluated with.
+		// Its identifiers are completed and documented by [gendsl.Procedure].Doc and [gendsl.Procedure].Options,
+		// and the identifiers defined by the script are found by [gendsl.Procedure].Binder.
+		Env *gendsl.Env
+	}
+
+	// Server is a language server, create it by [lsp.NewServer].
+	Server struct {
+		cfg      Config
+		docs     map[string]*document
+		out      io.Writer
+		shutdown bool
+		exit     bool
+	}
+)
+
+// NewServer creates a language server with `cfg`.
+func NewServer(cfg Config) *Server {
+	if cfg.Env == nil {
+		cfg.Env = gendsl.NewEnv()
+	}
+	return &Server{
+		cfg:  cfg,
+		docs: make(map[string]*document),
+	}
+}
+
+// Serve reads requests from `r` and writes responses and notifications to `w`,
+// until the client sends the exit notification or `r` is closed.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.out = w
+	br := bufio.NewReader(r)
+	for {
+		body, err := wire.ReadMessage(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, rerr := s.handle(&msg)
+		if s.exit {
+			return nil
+		}
+		if msg.ID == nil { // notification
+			continue
+		}
+		if err := s.reply(msg.ID, result, rerr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) (any, *responseError) {
+	var err error
+	switch msg.Method {
+	case "initialize":
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:           textDocumentSyncFull,
+				CompletionProvider:         &CompletionOptions{TriggerCharacters: []string{":", "(", "."}},
+				HoverProvider:              true,
+				DefinitionProvider:         true,
+				DocumentFormattingProvider: true,
+			},
+			ServerInfo: ServerInfo{Name: source},
+		}, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "exit":
+		s.exit = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			doc := newDocument(params.TextDocument.URI, params.TextDocument.Version, params.TextDocument.Text)
+			s.docs[doc.uri] = doc
+			err = s.publishDiagnostics(doc)
+		}
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			doc, ok := s.docs[params.TextDocument.URI]
+			if !ok || len(params.ContentChanges) == 0 {
+				return nil, nil
+			}
+			doc.version = params.TextDocument.Version
+			doc.setText(params.ContentChanges[len(params.ContentChanges)-1].Text)
+			err = s.publishDiagnostics(doc)
+		}
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			delete(s.docs, params.TextDocument.URI)
+		}
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			return s.completion(params), nil
+		}
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			return s.hover(params), nil
+		}
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			return s.definition(params), nil
+		}
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			return s.formatting(params)
+		}
+	default:
+		if msg.ID != nil {
+			return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+		}
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil, nil
+}
+
+func (s *Server) reply(id *json.RawMessage, result any, rerr *responseError) error {
+	msg := message{JSONRPC: "2.0", ID: id, Error: rerr}
+	if rerr == nil {
+		bs, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		msg.Result = bs
+	}
+	return s.send(msg)
+}
+
+func (s *Server) notify(method string, params any) error {
+	bs, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return s.send(message{JSONRPC: "2.0", Method: method, Params: bs})
+}
+
+func (s *Server) send(msg message) error {
+	bs, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return wire.WriteMessage(s.out, bs)
+}
+
+func (s *Server) publishDiagnostics(doc *document) error {
+	diagnostics := doc.analyze(&s.cfg).diagnostics
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     doc.version,
+		Diagnostics: diagnostics,
+	})
+}
+
+func (s *Server) completion(params TextDocumentPositionParams) CompletionList {
+	ret := CompletionList{Items: []CompletionItem{}}
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return ret
+	}
+	offset := doc.offset(params.Position)
+	begin := offset
+	for begin > 0 && isIdentifierChar(doc.text[begin-1]) {
+		begin--
+	}
+	prefix := string(doc.text[begin:offset])
+
+	// complete the options of the procedure of the enclosing expression
+	if begin >= 2 && string(doc.text[begin-2:begin]) == "#:" {
+		p, ok := s.cfg.Env.Lookup(enclosingOperator(doc.text[:begin]))
+		if proc, isProc := p.(gendsl.Procedure); ok && isProc {
+			for name, desc := range proc.Options {
+				if strings.HasPrefix(name, prefix) {
+					ret.Items = append(ret.Items, CompletionItem{
+						Label:         name,
+						Kind:          CompletionKindProperty,
+						Documentation: markdown(desc),
+					})
+				}
+			}
+		}
+		sortItems(ret.Items)
+		return ret
+	}
+
+	seen := make(map[string]bool)
+	for sc := s.completionScope(doc, offset); sc != nil; sc = sc.Parent() {
+		sc.Range(func(b gendsl.Binding) bool {
+			if !seen[b.Name] && strings.HasPrefix(b.Name, prefix) {
+				seen[b.Name] = true
+				ret.Items = append(ret.Items, CompletionItem{
+					Label:  b.Name,
+					Kind:   CompletionKindVariable,
+					Detail: describe(b),
+				})
+			}
+			return true
+		})
+	}
+	s.cfg.Env.Range(func(id string, v gendsl.Value) bool {
+		if !seen[id] && strings.HasPrefix(id, prefix) {
+			ret.Items = append(ret.Items, envItem(id, v))
+		}
+		return true
+	})
+	sortItems(ret.Items)
+	return ret
+}
+
+// completionScope returns the scope at `offset`,
+// the unclosed parentheses are closed to make the script parseable while editing.
+func (s *Server) completionScope(doc *document, offset int) *gendsl.Scope {
+	a := doc.analyze(&s.cfg)
+	if a.pc == nil {
+		repaired := newDocument(doc.uri, doc.version, closeParens(string(doc.text)))
+		a = repaired.analyze(&s.cfg)
+	}
+	return a.scopeAt(offset)
+}
+
+func (s *Server) hover(params TextDocumentPositionParams) *Hover {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+	offset := doc.offset(params.Position)
+	a := doc.analyze(&s.cfg)
+
+	if opt, call, ok := a.optionAt(offset); ok {
+		p, _ := s.cfg.Env.Lookup(call.Operator().Identifier())
+		proc, _ := p.(gendsl.Procedure)
+		r := doc.rangeOf(opt.Range)
+		return &Hover{Contents: *markdown("`#:" + opt.Name + "`\n\n" + proc.Options[opt.Name]), Range: &r}
+	}
+
+	n, ok := a.nodeAt(offset)
+	if !ok || n.Type() != gendsl.ExprTypeIdentifier {
+		return nil
+	}
+	r := doc.rangeOf(n.Range())
+	id := n.Identifier()
+	if b, ok := a.refs[n]; ok {
+		return &Hover{Contents: *markdown("`" + id + "` " + describe(b)), Range: &r}
+	}
+	v, ok := s.cfg.Env.Lookup(id)
+	if !ok {
+		return nil
+	}
+	return &Hover{Contents: *envItem(id, v).Documentation, Range: &r}
+}
+
+func (s *Server) definition(params TextDocumentPositionParams) []Location {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+	a := doc.analyze(&s.cfg)
+	n, ok := a.nodeAt(doc.offset(params.Position))
+	if !ok {
+		return nil
+	}
+	b, ok := a.refs[n]
+	if !ok || b.Def.IsZero() {
+		return nil
+	}
+	return []Location{{URI: doc.uri, Range: doc.rangeOf(b.Def.Range())}}
+}
+
+func (s *Server) formatting(params DocumentFormattingParams) ([]TextEdit, *responseError) {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	formatted, err := format.Source(string(doc.text))
+	if err != nil {
+		return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+	if formatted == string(doc.text) {
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{
+		Range:   Range{Start: doc.position(0), End: doc.position(len(doc.text))},
+		NewText: formatted,
+	}}, nil
+}
+
+func envItem(id string, v gendsl.Value) CompletionItem {
+	p, ok := v.(gendsl.Procedure)
+	if !ok {
+		detail := v.Type().String() + " = " + gendsl.Repr(v)
+		return CompletionItem{
+			Label:         id,
+			Kind:          CompletionKindVariable,
+			Detail:        detail,
+			Documentation: markdown("`" + id + "` " + detail),
+		}
+	}
+
+	doc := "```\n(" + id + " ...)\n```\n\n" + p.Doc
+	if len(p.Options) > 0 {
+		names := make([]string, 0, len(p.Options))
+		for name := range p.Options {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		doc += "\n\nOptions:"
+		for _, name := range names {
+			doc += "\n- `#:" + name + "` " + p.Options[name]
+		}
+	}
+	return CompletionItem{
+		Label:         id,
+		Kind:          CompletionKindFunction,
+		Detail:        "procedure",
+		Documentation: markdown(doc),
+	}
+}
+
+func markdown(s string) *MarkupContent {
+	return &MarkupContent{Kind: "markdown", Value: s}
+}
+
+func sortItems(items []CompletionItem) {
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+}
+
+func isIdentifierChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		strings.ContainsRune("~!@$%^&*_?|<>-", r)
+}
+
+// enclosingOperator returns the operator of the innermost unclosed expression in `text`.
+func enclosingOperator(text []rune) string {
+	depth := 0
+	for i := len(text) - 1; i >= 0; i-- {
+		switch text[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			j := i + 1
+			for ; j < len(text) && isIdentifierChar(text[j]); j++ {
+			}
+			return string(text[i+1 : j])
+		}
+	}
+	return ""
+}
+
+// closeParens appends the missing ')' to `text`.
+func closeParens(text string) string {
+	depth := strings.Count(text, "(") - strings.Count(text, ")")
+	if depth <= 0 {
+		return text
+	}
+	return text + strings.Repeat(")", depth)
+}
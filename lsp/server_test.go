@@ -0,0 +1,235 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+	"github.com/ccbhj/gendsl/internal/wire"
+)
+
+// client talks to a Server in the same process.
+type client struct {
+	w      io.WriteCloser
+	r      *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+func newClient(s *Server) *client {
+	cr, sw := io.Pipe()
+	sr, cw := io.Pipe()
+	c := &client{w: cw, r: bufio.NewReader(cr), done: make(chan error, 1)}
+	go func() {
+		c.done <- s.Serve(sr, sw)
+		sw.Close()
+	}()
+	return c
+}
+
+func (c *client) send(msg map[string]any) {
+	msg["jsonrpc"] = "2.0"
+	bs, err := json.Marshal(msg)
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(wire.WriteMessage(c.w, bs)).Should(Succeed())
+}
+
+func (c *client) receive() message {
+	bs, err := wire.ReadMessage(c.r)
+	Expect(err).ShouldNot(HaveOccurred())
+	var msg message
+	Expect(json.Unmarshal(bs, &msg)).Should(Succeed())
+	return msg
+}
+
+// call sends a request and decodes its result into `result`.
+func (c *client) call(method string, params any, result any) *responseError {
+	c.nextID++
+	c.send(map[string]any{"id": c.nextID, "method": method, "params": params})
+	msg := c.receive()
+	Expect(msg.ID).ShouldNot(BeNil())
+	if msg.Error != nil {
+		return msg.Error
+	}
+	Expect(json.Unmarshal(msg.Result, result)).Should(Succeed())
+	return nil
+}
+
+// open opens a document and returns the diagnostics published.
+func (c *client) open(uri, text string) []Diagnostic {
+	c.send(map[string]any{"method": "textDocument/didOpen", "params": DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "gendsl", Version: 1, Text: text},
+	}})
+	msg := c.receive()
+	Expect(msg.Method).Should(Equal("textDocument/publishDiagnostics"))
+	var params PublishDiagnosticsParams
+	Expect(json.Unmarshal(msg.Params, &params)).Should(Succeed())
+	return params.Diagnostics
+}
+
+func at(uri string, line, char int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: line, Character: char},
+	}
+}
+
+var _ = Describe("Server", func() {
+	const uri = "file:///test.dsl"
+	var c *client
+
+	BeforeEach(func() {
+		env := gendsl.NewEnv().WithBuiltins().
+			WithInt("ONE", 1).
+			WithProcedure("PRINTLN", gendsl.Procedure{
+				Doc:     "PRINTLN prints its arguments.",
+				Options: map[string]string{"out": "where to print", "sep": "separator"},
+			}).
+			WithProcedure("LET", gendsl.Procedure{
+				Doc:    "(LET {name} {value} {body})",
+				Binder: gendsl.BindArg(0, 2),
+			})
+		c = newClient(NewServer(Config{Env: env}))
+
+		var result InitializeResult
+		Expect(c.call("initialize", map[string]any{}, &result)).Should(BeNil())
+		Expect(result.Capabilities.HoverProvider).Should(BeTrue())
+	})
+
+	AfterEach(func() {
+		var result any
+		Expect(c.call("shutdown", nil, &result)).Should(BeNil())
+		c.send(map[string]any{"method": "exit"})
+		Eventually(c.done).Should(Receive(BeNil()))
+	})
+
+	It("publishes syntax errors", func() {
+		diags := c.open(uri, "(PRINTLN\n  1")
+		Expect(diags).Should(HaveLen(1))
+		Expect(diags[0].Severity).Should(Equal(SeverityError))
+		Expect(diags[0].Message).Should(ContainSubstring("parse error"))
+		Expect(diags[0].Range.Start.Line).Should(Equal(1))
+	})
+
+	It("publishes invalid regexp literals", func() {
+		diags := c.open(uri, "(PRINTLN\n  #rx\"(foo\")")
+		Expect(diags).Should(HaveLen(1))
+		Expect(diags[0].Message).Should(ContainSubstring("invalid regexp literal"))
+		Expect(diags[0].Range).Should(Equal(Range{Start: Position{1, 2}, End: Position{1, 11}}))
+	})
+
+	It("publishes unbounded identifiers", func() {
+		diags := c.open(uri, "(LET foo ONE\n  (PRINTLN foo bar))")
+		Expect(diags).Should(HaveLen(1))
+		Expect(diags[0].Message).Should(Equal("unbounded variable: bar"))
+		Expect(diags[0].Range).Should(Equal(Range{Start: Position{1, 15}, End: Position{1, 18}}))
+
+		// clear the diagnostics after fixing
+		c.send(map[string]any{"method": "textDocument/didChange", "params": DidChangeTextDocumentParams{
+			TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+			ContentChanges: []TextDocumentContentChangeEvent{{Text: "(LET foo ONE (PRINTLN foo))"}},
+		}})
+		var params PublishDiagnosticsParams
+		Expect(json.Unmarshal(c.receive().Params, &params)).Should(Succeed())
+		Expect(params.Diagnostics).Should(BeEmpty())
+	})
+
+	It("does not resolve the code quoted", func() {
+		diags := c.open(uri, "(PRINTLN '(foo bar) (quote baz) `(foo ,ONE ,qux `(foo ,bar ,,quux)))")
+		Expect(diags).Should(HaveLen(2))
+		Expect(diags[0].Message).Should(Equal("unbounded variable: qux"))
+		Expect(diags[1].Message).Should(Equal("unbounded variable: quux"))
+	})
+
+	It("resolves the values in the interpolated strings", func() {
+		diags := c.open(uri, "(PRINTLN #f\"${ONE} and ${(PRINTLN bar)}\")")
+		Expect(diags).Should(HaveLen(1))
+		Expect(diags[0].Message).Should(Equal("unbounded variable: bar"))
+		Expect(diags[0].Range).Should(Equal(Range{Start: Position{0, 34}, End: Position{0, 37}}))
+	})
+
+	It("resolves the macros and their parameters", func() {
+		diags := c.open(uri, "(defmacro TWICE (x &rest xs) `(PRINTLN ,x ,x ,@xs) (TWICE ONE 2 y))")
+		Expect(diags).Should(HaveLen(1))
+		Expect(diags[0].Message).Should(Equal("unbounded variable: y"))
+	})
+
+	It("completes identifiers in the env and the script", func() {
+		c.open(uri, "(LET foo 1 (PRINTLN f))")
+		var list CompletionList
+		Expect(c.call("textDocument/completion", at(uri, 0, 21), &list)).Should(BeNil())
+		Expect(list.Items).Should(HaveLen(1))
+		Expect(list.Items[0].Label).Should(Equal("foo"))
+
+		Expect(c.call("textDocument/completion", at(uri, 0, 13), &list)).Should(BeNil())
+		Expect(list.Items).Should(HaveLen(1))
+		Expect(list.Items[0].Label).Should(Equal("PRINTLN"))
+
+		Expect(c.call("textDocument/completion", at(uri, 0, 12), &list)).Should(BeNil())
+		Expect(list.Items).Should(HaveLen(8)) // with the builtins
+	})
+
+	It("completes identifiers while the script is not complete", func() {
+		c.open(uri, "(LET foo 1 (PRINTLN fo")
+		var list CompletionList
+		Expect(c.call("textDocument/completion", at(uri, 0, 22), &list)).Should(BeNil())
+		Expect(list.Items).Should(HaveLen(1))
+		Expect(list.Items[0].Label).Should(Equal("foo"))
+	})
+
+	It("completes options", func() {
+		c.open(uri, "(PRINTLN #:o")
+		var list CompletionList
+		Expect(c.call("textDocument/completion", at(uri, 0, 12), &list)).Should(BeNil())
+		Expect(list.Items).Should(HaveLen(1))
+		Expect(list.Items[0].Label).Should(Equal("out"))
+	})
+
+	It("shows documents on hover", func() {
+		c.open(uri, "(PRINTLN #:out \"stderr\" ONE)")
+		var hover Hover
+		Expect(c.call("textDocument/hover", at(uri, 0, 3), &hover)).Should(BeNil())
+		Expect(hover.Contents.Value).Should(ContainSubstring("PRINTLN prints its arguments."))
+		Expect(hover.Contents.Value).Should(ContainSubstring("`#:sep` separator"))
+
+		Expect(c.call("textDocument/hover", at(uri, 0, 12), &hover)).Should(BeNil())
+		Expect(hover.Contents.Value).Should(ContainSubstring("where to print"))
+
+		Expect(c.call("textDocument/hover", at(uri, 0, 25), &hover)).Should(BeNil())
+		Expect(hover.Contents.Value).Should(ContainSubstring("int = 1"))
+	})
+
+	It("goes to the definition of a binding in the script", func() {
+		c.open(uri, "(LET foo 1\n  (PRINTLN foo))")
+		var locs []Location
+		Expect(c.call("textDocument/definition", at(uri, 1, 12), &locs)).Should(BeNil())
+		Expect(locs).Should(Equal([]Location{{
+			URI:   uri,
+			Range: Range{Start: Position{0, 5}, End: Position{0, 8}},
+		}}))
+
+		Expect(c.call("textDocument/definition", at(uri, 1, 4), &locs)).Should(BeNil())
+		Expect(locs).Should(BeEmpty())
+	})
+
+	It("formats the document", func() {
+		c.open(uri, "(PRINTLN   1 ; one\n 2)")
+		var edits []TextEdit
+		Expect(c.call("textDocument/formatting", DocumentFormattingParams{
+			TextDocument: TextDocumentIdentifier{URI: uri},
+		}, &edits)).Should(BeNil())
+		Expect(edits).Should(HaveLen(1))
+		Expect(edits[0].NewText).Should(Equal("(PRINTLN\n  1 ; one\n  2)\n"))
+	})
+
+	It("reports unknown methods", func() {
+		var result any
+		err := c.call("workspace/unknown", nil, &result)
+		Expect(err).ShouldNot(BeNil())
+		Expect(err.Code).Should(Equal(codeMethodNotFound))
+	})
+})
@@ -0,0 +1,288 @@
+package gendsl
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxMacroDepth limits the expansions inside an expansion, so that a recursive macro cannot expand forever.
+const maxMacroDepth = 256
+
+type (
+	// macro is defined by (defmacro name (params...) template body...).
+	macro struct {
+		name     string
+		params   []string
+		rest     string // parameter after &rest, empty if the macro takes no rest arguments
+		template Code
+	}
+
+	// macroScope holds the macros visible in the body of defmacro.
+	macroScope struct {
+		parent *macroScope
+		m      *macro
+	}
+
+	// expander expands the macros in a script.
+	expander struct {
+		evalCtx *EvalCtx
+		depth   int
+	}
+)
+
+func (s *macroScope) lookup(name string) (*macro, bool) {
+	for ; s != nil; s = s.parent {
+		if s.m.name == name {
+			return s.m, true
+		}
+	}
+	return nil, false
+}
+
+// MacroExpand expands the macros defined by defmacro in the script, `evalCtx` is used to evaluate the templates of the macros.
+// The Code returned evaluates the script expanded, and its Text shows the script expanded for debugging.
+// The nodes in the expansion are still the nodes in the script,
+// so the positions in the errors and the [gendsl.Expr]s refer to the original source.
+//
+// The macros are expanded by [gendsl.ParseContext.Eval] before evaluation if the script defines any of them.
+func (c *ParseContext) MacroExpand(evalCtx *EvalCtx) (Code, error) {
+	root := c.Root().node
+	if !c.macros {
+		return Code{node: root, pc: c}, nil
+	}
+	x := &expander{evalCtx: evalCtx}
+	pc, err := x.expand(c, root, nil)
+	if err != nil {
+		return Code{}, err
+	}
+	return Code{node: root, pc: pc}, nil
+}
+
+// expand returns a ParseContext that has the macro calls under `node` replaced with their expansions.
+func (x *expander) expand(c *ParseContext, node *node32, scope *macroScope) (*ParseContext, error) {
+	holes := make(map[*node32][]Value)
+	var walk func(n *node32, scope *macroScope) error
+	walk = func(n *node32, scope *macroScope) error {
+		if vs, ok := c.holes[n]; ok { // filled by a quasiquote or expanded already
+			expanded := make([]Value, 0, len(vs))
+			for _, v := range vs {
+				if code, ok := v.(Code); ok {
+					pc, err := x.expand(code.pc, code.node, scope)
+					if err != nil {
+						return err
+					}
+					v = Code{node: code.node, pc: pc}
+				}
+				expanded = append(expanded, v)
+			}
+			holes[n] = expanded
+			return nil
+		}
+
+		switch n.pegRule {
+		case ruleQuote, ruleQuasiquote, ruleSpacing: // nothing to expand in the comments
+			return nil
+		case ruleExpression:
+			name, args := splitExpression(c, n)
+			if m, ok := scope.lookup(name); ok {
+				v, err := x.call(m, c, n, args, scope)
+				if err != nil {
+					return err
+				}
+				holes[n] = []Value{v}
+				return nil
+			}
+			if v, _ := x.evalCtx.Lookup(name); name == "defmacro" && IsBuiltin(v, name) {
+				m, err := defineMacro(c, n, args)
+				if err != nil {
+					return err
+				}
+				scope = &macroScope{parent: scope, m: m}
+				for _, arg := range args[3:] {
+					if err := walk(arg, scope); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+		for cur := n.up; cur != nil; cur = cur.next {
+			if err := walk(cur, scope); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(node, scope); err != nil {
+		return nil, err
+	}
+	if len(holes) == 0 {
+		return c, nil
+	}
+
+	expanded := *c
+	expanded.holes = holes
+	for n, vs := range c.holes {
+		if _, ok := holes[n]; !ok {
+			expanded.holes[n] = vs
+		}
+	}
+	return &expanded, nil
+}
+
+// call expands a call to macro `m`.
+func (x *expander) call(m *macro, c *ParseContext, node *node32, args []*node32, scope *macroScope) (Value, error) {
+	if x.depth >= maxMacroDepth {
+		return nil, evalErrorf(c, node, "macro %s expands too deep", m.name)
+	}
+	for cur := node.up; cur != nil; cur = cur.next {
+		if cur.pegRule == ruleOption {
+			return nil, evalErrorf(c, cur, "macro %s accepts no option", m.name)
+		}
+	}
+	if len(args) < len(m.params) || (m.rest == "" && len(args) > len(m.params)) {
+		return nil, evalErrorf(c, node, "macro %s expects %d argument(s), but got %d", m.name, len(m.params), len(args))
+	}
+
+	env := NewEnv()
+	codes := make([]Value, 0, len(args))
+	for _, arg := range args {
+		codes = append(codes, Code{node: arg, pc: c})
+	}
+	for i, param := range m.params {
+		env.WithValue(param, codes[i])
+	}
+	if m.rest != "" {
+		env.WithUserData(m.rest, &UserData{V: codes[len(m.params):]})
+	}
+	v, err := m.template.Eval(NewEvalCtx(x.evalCtx, x.evalCtx.UserData, env))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "fail to expand macro %s", m.name)
+	}
+
+	code, ok := v.(Code)
+	if !ok {
+		return v, nil
+	}
+	x.depth++
+	defer func() { x.depth-- }()
+	pc, err := x.expand(code.pc, code.node, scope)
+	if err != nil {
+		return nil, err
+	}
+	return Code{node: code.node, pc: pc}, nil
+}
+
+// defineMacro reads (defmacro name (params...) template body...).
+func defineMacro(c *ParseContext, node *node32, args []*node32) (*macro, error) {
+	if len(args) < 3 {
+		return nil, evalErrorf(c, node, "defmacro expects a name, the parameters and a template")
+	}
+	if args[0].pegRule != ruleIdentifier {
+		return nil, evalErrorf(c, args[0], "invalid macro name %s", c.nodeText(args[0]))
+	}
+	m := &macro{
+		name:     readIdentifierText(c, args[0]),
+		template: Code{node: args[2], pc: c},
+	}
+
+	params, ok := macroParams(c, args[1])
+	if !ok {
+		return nil, evalErrorf(c, args[1], "the parameters of a macro should be like (a b &rest c) or nil")
+	}
+	for i := 0; i < len(params); i++ {
+		if params[i] != "&rest" {
+			m.params = append(m.params, params[i])
+			continue
+		}
+		if i != len(params)-2 {
+			return nil, evalErrorf(c, args[1], "&rest should be followed by the last parameter")
+		}
+		m.rest = params[i+1]
+		break
+	}
+	return m, nil
+}
+
+// macroParams reads the names in the parameters of a macro like (a b &rest c), or nil for no parameter.
+func macroParams(c *ParseContext, node *node32) ([]string, bool) {
+	switch node.pegRule {
+	case ruleLiteral:
+		return nil, node.up.pegRule == ruleNilLiteral
+	case ruleExpression:
+		name, args := splitExpression(c, node)
+		params := []string{name}
+		for cur := node.up; cur != nil; cur = cur.next {
+			if cur.pegRule == ruleOption {
+				return nil, false
+			}
+		}
+		for _, arg := range args {
+			if arg.pegRule != ruleIdentifier {
+				return nil, false
+			}
+			params = append(params, readIdentifierText(c, arg))
+		}
+		return params, true
+	}
+	return nil, false
+}
+
+// splitExpression returns the name of the operator and the nodes of the arguments of an expression.
+func splitExpression(c *ParseContext, node *node32) (string, []*node32) {
+	var (
+		name string
+		args []*node32
+	)
+	for cur := node.up; cur != nil; cur = cur.next {
+		switch cur.pegRule {
+		case ruleOperator:
+			name = strings.TrimSpace(c.nodeText(cur.up))
+		case ruleValue:
+			args = append(args, cur.up)
+		}
+	}
+	return name, args
+}
+
+// definesMacros reports whether a script may define macros.
+func definesMacros(p *parser) bool {
+	for _, t := range p.Tokens() {
+		if t.pegRule != ruleIdentifier {
+			continue
+		}
+		id := string(p.buffer[t.begin:t.end])
+		if i := strings.IndexAny(id, " \t\r\n;"); i >= 0 { // drop the spacing
+			id = id[:i]
+		}
+		if id == "defmacro" {
+			return true
+		}
+	}
+	return false
+}
+
+// bindMacro is the Binder of defmacro, the name of the macro is visible in all the arguments,
+// and its parameters are visible in the parameters and the template.
+func bindMacro(call Node) []Binding {
+	args := call.Args()
+	if len(args) < 2 || args[0].Type() != ExprTypeIdentifier {
+		return nil
+	}
+	ret := []Binding{{Name: args[0].Identifier(), Def: args[0]}}
+	params := args[1]
+	if params.Type() != ExprTypeExpr {
+		return ret
+	}
+	for _, p := range append([]Node{params.Operator()}, params.Args()...) {
+		switch id := p.Identifier(); id {
+		case "":
+		case "&rest":
+			ret = append(ret, Binding{Name: id, Args: []int{1}})
+		default:
+			ret = append(ret, Binding{Name: id, Def: p, Args: []int{1, 2}})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,121 @@
+package gendsl
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Macro", func() {
+	var testEnv *Env
+	BeforeEach(func() {
+		testEnv = NewEnv().WithBuiltins().
+			WithProcedure("RETURN", Procedure{Eval: CheckNArgs("1", _return)}).
+			WithProcedure("DEFINE", Procedure{Eval: CheckNArgs("3", _define)}).
+			WithProcedure("BLOCK", Procedure{Eval: CheckNArgs("*", _block)}).
+			WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)}).
+			WithProcedure("ARRAY", Procedure{Eval: CheckNArgs("*", _array)}).
+			WithProcedure("IF", Procedure{Eval: CheckNArgs("3", func(_ *EvalCtx, args []Expr, _ map[string]Value) (Value, error) {
+				cond, err := args[0].Eval()
+				if err != nil {
+					return nil, err
+				}
+				if cond == Bool(false) {
+					return args[2].Eval()
+				}
+				return args[1].Eval()
+			})})
+	})
+
+	It("can define and expand macros", func() {
+		script := "(defmacro UNLESS (cond then else) `(IF ,cond ,else ,then)\n" +
+			"  (UNLESS #f 1 2))"
+		v, err := EvalExpr(script, testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+
+		pc, err := MakeParseContext(script)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		code, err := pc.MacroExpand(NewEvalCtx(nil, nil, testEnv))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(code.Text()).Should(gomega.Equal("(defmacro UNLESS (cond then else) `(IF ,cond ,else ,then)\n" +
+			"  (IF #f 2 1))"))
+		v, err = code.Eval(NewEvalCtx(nil, nil, testEnv))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+	})
+
+	It("evaluates the arguments only where the expansion does", func() {
+		calls := 0
+		env := testEnv.Clone().WithProcedure("COUNT", Procedure{Eval: func(*EvalCtx, []Expr, map[string]Value) (Value, error) {
+			calls++
+			return Int(calls), nil
+		}})
+		v, err := EvalExpr("(defmacro TWICE (x) `(PLUS ,x ,x) (TWICE (COUNT)))", env)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(3)))
+		gomega.Expect(calls).Should(gomega.Equal(2))
+	})
+
+	It("supports the rest arguments", func() {
+		v, err := EvalExpr("(defmacro SUM (x &rest xs) `(PLUS ,x ,@xs) (SUM 1 2 3))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(6)))
+
+		v, err = EvalExpr("(defmacro ONE nil 1 (ONE))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+	})
+
+	It("expands the macros in the expansions and the arguments", func() {
+		script := `(defmacro INC (x) ` + "`(PLUS ,x 1)" + `
+  (defmacro INC2 (x) ` + "`(INC (INC ,x))" + `
+    (INC2 (INC 1))))`
+		v, err := EvalExpr(script, testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(4)))
+
+		pc, err := MakeParseContext(script)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		code, err := pc.MacroExpand(NewEvalCtx(nil, nil, testEnv))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(code.Node().Args()[3].Args()[3].Text()).Should(gomega.Equal("(INC2 (INC 1))"))
+		gomega.Expect(code.Text()).Should(gomega.ContainSubstring("(PLUS (PLUS (PLUS 1 1) 1) 1)"))
+	})
+
+	It("keeps the macros in the scope of defmacro", func() {
+		_, err := EvalExpr("(BLOCK (defmacro ONE nil 1 (ONE)) (ONE))", testEnv)
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).Should(gomega.ContainSubstring("unsupported operator ONE"))
+	})
+
+	It("reports the positions in the original source", func() {
+		_, err := EvalExpr("(defmacro TWICE (x) `(PLUS ,x ,x)\n  (TWICE\n    foo))", testEnv)
+		var ue *UnboundedIdentifierError
+		gomega.Expect(errors.As(err, &ue)).Should(gomega.BeTrue())
+		gomega.Expect(ue.ID).Should(gomega.Equal("foo"))
+		gomega.Expect(ue.BeginLine).Should(gomega.Equal(3))
+		gomega.Expect(ue.BeginSym).Should(gomega.Equal(5))
+	})
+
+	It("reports the invalid macros", func() {
+		for _, script := range []string{
+			"(defmacro 1 nil 1 1)",
+			"(defmacro M 1 1 1)",
+			"(defmacro M (x &rest) 1 1)",
+			"(defmacro M (x) `(M ,x) (M 1))",
+			"(defmacro M (x) 1 (M))",
+			"(defmacro M (x) 1 (M #:n 1 2))",
+			"(defmacro M (x) (PLUS x) (M 1))",
+		} {
+			_, err := EvalExpr(script, testEnv)
+			gomega.Expect(err).Should(gomega.HaveOccurred(), script)
+		}
+	})
+
+	It("allows the env to override defmacro", func() {
+		v, err := EvalExpr("(defmacro 1)", testEnv.Clone().WithProcedure("defmacro", Procedure{Eval: CheckNArgs("1", _return)}))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+	})
+})
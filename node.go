@@ -0,0 +1,326 @@
+package gendsl
+
+import (
+	"sort"
+)
+
+type (
+	// Position is a location in the script.
+	// Line and Symbol count from 1 just like the ones reported in errors,
+	// and Offset is the index of the rune in the script.
+	Position struct {
+		Offset       int
+		Line, Symbol int
+	}
+
+	// Range is the range of some text in the script, End is exclusive.
+	Range struct {
+		Begin, End Position
+	}
+
+	// Node is a node in the syntax tree of a script.
+	// It allows tools to inspect a script without evaluating it.
+	Node struct {
+		node *node32
+		pc   *ParseContext
+	}
+
+	// OptionNode is an option declared in an expression like `#:name value`.
+	OptionNode struct {
+		Name  string
+		Range Range // range of the whole option
+		Value Node
+	}
+
+	// Comment is a comment in the script.
+	Comment struct {
+		Text  string // text of the comment, including the leading ';', '#|' or '#;'
+		Range Range
+	}
+)
+
+// Root returns the node of the top level expression of the script.
+func (c *ParseContext) Root() Node {
+	n := c.root
+	for n != nil && (n.pegRule == ruleScript || n.pegRule == ruleValue) {
+		n = skipSpacing(n.up)
+	}
+	return Node{node: n, pc: c}
+}
+
+// Comments returns all the comments in the script in the order they appear,
+// including the line comments, the block comments and the datum comments.
+func (c *ParseContext) Comments() []Comment {
+	ret := make([]Comment, 0)
+	lineComments := func(begin, end uint32) {
+		text := c.p.buffer[begin:end]
+		for i := 0; i < len(text); i++ {
+			if text[i] != ';' {
+				continue
+			}
+			from := i
+			for ; i < len(text) && text[i] != '\r' && text[i] != '\n'; i++ {
+			}
+			ret = append(ret, Comment{
+				Text:  string(text[from:i]),
+				Range: c.nodeRange(begin+uint32(from), begin+uint32(i)),
+			})
+		}
+	}
+	var walk func(n *node32)
+	walk = func(n *node32) {
+		for ; n != nil; n = n.next {
+			if n.pegRule != ruleSpacing {
+				walk(n.up)
+				continue
+			}
+			begin := n.begin
+			for cur := n.up; cur != nil; cur = cur.next {
+				lineComments(begin, cur.begin)
+				end := cur.end
+				if cur.pegRule == ruleDatumComment {
+					end = contentEnd(cur) // the spacing after the datum is not a part of the comment
+				}
+				ret = append(ret, Comment{
+					Text:  string(c.p.buffer[cur.begin:end]),
+					Range: c.nodeRange(cur.begin, end),
+				})
+				if cur.pegRule == ruleDatumComment {
+					walk(trailingSpacing(cur))
+				}
+				begin = cur.end
+			}
+			lineComments(begin, n.end)
+		}
+	}
+	walk(c.root)
+	return ret
+}
+
+// trailingSpacing returns the spacing at the end of a datum comment.
+func trailingSpacing(n *node32) *node32 {
+	for n.up != nil {
+		last := n.up
+		for ; last.next != nil; last = last.next {
+		}
+		if last.end != n.end {
+			return nil
+		}
+		if last.pegRule == ruleSpacing {
+			return last
+		}
+		n = last
+	}
+	return nil
+}
+
+// position translates a rune offset into a Position, the same way as the positions in errors.
+func (c *ParseContext) position(offset uint32) Position {
+	o := int(offset)
+	line := sort.Search(len(c.lineStarts), func(i int) bool { return c.lineStarts[i] > o })
+	if o < len(c.p.buffer) && c.p.buffer[o] == '\n' {
+		return Position{Offset: o, Line: line + 1, Symbol: 0}
+	}
+	return Position{Offset: o, Line: line, Symbol: o - c.lineStarts[line-1] + 1}
+}
+
+func (c *ParseContext) nodeRange(begin, end uint32) Range {
+	return Range{Begin: c.position(begin), End: c.position(end)}
+}
+
+// IsZero reports whether n refers to no node.
+func (n Node) IsZero() bool {
+	return n.node == nil
+}
+
+// Type returns the type of the expression of the node.
+func (n Node) Type() ExprType {
+	return getExprType(n.node)
+}
+
+// Text returns the raw text of the node without the spaces and comments around.
+func (n Node) Text() string {
+	return n.pc.nodeText(n.node)
+}
+
+// Range returns the range of the node without the spaces and comments around.
+func (n Node) Range() Range {
+	return n.pc.nodeRange(contentBegin(n.node), contentEnd(n.node))
+}
+
+// Operator returns the operator of an expression like (X Y Z...) which is X.
+// It returns a zero Node if n is not an expression.
+func (n Node) Operator() Node {
+	if n.node.pegRule != ruleExpression {
+		return Node{}
+	}
+	for cur := n.node.up; cur != nil; cur = cur.next {
+		if cur.pegRule == ruleOperator {
+			return Node{node: cur.up, pc: n.pc}
+		}
+	}
+	return Node{}
+}
+
+// Args returns the arguments of an expression, or nil if n is not an expression.
+func (n Node) Args() []Node {
+	if n.node.pegRule != ruleExpression {
+		return nil
+	}
+	ret := make([]Node, 0)
+	for cur := n.node.up; cur != nil; cur = cur.next {
+		if cur.pegRule == ruleValue {
+			ret = append(ret, Node{node: cur.up, pc: n.pc})
+		}
+	}
+	return ret
+}
+
+// Options returns the options of an expression in the order they are declared,
+// or nil if n is not an expression.
+func (n Node) Options() []OptionNode {
+	if n.node.pegRule != ruleExpression {
+		return nil
+	}
+	ret := make([]OptionNode, 0)
+	for cur := n.node.up; cur != nil; cur = cur.next {
+		if cur.pegRule != ruleOption {
+			continue
+		}
+		ret = append(ret, OptionNode{
+			Name:  readIdentifierText(n.pc, cur.up),
+			Range: n.pc.nodeRange(contentBegin(cur), contentEnd(cur)),
+			Value: Node{node: cur.up.next, pc: n.pc},
+		})
+	}
+	return ret
+}
+
+// Identifier returns the identifier that an identifier node refers to, for `foo.bar.baz` it returns "foo".
+// It returns an empty string if n is not an identifier.
+func (n Node) Identifier() string {
+	switch n.node.pegRule {
+	case ruleIdentifier:
+		return readIdentifierText(n.pc, n.node)
+	case ruleIdentifierAttr:
+		return readIdentifierText(n.pc, n.node.up)
+	}
+	return ""
+}
+
+// Path returns the attributes selected by the identifier, for `foo.bar.baz` it returns ["bar", "baz"].
+func (n Node) Path() []string {
+	if n.node.pegRule != ruleIdentifierAttr {
+		return nil
+	}
+	ret := make([]string, 0)
+	for cur := n.node.up.next; cur != nil; cur = cur.next {
+		ret = append(ret, readIdentifierText(n.pc, cur.up))
+	}
+	return ret
+}
+
+// Literal returns the value of a literal node.
+// An error is returned if n is not a literal or the literal is invalid.
+func (n Node) Literal() (Value, error) {
+	if n.node.pegRule != ruleLiteral {
+		return nil, evalErrorf(n.pc, n.node, "%s is not a literal", n.Text())
+	}
+	v, err := n.pc.parseNode(n.node, nil)
+	if err != nil {
+		return nil, err
+	}
+	return v.(Value), nil
+}
+
+// Quoted returns the node quoted by 'x, `x, ,x or ,@x, which is x.
+// It returns a zero Node if n is not a quote.
+func (n Node) Quoted() Node {
+	if getExprType(n.node) != ExprTypeQuote {
+		return Node{}
+	}
+	return Node{node: quoted(n.node), pc: n.pc}
+}
+
+// Children returns the operator, options' values and arguments of an expression in the order they appear,
+// the node quoted if n is a quote, or the values embedded if n is an interpolated string.
+func (n Node) Children() []Node {
+	switch getExprType(n.node) {
+	case ExprTypeQuote:
+		return []Node{n.Quoted()}
+	case ExprTypeInterpolation:
+		ret := make([]Node, 0)
+		for _, v := range interpolationHoles(n.node) {
+			ret = append(ret, Node{node: v.up, pc: n.pc})
+		}
+		return ret
+	}
+	if n.node.pegRule != ruleExpression {
+		return nil
+	}
+	ret := make([]Node, 0)
+	for cur := n.node.up; cur != nil; cur = cur.next {
+		switch cur.pegRule {
+		case ruleOperator, ruleValue:
+			ret = append(ret, Node{node: cur.up, pc: n.pc})
+		case ruleOption:
+			ret = append(ret, Node{node: cur.up.next, pc: n.pc})
+		}
+	}
+	return ret
+}
+
+// Node returns the node of the expression.
+func (e Expr) Node() Node {
+	return Node{node: e.node, pc: e.pc}
+}
+
+// Inspect traverses the syntax tree in depth-first order, it calls f(n) for each node
+// and its children will not be visited if f returns false.
+func Inspect(n Node, f func(Node) bool) {
+	if n.IsZero() || !f(n) {
+		return
+	}
+	for _, child := range n.Children() {
+		Inspect(child, f)
+	}
+}
+
+func skipSpacing(n *node32) *node32 {
+	for ; n != nil && n.pegRule == ruleSpacing; n = n.next {
+	}
+	return n
+}
+
+// contentBegin returns the begin of a node without the leading spacing.
+func contentBegin(n *node32) uint32 {
+	first := n.up
+	if first == nil || first.begin != n.begin {
+		return n.begin
+	}
+	if first.pegRule != ruleSpacing {
+		return contentBegin(first)
+	}
+	if next := first.next; next != nil && next.begin == first.end {
+		return contentBegin(next)
+	}
+	return first.end
+}
+
+// contentEnd returns the end of a node without the trailing spacing.
+func contentEnd(n *node32) uint32 {
+	var last, prev *node32
+	for cur := n.up; cur != nil; cur = cur.next {
+		prev, last = last, cur
+	}
+	if last == nil || last.end != n.end {
+		return n.end
+	}
+	if last.pegRule != ruleSpacing {
+		return contentEnd(last)
+	}
+	if prev != nil && prev.end == last.begin {
+		return contentEnd(prev)
+	}
+	return last.begin
+}
@@ -0,0 +1,190 @@
+package gendsl
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxDecimalScale limits the digits after the point of a Decimal literal, so that a literal like 1e-999999999m cannot exhaust the memory.
+const maxDecimalScale = 1 << 16
+
+type (
+	// BigInt is an integer of arbitrary precision, it is written like 123n or 0xffn in the DSL.
+	// A BigInt never shares its *big.Int with the caller, so it is safe to copy it by value.
+	BigInt struct {
+		i *big.Int
+	}
+
+	// Decimal is an exact decimal number like 19.99m in the DSL, its value is unscaled * 10^-scale.
+	// The scale is kept as written, so 19.90m prints as 19.90m, use [gendsl.Decimal.Rat] to compare the values.
+	Decimal struct {
+		unscaled *big.Int
+		scale    int32
+	}
+)
+
+var (
+	_ Value = BigInt{}
+	_ Value = Decimal{}
+)
+
+// NewBigInt returns the BigInt of a copy of `i`, a nil `i` is 0.
+func NewBigInt(i *big.Int) BigInt {
+	if i == nil {
+		return BigInt{i: new(big.Int)}
+	}
+	return BigInt{i: new(big.Int).Set(i)}
+}
+
+func (BigInt) _value()         {}
+func (BigInt) Type() ValueType { return ValueTypeBigInt }
+func (b BigInt) Unwrap() any   { return b.Big() }
+
+// Big returns a copy of the integer.
+func (b BigInt) Big() *big.Int {
+	return new(big.Int).Set(b.int())
+}
+
+func (b BigInt) String() string { return b.int().String() }
+
+func (b BigInt) int() *big.Int {
+	if b.i == nil {
+		return new(big.Int)
+	}
+	return b.i
+}
+
+// NewDecimal returns the Decimal of unscaled * 10^-scale, a nil `unscaled` is 0.
+func NewDecimal(unscaled *big.Int, scale int32) Decimal {
+	u := new(big.Int)
+	if unscaled != nil {
+		u.Set(unscaled)
+	}
+	if scale < 0 {
+		u.Mul(u, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: u, scale: scale}
+}
+
+// ParseDecimal parses a decimal number like "19.99", "-0.5" or "1.5e3", the digits can be separated by '_'.
+func ParseDecimal(s string) (Decimal, error) {
+	text := strings.ReplaceAll(s, "_", "")
+	mantissa, exp := text, int64(0)
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		e, ok := new(big.Int).SetString(strings.TrimPrefix(text[i+1:], "+"), 10)
+		if !ok || !e.IsInt64() || e.Int64() > maxDecimalScale || e.Int64() < -maxDecimalScale {
+			return Decimal{}, errors.Errorf("invalid decimal %q", s)
+		}
+		mantissa, exp = text[:i], e.Int64()
+	}
+	intPart, frac, _ := strings.Cut(mantissa, ".")
+	digits := intPart + frac
+	if digits == "" || digits == "+" || digits == "-" {
+		return Decimal{}, errors.Errorf("invalid decimal %q", s)
+	}
+	u, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, errors.Errorf("invalid decimal %q", s)
+	}
+	scale := int64(len(frac)) - exp
+	if scale > maxDecimalScale {
+		return Decimal{}, errors.Errorf("invalid decimal %q: too many digits after the point", s)
+	}
+	return NewDecimal(u, int32(scale)), nil
+}
+
+// DecimalFromRat returns `r` rounded half to even to `scale` digits after the point.
+func DecimalFromRat(r *big.Rat, scale int32) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	n := new(big.Int).Mul(r.Num(), pow10(scale))
+	q, m := new(big.Int).QuoRem(n, r.Denom(), new(big.Int))
+	// compare the remainder with a half of the denominator
+	switch new(big.Int).Abs(m.Lsh(m, 1)).Cmp(r.Denom()) {
+	case 1:
+		q.Add(q, big.NewInt(int64(n.Sign())))
+	case 0:
+		if q.Bit(0) == 1 {
+			q.Add(q, big.NewInt(int64(n.Sign())))
+		}
+	}
+	return Decimal{unscaled: q, scale: scale}
+}
+
+func (Decimal) _value()         {}
+func (Decimal) Type() ValueType { return ValueTypeDecimal }
+func (d Decimal) Unwrap() any   { return d.Rat() }
+
+// Rat returns the value of the Decimal.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.int(), pow10(d.scale))
+}
+
+// Unscaled returns a copy of the unscaled integer and the scale, the value is unscaled * 10^-scale.
+func (d Decimal) Unscaled() (*big.Int, int32) {
+	return new(big.Int).Set(d.int()), d.scale
+}
+
+// String returns the Decimal in the form of [-]digits[.digits] without the suffix m.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.int().Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale returns the unscaled integer of the Decimal at a larger `scale`.
+func (d Decimal) rescale(scale int32) *big.Int {
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func parseBigIntLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	text := c.nodeText(node)
+	s := strings.ReplaceAll(text[:len(text)-1], "_", "")
+	sign := ""
+	if s[0] == '+' || s[0] == '-' {
+		sign, s = s[:1], s[1:]
+	}
+	base := 10
+	if len(s) > 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		base, s = 16, s[2:]
+	}
+	i, ok := new(big.Int).SetString(sign+s, base)
+	if !ok {
+		return nil, evalErrorf(c, node, "invalid bigint literal %q", text)
+	}
+	return BigInt{i: i}, nil
+}
+
+func parseDecimalLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	text := c.nodeText(node)
+	d, err := ParseDecimal(text[:len(text)-1])
+	if err != nil {
+		return nil, evalErrorf(c, node, "invalid decimal literal %q: %s", text, err)
+	}
+	return d, nil
+}
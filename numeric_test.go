@@ -0,0 +1,140 @@
+package gendsl
+
+import (
+	"math"
+	"math/big"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+func bigInt(s string) BigInt {
+	i, ok := new(big.Int).SetString(s, 10)
+	gomega.Expect(ok).Should(gomega.BeTrue())
+	return NewBigInt(i)
+}
+
+func decimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	return d
+}
+
+var _ = Describe("BigInt and Decimal", func() {
+	It("can parse the literals", func() {
+		for expr, want := range map[string]Value{
+			"123n":                    bigInt("123"),
+			"-123n":                   bigInt("-123"),
+			"0xffn":                   bigInt("255"),
+			"1_000n":                  bigInt("1000"),
+			"007n":                    bigInt("7"),
+			"99999999999999999999n":   bigInt("99999999999999999999"),
+			"19.99m":                  decimal("19.99"),
+			"-0.5m":                   decimal("-0.5"),
+			".5m":                     decimal("0.5"),
+			"1.5e3m":                  decimal("1500"),
+			"1e3m":                    decimal("1000"),
+			"-2E-2m":                  decimal("-0.02"),
+			"(PLUS 1 2)":              Int(3),
+			"(RETURN #:n 1n 19.990m)": decimal("19.990"),
+		} {
+			pc, err := MakeParseContext(expr)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			v, err := pc.Eval(NewEvalCtx(nil, nil, NewEnv().
+				WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)}).
+				WithProcedure("RETURN", Procedure{Eval: CheckNArgs("1", _return)})))
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v).Should(gomega.Equal(want), expr)
+		}
+	})
+
+	It("suggests a bigint for the integers out of range", func() {
+		_, err := EvalExpr("99999999999999999999", NewEnv())
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).Should(gomega.ContainSubstring("99999999999999999999n"))
+	})
+
+	It("converts from and to math/big", func() {
+		i := big.NewInt(42)
+		b := NewBigInt(i)
+		i.SetInt64(0)
+		gomega.Expect(b.Unwrap()).Should(gomega.Equal(big.NewInt(42)))
+		gomega.Expect(BigInt{}.String()).Should(gomega.Equal("0"))
+
+		d := NewDecimal(big.NewInt(1999), 2)
+		gomega.Expect(d.String()).Should(gomega.Equal("19.99"))
+		gomega.Expect(d.Rat()).Should(gomega.Equal(big.NewRat(1999, 100)))
+		gomega.Expect(d.Unwrap()).Should(gomega.Equal(big.NewRat(1999, 100)))
+		u, scale := d.Unscaled()
+		gomega.Expect(u).Should(gomega.Equal(big.NewInt(1999)))
+		gomega.Expect(scale).Should(gomega.Equal(int32(2)))
+		gomega.Expect(NewDecimal(big.NewInt(5), -2).String()).Should(gomega.Equal("500"))
+		gomega.Expect(NewDecimal(big.NewInt(-5), 3).String()).Should(gomega.Equal("-0.005"))
+
+		gomega.Expect(DecimalFromRat(big.NewRat(1, 3), 4).String()).Should(gomega.Equal("0.3333"))
+		gomega.Expect(DecimalFromRat(big.NewRat(5, 2), 0).String()).Should(gomega.Equal("2"))
+		gomega.Expect(DecimalFromRat(big.NewRat(-7, 2), 0).String()).Should(gomega.Equal("-4"))
+		gomega.Expect(DecimalFromRat(big.NewRat(2, 3), 2).String()).Should(gomega.Equal("0.67"))
+
+		for _, s := range []string{"", ".", "-", "1.2.3", "1e", "abc", "1e99999999999"} {
+			_, err := ParseDecimal(s)
+			gomega.Expect(err).Should(gomega.HaveOccurred(), s)
+		}
+	})
+
+	It("prints the literals", func() {
+		for _, v := range []Value{bigInt("-99999999999999999999"), decimal("19.990"), decimal("-0.05"), NewDecimal(big.NewInt(3), 0)} {
+			text := Repr(v)
+			got, err := EvalExpr(text, NewEnv())
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), text)
+			gomega.Expect(got.Unwrap()).Should(gomega.Equal(v.Unwrap()), text)
+		}
+		gomega.Expect(Repr(decimal("19.990"))).Should(gomega.Equal("19.990m"))
+		gomega.Expect(Repr(NewDecimal(big.NewInt(3), 0))).Should(gomega.Equal("3.m"))
+	})
+
+	It("promotes the numbers to keep the arithmetic exact", func() {
+		for _, c := range []struct {
+			fn   func(a, b Value) (Value, error)
+			a, b Value
+			want Value
+		}{
+			{Add, Int(1), Int(2), Int(3)},
+			{Add, Uint(1), Uint(2), Uint(3)},
+			{Sub, Uint(1), Uint(2), Int(-1)},
+			{Add, Int(1), Uint(2), Int(3)},
+			{Add, Int(math.MaxInt64), Int(1), bigInt("9223372036854775808")},
+			{Mul, Uint(math.MaxUint64), Uint(2), bigInt("36893488147419103230")},
+			{Add, bigInt("1"), Int(1), bigInt("2")},
+			{Div, Int(7), Int(-2), Int(-3)},
+			{Add, decimal("0.1"), decimal("0.2"), decimal("0.3")},
+			{Sub, decimal("19.99"), Int(20), decimal("-0.01")},
+			{Mul, decimal("19.99"), Int(3), decimal("59.97")},
+			{Mul, decimal("1.5"), decimal("1.5"), decimal("2.25")},
+			{Add, decimal("0.5"), bigInt("99999999999999999999"), decimal("99999999999999999999.5")},
+			{Div, decimal("10.00"), Int(4), decimal("2.50")},
+			{Div, Int(1), decimal("8"), decimal("0.125")},
+			{Div, decimal("1"), Int(3), decimal("0.3333333333333333333333333333333333")},
+			{Add, Float(0.5), Int(1), Float(1.5)},
+			{Div, Float(1), Float(4), Float(0.25)},
+		} {
+			v, err := c.fn(c.a, c.b)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), Repr(c.a)+" "+Repr(c.b))
+			gomega.Expect(v).Should(gomega.Equal(c.want), Repr(c.a)+" "+Repr(c.b))
+		}
+
+		for _, c := range [][2]Value{
+			{Float(1), decimal("1")},
+			{bigInt("1"), Float(1)},
+			{Int(1), String("1")},
+			{nil, Int(1)},
+		} {
+			_, err := Add(c[0], c[1])
+			gomega.Expect(err).Should(gomega.HaveOccurred())
+		}
+		_, err := Div(Int(1), Int(0))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+		_, err = Div(decimal("1"), decimal("0.0"))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+})
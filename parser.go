@@ -2,6 +2,7 @@
 package gendsl
 
 import (
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -12,7 +13,12 @@ type (
 	// ParseContext holds the stateless parser context for a compiled script.
 	// It can be reused and re-evaluated with different [gendsl.EvalCtx].
 	ParseContext struct {
-		p *parser
+		p          *parser
+		root       *node32
+		lineStarts []int                     // offsets where each line begins
+		holes      map[*node32][]Value       // values filled into the unquotes by a quasiquote, or the expansions of the macro calls
+		macros     bool                      // whether the script may define macros
+		regexps    map[uint32]*regexp.Regexp // regexp literals compiled, keyed by their offsets
 	}
 
 	// 	OptionList map[string]any
@@ -35,6 +41,18 @@ func init() {
 		ruleStringLiteral:     parseStringLiteral,
 		ruleLongStringLiteral: parseLongStringLiteral,
 		ruleNilLiteral:        parseNilLiteral,
+		ruleKeywordLiteral:    parseKeywordLiteral,
+		ruleBigIntLiteral:     parseBigIntLiteral,
+		ruleDecimalLiteral:    parseDecimalLiteral,
+		ruleTimeLiteral:       parseTimeLiteral,
+		ruleDurationLiteral:   parseDurationLiteral,
+		ruleBytesLiteral:      parseBytesLiteral,
+		ruleRegexpLiteral:     parseRegexpLiteral,
+		ruleQuote:             parseQuote,
+		ruleQuasiquote:        parseQuasiquote,
+		ruleUnquote:           parseUnquote,
+		ruleUnquoteSplicing:   parseUnquoteSplicing,
+		ruleInterpolation:     parseInterpolation,
 	}
 }
 
@@ -73,25 +91,51 @@ func MakeParseContext(expr string) (*ParseContext, error) {
 		return nil, err
 	}
 	if err := parser.Parse(); err != nil {
-		pe := new(parseError)
-		if errors.Is(err, pe) {
-			return nil, &SyntaxError{pe: pe}
+		var pe *parseError
+		if errors.As(err, &pe) {
+			return nil, newSyntaxError(pe)
 		}
 		return nil, err
 	}
+	return newParseContext(parser)
+}
+
+func newParseContext(p *parser) (*ParseContext, error) {
+	lineStarts := []int{0}
+	for i, r := range p.buffer {
+		if r == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	regexps, err := compileRegexps(p)
+	if err != nil {
+		return nil, err
+	}
 	return &ParseContext{
-		p: parser,
+		p:          p,
+		root:       p.AST(),
+		lineStarts: lineStarts,
+		macros:     definesMacros(p),
+		regexps:    regexps,
 	}, nil
 }
 
 // Eval evaluates the compiled script with an evalCtx.
+// The macros defined in the script are expanded before evaluation, see [gendsl.ParseContext.MacroExpand].
 // It will panic if evalCtx is nil.
 func (c *ParseContext) Eval(evalCtx *EvalCtx) (Value, error) {
 	if evalCtx == nil {
 		panic("evalCtx cannot be nil")
 	}
-	ast := c.p.AST()
-	v, err := c.parseNode(ast, evalCtx)
+	pc := c
+	if c.macros {
+		code, err := c.MacroExpand(evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		pc = code.pc
+	}
+	v, err := pc.parseNode(c.root, evalCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +149,9 @@ func (c *ParseContext) Eval(evalCtx *EvalCtx) (Value, error) {
 	return ret, nil
 }
 
+// nodeText returns the text of a node without the spaces and comments around.
 func (c *ParseContext) nodeText(n *node32) string {
-	return strings.TrimSpace(string(c.p.buffer[n.begin:n.end]))
+	return string(c.p.buffer[contentBegin(n):contentEnd(n)])
 }
 
 // PrintTree output the syntax tree to the stdio
@@ -122,6 +167,9 @@ func (c *ParseContext) parseNode(node *node32, evalCtx *EvalCtx) (any, error) {
 	if parser == nil {
 		panic("parser for rule " + node.pegRule.String() + " not found")
 	}
+	if evalCtx != nil && evalCtx.tracer != nil && tracedRule(node.pegRule) {
+		return c.traceNode(node, evalCtx, func() (any, error) { return parser(c, evalCtx, node) })
+	}
 	return parser(c, evalCtx, node)
 }
 
@@ -240,9 +288,46 @@ func parseOperator(c *ParseContext, e *EvalCtx, node *node32) (any, error) {
 
 func parseLongStringLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
 	text := c.nodeText(node)
+	if strings.HasPrefix(text, "#h") {
+		return String(dedent(text[5 : len(text)-3])), nil
+	}
 	return String(text[3 : len(text)-3]), nil
 }
 
+// dedent removes a leading newline and the common indentation of the non-blank lines in a heredoc,
+// a line with only spaces and tabs, like the one before the closing """, becomes empty.
+func dedent(s string) string {
+	if strings.HasPrefix(s, "\r\n") {
+		s = s[2:]
+	} else if strings.HasPrefix(s, "\n") {
+		s = s[1:]
+	}
+	lines := strings.Split(s, "\n")
+	indent := ""
+	found := false
+	for _, line := range lines {
+		if strings.TrimLeft(line, " \t\r") == "" {
+			continue
+		}
+		prefix := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !found {
+			indent, found = prefix, true
+			continue
+		}
+		for !strings.HasPrefix(prefix, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+	for i, line := range lines {
+		if strings.TrimLeft(line, " \t\r") == "" {
+			lines[i] = strings.TrimLeft(line, " \t")
+			continue
+		}
+		lines[i] = line[len(indent):]
+	}
+	return strings.Join(lines, "\n")
+}
+
 func parseStringLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
 	text := c.nodeText(node)
 	unquoted, err := strconv.Unquote(text)
@@ -293,6 +378,9 @@ func parseIntegerLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error)
 		}
 	}
 
+	if errors.Is(err, strconv.ErrRange) {
+		return nil, evalErrorf(c, node, "invalid literal %q: out of range, use the suffix n for a bigint like %sn", text, strings.TrimRight(text, "uU"))
+	}
 	if err != nil {
 		return nil, evalErrorf(c, node, "invalid literal %q: %s", text, err)
 	}
@@ -0,0 +1,197 @@
+// Package profiler measures how much time the procedures take in the evaluation of the scripts.
+//
+// A [profiler.Profiler] aggregates the calls, the cumulative and the self time per procedure and per call site,
+// and exports a profile that the `go tool pprof` can read, with the DSL call stacks as the sample stacks:
+//
+//	p := profiler.New("rule.dsl")
+//	v, err := p.Eval(pc, gendsl.NewEvalCtx(nil, nil, env))
+//	for _, stat := range p.Procedures() {
+//		fmt.Println(stat.Name, stat.Calls, stat.Cum, stat.Self)
+//	}
+//	err = p.WriteProfile(f) // go tool pprof -top rule.prof
+package profiler
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	// Stat is the statistics of a procedure or a call site.
+	Stat struct {
+		Name  string          // name of the procedure
+		Site  gendsl.Position // where the expression begins, zero for a procedure
+		Calls int
+		Cum   time.Duration // time spent in the calls, including the calls inside them
+		Self  time.Duration // time spent in the calls, excluding the calls inside them
+	}
+
+	// Profiler is a [gendsl.Tracer] that profiles the procedure calls,
+	// the statistics are accumulated across evaluations.
+	// It is not safe to evaluate scripts concurrently with the same Profiler.
+	Profiler struct {
+		filename   string
+		start      time.Time
+		frames     []frame
+		procs      map[string]*Stat
+		sites      map[site]*Stat
+		samples    map[string]*sample // samples by their stacks
+		sampleKeys []string           // keys of the samples in order
+	}
+
+	site struct {
+		name string
+		pos  gendsl.Position
+	}
+
+	frame struct {
+		site  site
+		child time.Duration // time spent in the calls inside
+	}
+
+	sample struct {
+		stack []site // the innermost call comes first
+		calls int64
+		self  time.Duration
+	}
+)
+
+// New creates a Profiler, `filename` is the name of the script shown in the profiles.
+func New(filename string) *Profiler {
+	return &Profiler{
+		filename: filename,
+		start:    time.Now(),
+		procs:    make(map[string]*Stat),
+		sites:    make(map[site]*Stat),
+		samples:  make(map[string]*sample),
+	}
+}
+
+// Eval evaluates the script of `pc` with `evalCtx` and profiles it.
+// The tracer of `evalCtx` is replaced during the evaluation.
+func (p *Profiler) Eval(pc *gendsl.ParseContext, evalCtx *gendsl.EvalCtx) (gendsl.Value, error) {
+	prev := evalCtx.Tracer()
+	defer evalCtx.WithTracer(prev)
+	return pc.Eval(evalCtx.WithTracer(p))
+}
+
+func (p *Profiler) EnterNode(ev gendsl.TraceEvent) {
+	if ev.Kind != gendsl.ExprTypeExpr {
+		return
+	}
+	p.frames = append(p.frames, frame{site: site{name: ev.Name, pos: ev.Range.Begin}})
+}
+
+func (p *Profiler) ExitNode(ev gendsl.TraceEvent) {
+	if ev.Kind != gendsl.ExprTypeExpr {
+		return
+	}
+	top := len(p.frames) - 1
+	f := p.frames[top]
+	self := ev.Duration - f.child
+	if self < 0 {
+		self = 0
+	}
+	if top > 0 {
+		p.frames[top-1].child += ev.Duration
+	}
+
+	// the cumulative time of a recursive call is counted by the outermost one
+	recursiveProc, recursiveSite := false, false
+	for _, outer := range p.frames[:top] {
+		recursiveProc = recursiveProc || outer.site.name == f.site.name
+		recursiveSite = recursiveSite || outer.site == f.site
+	}
+	proc := p.procs[f.site.name]
+	if proc == nil {
+		proc = &Stat{Name: f.site.name}
+		p.procs[f.site.name] = proc
+	}
+	accumulate(proc, ev.Duration, self, recursiveProc)
+	st := p.sites[f.site]
+	if st == nil {
+		st = &Stat{Name: f.site.name, Site: f.site.pos}
+		p.sites[f.site] = st
+	}
+	accumulate(st, ev.Duration, self, recursiveSite)
+
+	p.addSample(self)
+	p.frames = p.frames[:top]
+}
+
+func accumulate(st *Stat, cum, self time.Duration, recursive bool) {
+	st.Calls++
+	st.Self += self
+	if !recursive {
+		st.Cum += cum
+	}
+}
+
+// addSample adds the self time of the call on the top of the stack.
+func (p *Profiler) addSample(self time.Duration) {
+	keys := make([]string, 0, len(p.frames))
+	for i := len(p.frames) - 1; i >= 0; i-- {
+		s := p.frames[i].site
+		keys = append(keys, s.name+"@"+strconv.Itoa(s.pos.Offset))
+	}
+	key := strings.Join(keys, ";")
+	smp := p.samples[key]
+	if smp == nil {
+		smp = &sample{stack: make([]site, 0, len(p.frames))}
+		for i := len(p.frames) - 1; i >= 0; i-- {
+			smp.stack = append(smp.stack, p.frames[i].site)
+		}
+		p.samples[key] = smp
+		p.sampleKeys = append(p.sampleKeys, key)
+	}
+	smp.calls++
+	smp.self += self
+}
+
+// Procedures returns the statistics per procedure, sorted by the cumulative time in descending order.
+func (p *Profiler) Procedures() []Stat {
+	ret := make([]Stat, 0, len(p.procs))
+	for _, st := range p.procs {
+		ret = append(ret, *st)
+	}
+	sortStats(ret)
+	return ret
+}
+
+// CallSites returns the statistics per call site, sorted by the cumulative time in descending order.
+func (p *Profiler) CallSites() []Stat {
+	ret := make([]Stat, 0, len(p.sites))
+	for _, st := range p.sites {
+		ret = append(ret, *st)
+	}
+	sortStats(ret)
+	return ret
+}
+
+func sortStats(stats []Stat) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Cum != stats[j].Cum {
+			return stats[i].Cum > stats[j].Cum
+		}
+		if stats[i].Name != stats[j].Name {
+			return stats[i].Name < stats[j].Name
+		}
+		return stats[i].Site.Offset < stats[j].Site.Offset
+	})
+}
+
+// Reset drops the statistics collected.
+func (p *Profiler) Reset() {
+	*p = *New(p.filename)
+}
+
+// WriteProfile writes a gzipped profile.proto with the samples of "calls/count" and "time/nanoseconds",
+// a call site is a location whose line is where the expression begins.
+func (p *Profiler) WriteProfile(w io.Writer) error {
+	return writeProfile(w, p)
+}
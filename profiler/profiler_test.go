@@ -0,0 +1,108 @@
+package profiler
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/google/pprof/profile"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+)
+
+func block(_ *gendsl.EvalCtx, args []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+	var ret gendsl.Value = gendsl.Nil{}
+	for _, arg := range args {
+		v, err := arg.Eval()
+		if err != nil {
+			return nil, err
+		}
+		ret = v
+	}
+	return ret, nil
+}
+
+func slow(_ *gendsl.EvalCtx, _ []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+	time.Sleep(5 * time.Millisecond)
+	return gendsl.Int(1), nil
+}
+
+var _ = Describe("Profiler", func() {
+	var (
+		pc  *gendsl.ParseContext
+		env *gendsl.Env
+		p   *Profiler
+	)
+
+	BeforeEach(func() {
+		var err error
+		pc, err = gendsl.MakeParseContext(`(BLOCK
+  (SLOW)
+  (BLOCK (FAST) (FAST)))`)
+		Expect(err).ShouldNot(HaveOccurred())
+		env = gendsl.NewEnv().
+			WithProcedure("BLOCK", gendsl.Procedure{Eval: block}).
+			WithProcedure("SLOW", gendsl.Procedure{Eval: slow}).
+			WithProcedure("FAST", gendsl.Procedure{Eval: block})
+		p = New("main.dsl")
+	})
+
+	It("can aggregate the calls per procedure and call site", func() {
+		Expect(p.Eval(pc, gendsl.NewEvalCtx(nil, nil, env))).Should(Equal(gendsl.Nil{}))
+		Expect(p.Eval(pc, gendsl.NewEvalCtx(nil, nil, env))).Should(Equal(gendsl.Nil{}))
+
+		procs := p.Procedures()
+		Expect(procs).Should(HaveLen(3))
+		Expect(procs[0].Name).Should(Equal("BLOCK"))
+		Expect(procs[0].Calls).Should(Equal(4))
+		Expect(procs[0].Cum).Should(BeNumerically(">=", 10*time.Millisecond))
+		Expect(procs[0].Self).Should(BeNumerically("<", procs[0].Cum))
+		Expect(procs[1].Name).Should(Equal("SLOW"))
+		Expect(procs[1].Self).Should(Equal(procs[1].Cum))
+		Expect(procs[2].Name).Should(Equal("FAST"))
+		Expect(procs[2].Calls).Should(Equal(4))
+
+		sites := p.CallSites()
+		Expect(sites).Should(HaveLen(5))
+		Expect(sites[0].Site).Should(Equal(gendsl.Position{Offset: 0, Line: 1, Symbol: 1}))
+		Expect(sites[0].Calls).Should(Equal(2))
+		Expect(sites[0].Cum).Should(Equal(procs[0].Cum))
+
+		p.Reset()
+		Expect(p.Procedures()).Should(BeEmpty())
+	})
+
+	It("can write a profile for pprof", func() {
+		_, err := p.Eval(pc, gendsl.NewEvalCtx(nil, nil, env))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		buf := &bytes.Buffer{}
+		Expect(p.WriteProfile(buf)).Should(Succeed())
+		prof, err := profile.Parse(buf)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(prof.CheckValid()).Should(Succeed())
+
+		Expect(prof.SampleType).Should(HaveLen(2))
+		Expect(prof.SampleType[1].Type).Should(Equal("time"))
+		Expect(prof.SampleType[1].Unit).Should(Equal("nanoseconds"))
+		Expect(prof.Function).Should(HaveLen(3))
+		Expect(prof.Function[0].Filename).Should(Equal("main.dsl"))
+		Expect(prof.Sample).Should(HaveLen(5)) // FAST is called at two sites
+
+		stacks := make(map[string]int64)
+		for _, s := range prof.Sample {
+			key := ""
+			for _, loc := range s.Location {
+				key += loc.Line[0].Function.Name + ";"
+			}
+			stacks[key] += s.Value[0]
+		}
+		Expect(stacks).Should(Equal(map[string]int64{
+			"BLOCK;":            1,
+			"SLOW;BLOCK;":       1,
+			"BLOCK;BLOCK;":      1,
+			"FAST;BLOCK;BLOCK;": 2,
+		}))
+	})
+})
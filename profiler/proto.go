@@ -0,0 +1,183 @@
+package profiler
+
+import (
+	"compress/gzip"
+	"io"
+	"time"
+)
+
+// Fields of the messages in profile.proto,
+// see https://github.com/google/pprof/blob/main/proto/profile.proto
+const (
+	profileSampleType    = 1
+	profileSample        = 2
+	profileLocation      = 4
+	profileFunction      = 5
+	profileStringTable   = 6
+	profileTimeNanos     = 9
+	profileDurationNanos = 10
+	profilePeriodType    = 11
+	profilePeriod        = 12
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+	functionFilename   = 4
+)
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// buffer encodes protobuf messages.
+type buffer []byte
+
+func (b *buffer) varint(v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v)|0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+func (b *buffer) key(field, wire int) {
+	b.varint(uint64(field<<3 | wire))
+}
+
+// uint64 encodes a varint field, zero is omitted.
+func (b *buffer) uint64(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	b.key(field, wireVarint)
+	b.varint(v)
+}
+
+func (b *buffer) int64(field int, v int64) {
+	b.uint64(field, uint64(v))
+}
+
+func (b *buffer) bytes(field int, bs []byte) {
+	b.key(field, wireBytes)
+	b.varint(uint64(len(bs)))
+	*b = append(*b, bs...)
+}
+
+func (b *buffer) message(field int, msg buffer) {
+	b.bytes(field, msg)
+}
+
+// packed encodes a packed repeated varint field.
+func (b *buffer) packed(field int, vs []uint64) {
+	var msg buffer
+	for _, v := range vs {
+		msg.varint(v)
+	}
+	b.bytes(field, msg)
+}
+
+// stringTable is the string table of a profile.
+type stringTable struct {
+	table []string
+	index map[string]int64
+}
+
+func (s *stringTable) id(str string) int64 {
+	if s.index == nil {
+		s.table, s.index = []string{""}, map[string]int64{"": 0}
+	}
+	if id, ok := s.index[str]; ok {
+		return id
+	}
+	id := int64(len(s.table))
+	s.table = append(s.table, str)
+	s.index[str] = id
+	return id
+}
+
+func writeProfile(w io.Writer, p *Profiler) error {
+	var (
+		out       buffer
+		strs      stringTable
+		functions = make(map[string]uint64)
+		locations = make(map[site]uint64)
+	)
+
+	valueType := func(typ, unit string) buffer {
+		var vt buffer
+		vt.int64(valueTypeType, strs.id(typ))
+		vt.int64(valueTypeUnit, strs.id(unit))
+		return vt
+	}
+	out.message(profileSampleType, valueType("calls", "count"))
+	out.message(profileSampleType, valueType("time", "nanoseconds"))
+
+	functionOf := func(name string) uint64 {
+		if id, ok := functions[name]; ok {
+			return id
+		}
+		id := uint64(len(functions) + 1)
+		functions[name] = id
+		var fn buffer
+		fn.uint64(functionID, id)
+		fn.int64(functionName, strs.id(name))
+		fn.int64(functionSystemName, strs.id(name))
+		fn.int64(functionFilename, strs.id(p.filename))
+		out.message(profileFunction, fn)
+		return id
+	}
+	locationOf := func(s site) uint64 {
+		if id, ok := locations[s]; ok {
+			return id
+		}
+		id := uint64(len(locations) + 1)
+		locations[s] = id
+		var line buffer
+		line.uint64(lineFunctionID, functionOf(s.name))
+		line.int64(lineLine, int64(s.pos.Line))
+		var loc buffer
+		loc.uint64(locationID, id)
+		loc.message(locationLine, line)
+		out.message(profileLocation, loc)
+		return id
+	}
+
+	for _, key := range p.sampleKeys {
+		smp := p.samples[key]
+		ids := make([]uint64, 0, len(smp.stack))
+		for _, s := range smp.stack {
+			ids = append(ids, locationOf(s))
+		}
+		var msg buffer
+		msg.packed(sampleLocationID, ids)
+		msg.packed(sampleValue, []uint64{uint64(smp.calls), uint64(smp.self)})
+		out.message(profileSample, msg)
+	}
+
+	out.int64(profileTimeNanos, p.start.UnixNano())
+	out.int64(profileDurationNanos, int64(time.Since(p.start)))
+	out.message(profilePeriodType, valueType("calls", "count"))
+	out.int64(profilePeriod, 1)
+	for _, str := range strs.table { // all the strings are interned now
+		out.bytes(profileStringTable, []byte(str))
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(out); err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,235 @@
+package gendsl
+
+import (
+	"sort"
+	"strings"
+)
+
+// Code is a piece of the script quoted as data by `'x`, `(quote x)` or a quasiquote.
+// It is not evaluated until [gendsl.Code.Eval] or the `eval` procedure is called,
+// so that procedures can take, compose and return code.
+//
+// In a quasiquote like (quasiquote (PLUS ,x 1)), `,x` is evaluated when the quasiquote is evaluated and its value is filled into the code,
+// and `,@x` splices the values of x into the arguments of the expression.
+// A Code filled is inserted as code, so it is evaluated together with the code around it,
+// a [gendsl.Symbol] is inserted as an identifier, and other values are inserted as they are.
+//
+// Quoting an identifier like 'x returns a [gendsl.Symbol] instead of a Code.
+type Code struct {
+	node *node32
+	pc   *ParseContext
+}
+
+var _ Value = Code{}
+
+func (Code) _value()         {}
+func (Code) Type() ValueType { return ValueTypeCode }
+func (c Code) Unwrap() any   { return c.Node() }
+
+// Node returns the syntax tree of the code, the unquotes in it are not replaced with the values filled.
+func (c Code) Node() Node {
+	return Node{node: c.node, pc: c.pc}
+}
+
+// Text returns the text of the code, with the unquotes replaced by the values filled.
+func (c Code) Text() string {
+	var (
+		sb    strings.Builder
+		begin = contentBegin(c.node)
+		end   = contentEnd(c.node)
+		holes = make([]*node32, 0, len(c.pc.holes))
+	)
+	for n := range c.pc.holes {
+		if contentBegin(n) >= begin && contentEnd(n) <= end {
+			holes = append(holes, n)
+		}
+	}
+	sort.Slice(holes, func(i, j int) bool { return holes[i].begin < holes[j].begin })
+
+	for _, n := range holes {
+		if contentBegin(n) < begin { // inside a hole printed already
+			continue
+		}
+		sb.WriteString(string(c.pc.p.buffer[begin:contentBegin(n)]))
+		for i, v := range c.pc.holes[n] {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			switch v := v.(type) {
+			case Code:
+				sb.WriteString(v.Text())
+			case Symbol:
+				sb.WriteString(v.Name())
+			default:
+				sb.WriteString(Repr(v))
+			}
+		}
+		begin = contentEnd(n)
+	}
+	sb.WriteString(string(c.pc.p.buffer[begin:end]))
+	return sb.String()
+}
+
+// Eval evaluates the code with `evalCtx`.
+func (c Code) Eval(evalCtx *EvalCtx) (Value, error) {
+	v, err := c.pc.parseNode(c.node, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := v.(Value)
+	if !ok {
+		return nil, evalErrorf(c.pc, c.node, "code should return a Value, but got %v", v)
+	}
+	return ret, nil
+}
+
+// quoted returns the node quoted by a quote node.
+func quoted(node *node32) *node32 {
+	return node.up.up // skip the Value
+}
+
+func parseQuote(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	return quote(c, quoted(node)), nil
+}
+
+// quote returns a Symbol if `node` is an identifier, or the Code of `node` otherwise.
+func quote(c *ParseContext, node *node32) Value {
+	if node.pegRule == ruleIdentifier {
+		return NewSymbol(readIdentifierText(c, node))
+	}
+	return Code{node: node, pc: c}
+}
+
+func parseQuasiquote(c *ParseContext, evalCtx *EvalCtx, node *node32) (any, error) {
+	return quasiquote(c, evalCtx, quoted(node))
+}
+
+// quasiquote quotes `node` and fills the values of the unquotes that belong to this quasiquote.
+// A quasiquoted unquote like `,x returns the value of x itself.
+func quasiquote(c *ParseContext, evalCtx *EvalCtx, node *node32) (Value, error) {
+	if node.pegRule == ruleUnquote {
+		v, err := c.parseNode(node.up, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		return filledValue(v), nil
+	}
+
+	holes := make(map[*node32][]Value)
+	// inArgs tells whether `n` is an argument of an expression or inside its Value, where ,@ is allowed.
+	var fill func(n *node32, depth int, inArgs bool) error
+	fill = func(n *node32, depth int, inArgs bool) error {
+		switch n.pegRule {
+		case ruleSpacing: // the unquotes in the datum comments are never filled
+			return nil
+		case ruleQuasiquote:
+			depth++
+		case ruleUnquote, ruleUnquoteSplicing:
+			if depth > 0 {
+				depth--
+				break
+			}
+			if n.pegRule == ruleUnquoteSplicing && !inArgs {
+				return evalErrorf(c, n, "unquote-splicing is only allowed in the arguments of an expression")
+			}
+			v, err := c.parseNode(n.up, evalCtx)
+			if err != nil {
+				return err
+			}
+			val := filledValue(v)
+			if n.pegRule == ruleUnquote {
+				holes[n] = []Value{val}
+				return nil
+			}
+			vs, ok := spliceValues(val)
+			if !ok {
+				return evalErrorf(c, n, "cannot splice a value of %s", val.Type())
+			}
+			holes[n] = vs
+			return nil
+		}
+		for cur := n.up; cur != nil; cur = cur.next {
+			arg := n.pegRule == ruleExpression && cur.pegRule == ruleValue || n.pegRule == ruleValue && inArgs
+			if err := fill(cur, depth, arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := fill(node, 0, false); err != nil {
+		return nil, err
+	}
+	if len(holes) == 0 {
+		return quote(c, node), nil
+	}
+
+	filled := *c
+	filled.holes = holes
+	for n, vs := range c.holes {
+		if _, ok := holes[n]; !ok {
+			filled.holes[n] = vs
+		}
+	}
+	return Code{node: node, pc: &filled}, nil
+}
+
+// filledValue returns the Value to fill in for the result `v` of an unquote or an interpolation, nil is taken as Nil.
+func filledValue(v any) Value {
+	val, _ := v.(Value)
+	return nilToNil(val)
+}
+
+// spliceValues returns the values that `,@` splices, which can be a UserData of []Value or nil.
+func spliceValues(v Value) ([]Value, bool) {
+	switch v := v.(type) {
+	case Nil:
+		return nil, true
+	case *UserData:
+		vs, ok := v.V.([]Value)
+		return vs, ok
+	}
+	return nil, false
+}
+
+func parseUnquote(c *ParseContext, evalCtx *EvalCtx, node *node32) (any, error) {
+	vs, ok := c.holes[node]
+	if !ok {
+		return nil, evalErrorf(c, node, "unquote outside a quasiquote")
+	}
+	return evalFilled(c, node, vs[0], evalCtx)
+}
+
+func parseUnquoteSplicing(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	if _, ok := c.holes[node]; !ok {
+		return nil, evalErrorf(c, node, "unquote-splicing outside a quasiquote")
+	}
+	return nil, evalErrorf(c, node, "unquote-splicing is only allowed in the arguments of an expression")
+}
+
+// evalFilled evaluates a value filled into `node` by a quasiquote or a macro,
+// a Code is evaluated, and a Symbol is evaluated as the identifier of its name.
+func evalFilled(c *ParseContext, node *node32, v Value, evalCtx *EvalCtx) (Value, error) {
+	switch v := v.(type) {
+	case Code:
+		return v.Eval(evalCtx)
+	case Symbol:
+		ret, ok := evalCtx.Lookup(v.Name())
+		if !ok {
+			return nil, newUnboundedIdentifierError(c, node, v.Name())
+		}
+		return ret, nil
+	}
+	return v, nil
+}
+
+// appendSpliced appends the values spliced by `,@` as the operands of an expression.
+func appendSpliced(operands []Expr, c *ParseContext, evalCtx *EvalCtx, vs []Value) []Expr {
+	for _, v := range vs {
+		if code, ok := v.(Code); ok {
+			operands = append(operands, newExpr(code.pc, evalCtx, code.node))
+			continue
+		}
+		operands = append(operands, Expr{evalCtx: evalCtx, pc: c, value: v}) // a Symbol is evaluated by Expr.Eval
+	}
+	return operands
+}
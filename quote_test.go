@@ -0,0 +1,210 @@
+package gendsl
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("Quote", func() {
+	var testEnv *Env
+	BeforeEach(func() {
+		testEnv = NewEnv().WithBuiltins().
+			WithProcedure("RETURN", Procedure{Eval: CheckNArgs("1", _return)}).
+			WithProcedure("DEFINE", Procedure{Eval: CheckNArgs("3", _define)}).
+			WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)}).
+			WithProcedure("ARRAY", Procedure{Eval: CheckNArgs("*", _array)})
+	})
+
+	It("can quote code as data", func() {
+		for _, expr := range []string{"'(PLUS 1 x)", "(quote (PLUS 1 x))", "(RETURN '(PLUS 1 x))"} {
+			v, err := EvalExpr(expr, testEnv)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v.Type()).Should(gomega.Equal(ValueType(ValueTypeCode)), expr)
+			code := v.(Code)
+			gomega.Expect(code.Text()).Should(gomega.Equal("(PLUS 1 x)"))
+			gomega.Expect(Repr(code)).Should(gomega.Equal("'(PLUS 1 x)"))
+			gomega.Expect(code.Node().Operator().Identifier()).Should(gomega.Equal("PLUS"))
+
+			v, err = code.Eval(NewEvalCtx(nil, nil, NewEnv().WithInt("x", 2)).Derive(testEnv))
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Expect(v).Should(gomega.Equal(Int(3)))
+		}
+
+		v, err := EvalExpr("'x", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(Repr(v)).Should(gomega.Equal("'x"))
+		v, err = EvalExpr("''1", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(Repr(v)).Should(gomega.Equal("''1"))
+	})
+
+	It("can evaluate code with eval", func() {
+		v, err := EvalExpr(`(DEFINE "c" '(PLUS x 1) (DEFINE "x" 41 (eval c)))`, testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(42)))
+
+		v, err = EvalExpr(`(eval (PLUS 1 2))`, testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(3)))
+	})
+
+	It("can fill the unquotes in a quasiquote", func() {
+		v, err := EvalExpr("(DEFINE \"x\" 2 `(PLUS ,x ,(PLUS x 1) y))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		code := v.(Code)
+		gomega.Expect(code.Text()).Should(gomega.Equal("(PLUS 2 3 y)"))
+		gomega.Expect(code.Node().Text()).Should(gomega.Equal("(PLUS ,x ,(PLUS x 1) y)"))
+
+		v, err = code.Eval(NewEvalCtx(nil, nil, testEnv).Derive(NewEnv().WithInt("x", 100).WithInt("y", 10)))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(15)))
+
+		v, err = EvalExpr("(DEFINE \"x\" 2 (quasiquote (PLUS ,x 1)))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Code).Text()).Should(gomega.Equal("(PLUS 2 1)"))
+	})
+
+	It("can compose code", func() {
+		v, err := EvalExpr("(DEFINE \"c\" '(PLUS x 1) (eval `(PLUS ,c ,c)))",
+			testEnv.Clone().WithInt("x", 1))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(4)))
+
+		v, err = EvalExpr("(DEFINE \"c\" '(PLUS x 1) `(RETURN ,c))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Code).Text()).Should(gomega.Equal("(RETURN (PLUS x 1))"))
+	})
+
+	It("can splice values", func() {
+		v, err := EvalExpr("(DEFINE \"xs\" (ARRAY 1 2 '(PLUS 1 2)) `(PLUS 1 ,@xs))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		code := v.(Code)
+		gomega.Expect(code.Text()).Should(gomega.Equal("(PLUS 1 1 2 (PLUS 1 2))"))
+		v, err = code.Eval(NewEvalCtx(nil, nil, testEnv))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(7)))
+
+		v, err = EvalExpr("(DEFINE \"xs\" nil (eval `(PLUS 1 ,@xs)))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+
+		_, err = EvalExpr("(DEFINE \"xs\" 1 `(PLUS 1 ,@xs))", testEnv)
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+	})
+
+	It("fills nil for an unquote that returns nil", func() {
+		env := testEnv.Clone().WithProcedure("NILP", Procedure{Eval: func(*EvalCtx, []Expr, map[string]Value) (Value, error) {
+			return nil, nil
+		}})
+		for _, expr := range []string{"(quasiquote (NILP ,(NILP)))", "`(NILP ,(NILP))", "`(NILP ,@(NILP))"} {
+			v, err := EvalExpr(expr, env)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v.Type()).Should(gomega.Equal(ValueType(ValueTypeCode)), expr)
+		}
+
+		v, err := EvalExpr("`(RETURN ,(NILP))", env)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		v, err = v.(Code).Eval(NewEvalCtx(nil, nil, env))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Nil{}))
+	})
+
+	It("returns the value of a quasiquoted unquote", func() {
+		for _, expr := range []string{"(DEFINE \"x\" 2 `,x)", "(DEFINE \"x\" 2 (quasiquote ,x))", "(DEFINE \"x\" 2 `,(PLUS x 0))"} {
+			v, err := EvalExpr(expr, testEnv)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v).Should(gomega.Equal(Int(2)), expr)
+		}
+
+		v, err := EvalExpr("(DEFINE \"c\" '(PLUS 1 2) `,c)", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Code).Text()).Should(gomega.Equal("(PLUS 1 2)"))
+	})
+
+	It("fills the unquotes again when the quasiquote is evaluated again", func() {
+		pc, err := MakeParseContext("(RETURN `(PLUS ,x))")
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		quoted := pc.Root().Args()[0].Quoted()
+		stale := *pc
+		stale.holes = map[*node32][]Value{quoted.Args()[0].node: {Int(100)}}
+
+		v, err := quasiquote(&stale, NewEvalCtx(nil, nil, testEnv.Clone().WithInt("x", 1)), quoted.node)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Code).Text()).Should(gomega.Equal("(PLUS 1)"))
+	})
+
+	It("keeps the unquotes of the nested quasiquotes", func() {
+		v, err := EvalExpr("(DEFINE \"x\" 1 `(RETURN `(PLUS ,x ,,x)))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Code).Text()).Should(gomega.Equal("(RETURN `(PLUS ,x ,1))"))
+	})
+
+	It("reports the unquotes outside a quasiquote", func() {
+		_, err := EvalExpr("(PLUS 1 ,x)", testEnv)
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+		_, err = EvalExpr("(PLUS 1 ,@x)", testEnv)
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+		for _, expr := range []string{"(DEFINE \"xs\" (ARRAY 1) `,@xs)", "(DEFINE \"xs\" (ARRAY 1) (quasiquote ,@xs))", "(DEFINE \"xs\" (ARRAY 1) `#f\"${,@xs}\")"} {
+			_, err = EvalExpr(expr, testEnv)
+			var evalErr *EvaluateError
+			gomega.Expect(errors.As(err, &evalErr)).Should(gomega.BeTrue(), expr)
+			gomega.Expect(evalErr.Error()).Should(gomega.ContainSubstring("unquote-splicing is only allowed in the arguments of an expression"), expr)
+			gomega.Expect(evalErr.BeginSym).Should(gomega.Equal(strings.Index(expr, ",@")+1), expr)
+		}
+	})
+
+	It("can be inspected as a node", func() {
+		pc, err := MakeParseContext("(PLUS '(PLUS 1 2) `(PLUS ,x))")
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		args := pc.Root().Args()
+		gomega.Expect(args).Should(gomega.HaveLen(2))
+		gomega.Expect(args[0].Type()).Should(gomega.Equal(ExprTypeQuote))
+		gomega.Expect(args[0].Quoted().Text()).Should(gomega.Equal("(PLUS 1 2)"))
+		gomega.Expect(args[1].Quoted().Args()[0].Quoted().Text()).Should(gomega.Equal("x"))
+
+		ids := make([]string, 0)
+		Inspect(pc.Root(), func(n Node) bool {
+			if n.Type() == ExprTypeIdentifier {
+				ids = append(ids, n.Identifier())
+			}
+			return true
+		})
+		gomega.Expect(ids).Should(gomega.Equal([]string{"PLUS", "PLUS", "PLUS", "x"}))
+	})
+
+	It("defines the builtins only in the envs that opt in", func() {
+		env := NewEnv().WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)})
+		for _, expr := range []string{"(quote 1)", "(quasiquote 1)", "(eval 1)", "(defmacro ID (x) x (ID 1))"} {
+			_, err := EvalExpr(expr, env)
+			gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("unsupported operator")), expr)
+		}
+		v, err := EvalExpr("'(PLUS 1 2)", env)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Code).Text()).Should(gomega.Equal("(PLUS 1 2)"))
+
+		eval, ok := Builtin("eval")
+		gomega.Expect(ok).Should(gomega.BeTrue())
+		gomega.Expect(IsBuiltin(eval, "eval")).Should(gomega.BeTrue())
+		gomega.Expect(IsBuiltin(eval, "quote")).Should(gomega.BeFalse())
+		v, err = EvalExpr("(eval '(PLUS 1 2))", env.Clone().WithProcedure("eval", eval))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(3)))
+		_, err = EvalExpr("(quote 1)", env.Clone().WithProcedure("eval", eval))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+
+		quote := Procedure{Eval: CheckNArgs("1", _return)}
+		gomega.Expect(IsBuiltin(quote, "quote")).Should(gomega.BeFalse())
+		v, err = EvalExpr("(quote 1)", env.Clone().WithProcedure("quote", quote).WithBuiltins())
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+	})
+
+	It("allows the env to override the builtins", func() {
+		v, err := EvalExpr("(quote 1)", testEnv.Clone().WithProcedure("quote", Procedure{Eval: CheckNArgs("1", _return)}))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+	})
+})
@@ -0,0 +1,162 @@
+package gendsl
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Regexp is a regular expression written like #rx"^foo\d+" in the DSL, its syntax is the one of [regexp].
+// The pattern is taken as it is except that \" stands for a '"', so no extra escaping is needed for the backslashes.
+// A regexp literal is compiled once by [gendsl.MakeParseContext], which reports a [gendsl.SyntaxError] if it is invalid.
+//
+// The methods of Regexp take a String or Bytes and return the same type of the subject.
+type Regexp struct {
+	re *regexp.Regexp
+}
+
+var _ Value = Regexp{}
+
+// NewRegexp returns the Regexp of `re`, it will panic if re is nil.
+func NewRegexp(re *regexp.Regexp) Regexp {
+	if re == nil {
+		panic("re cannot be nil")
+	}
+	return Regexp{re: re}
+}
+
+func (Regexp) _value()         {}
+func (Regexp) Type() ValueType { return ValueTypeRegexp }
+func (r Regexp) Unwrap() any   { return r.re }
+
+// Regexp returns the compiled regular expression.
+func (r Regexp) Regexp() *regexp.Regexp { return r.re }
+
+// Match reports whether the String or Bytes `v` contains any match of the Regexp.
+func (r Regexp) Match(v Value) (bool, error) {
+	switch v := v.(type) {
+	case String:
+		return r.re.MatchString(string(v)), nil
+	case Bytes:
+		return r.re.Match(v), nil
+	}
+	return false, r.subjectError(v)
+}
+
+// Find returns the leftmost match in `v`, or Nil if there is no match.
+func (r Regexp) Find(v Value) (Value, error) {
+	vs, err := r.Submatch(v)
+	if err != nil || vs == nil {
+		return Nil{}, err
+	}
+	return vs[0], nil
+}
+
+// Submatch returns the leftmost match in `v` followed by the matches of the groups,
+// a group that does not participate in the match is Nil. It returns nil if there is no match.
+func (r Regexp) Submatch(v Value) ([]Value, error) {
+	var (
+		loc []int
+		sub func(i, j int) Value
+	)
+	switch v := v.(type) {
+	case String:
+		loc = r.re.FindStringSubmatchIndex(string(v))
+		sub = func(i, j int) Value { return v[i:j] }
+	case Bytes:
+		loc = r.re.FindSubmatchIndex(v)
+		sub = func(i, j int) Value { return append(Bytes{}, v[i:j]...) }
+	default:
+		return nil, r.subjectError(v)
+	}
+	if loc == nil {
+		return nil, nil
+	}
+	ret := make([]Value, 0, len(loc)/2)
+	for i := 0; i < len(loc); i += 2 {
+		if loc[i] < 0 {
+			ret = append(ret, Nil{})
+			continue
+		}
+		ret = append(ret, sub(loc[i], loc[i+1]))
+	}
+	return ret, nil
+}
+
+func (r Regexp) subjectError(v Value) error {
+	return errors.Errorf("cannot match %s with #rx%q, expecting string or bytes", typeName(v), r.re.String())
+}
+
+// regexpPattern reads the pattern of a regexp literal.
+func regexpPattern(text string) string {
+	body := text[len(`#rx"`) : len(text)-1]
+	return strings.ReplaceAll(body, `\"`, `"`)
+}
+
+// compileRegexps compiles the regexp literals in a script except the ones in datum comments,
+// they are keyed by their offsets in the script.
+func compileRegexps(p *parser) (map[uint32]*regexp.Regexp, error) {
+	var (
+		ret       map[uint32]*regexp.Regexp
+		commented [][2]uint32
+	)
+	for _, t := range p.Tokens() {
+		if t.pegRule == ruleDatumComment {
+			commented = append(commented, [2]uint32{t.begin, t.end})
+		}
+	}
+	for _, t := range p.Tokens() {
+		if t.pegRule != ruleRegexpLiteral || inRanges(commented, t.begin) {
+			continue
+		}
+		re, err := regexp.Compile(regexpPattern(string(p.buffer[t.begin:t.end])))
+		if err != nil {
+			return nil, syntaxErrorf(p, t.begin, t.end, "invalid regexp literal: %s", err)
+		}
+		if ret == nil {
+			ret = make(map[uint32]*regexp.Regexp)
+		}
+		ret[t.begin] = re
+	}
+	return ret, nil
+}
+
+func inRanges(ranges [][2]uint32, offset uint32) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRegexpLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	re, ok := c.regexps[node.begin]
+	if !ok {
+		return nil, evalErrorf(c, node, "regexp literal is not compiled")
+	}
+	return Regexp{re: re}, nil
+}
+
+func reprRegexp(r Regexp) string {
+	var sb strings.Builder
+	sb.WriteString(`#rx"`)
+	pattern := r.re.String()
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern) && pattern[i+1] != '"':
+			sb.WriteString(pattern[i : i+2])
+			i++
+		case pattern[i] == '\\' && i+1 < len(pattern): // \" matches a '"' as well
+			sb.WriteString(`\"`)
+			i++
+		case pattern[i] == '"':
+			sb.WriteString(`\"`)
+		default:
+			sb.WriteByte(pattern[i])
+		}
+	}
+	sb.WriteString(`"`)
+	return sb.String()
+}
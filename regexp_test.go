@@ -0,0 +1,95 @@
+package gendsl
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Regexp", func() {
+	It("compiles the literals when parsing", func() {
+		for expr, want := range map[string]string{
+			`#rx"^foo\d+"`:                `^foo\d+`,
+			`#rx"say \"hi\""`:             `say "hi"`,
+			`#rx"a\\\"b"`:                 `a\\"b`,
+			`#rx""`:                       ``,
+			`(RETURN #:r #rx"x" #rx"\\")`: `\\`,
+		} {
+			v, err := EvalExpr(expr, NewEnv().WithProcedure("RETURN", Procedure{Eval: CheckNArgs("1", _return)}))
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v.(Regexp).Regexp().String()).Should(gomega.Equal(want), expr)
+		}
+
+		_, err := MakeParseContext("(RETURN\n  #rx\"(foo\")")
+		var se *SyntaxError
+		gomega.Expect(errors.As(err, &se)).Should(gomega.BeTrue())
+		gomega.Expect(se.BeginLine).Should(gomega.Equal(2))
+		gomega.Expect(se.BeginSym).Should(gomega.Equal(3))
+		gomega.Expect(se.Error()).Should(gomega.ContainSubstring("missing closing )"))
+
+		pc, err := MakeParseContext(`#rx"^a+$"`)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		v1, err := pc.Eval(NewEvalCtx(nil, nil, NewEnv()))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		v2, err := pc.Eval(NewEvalCtx(nil, nil, NewEnv()))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v1.Unwrap()).Should(gomega.BeIdenticalTo(v2.Unwrap()))
+	})
+
+	It("compiles the literals when loading a compiled script", func() {
+		pc, err := MakeParseContext(`#rx"^a+$"`)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		data, err := pc.MarshalBinary()
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		pc, err = LoadCompiled(data)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		v, err := pc.Eval(NewEvalCtx(nil, nil, NewEnv()))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Regexp).Regexp().String()).Should(gomega.Equal("^a+$"))
+	})
+
+	It("matches strings and bytes", func() {
+		re := NewRegexp(regexp.MustCompile(`(\w+)@(\w+)?\.com`))
+		ok, err := re.Match(String("mail bob@.com"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(ok).Should(gomega.BeTrue())
+		ok, err = re.Match(Bytes("nothing"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(ok).Should(gomega.BeFalse())
+		_, err = re.Match(Int(1))
+		gomega.Expect(err).Should(gomega.HaveOccurred())
+
+		v, err := re.Find(String("mail bob@example.com"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(String("bob@example.com")))
+		v, err = re.Find(String("nothing"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Nil{}))
+
+		vs, err := re.Submatch(String("mail bob@.com"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(vs).Should(gomega.Equal([]Value{String("bob@.com"), String("bob"), Nil{}}))
+		vs, err = re.Submatch(Bytes("bob@example.com"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(vs).Should(gomega.Equal([]Value{Bytes("bob@example.com"), Bytes("bob"), Bytes("example")}))
+		vs, err = re.Submatch(Bytes("nothing"))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(vs).Should(gomega.BeNil())
+		gomega.Expect(func() { NewRegexp(nil) }).Should(gomega.Panic())
+	})
+
+	It("prints the literals", func() {
+		for _, pattern := range []string{`^foo\d+`, `say "hi"`, `a\"b`, `a\\`, ``} {
+			re := NewRegexp(regexp.MustCompile(pattern))
+			text := Repr(re)
+			v, err := EvalExpr(text, NewEnv())
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), text)
+			for _, s := range []string{`say "hi"`, `foo12`, `a"b`, `a\`, ``} {
+				gomega.Expect(v.(Regexp).Regexp().MatchString(s)).Should(gomega.Equal(re.Regexp().MatchString(s)), text+" "+s)
+			}
+		}
+		gomega.Expect(Repr(NewRegexp(regexp.MustCompile(`say "hi"\d`)))).Should(gomega.Equal(`#rx"say \"hi\"\d"`))
+	})
+})
@@ -0,0 +1,75 @@
+package gendsl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repr returns the representation of `v` in the DSL syntax.
+// For literal values, code, symbols and keywords, the result can be parsed back into an equal value,
+// other values are printed in the form of #<type ...>.
+func Repr(v Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case Int:
+		return strconv.FormatInt(int64(v), 10)
+	case Uint:
+		return strconv.FormatUint(uint64(v), 10) + "u"
+	case String:
+		return strconv.Quote(string(v))
+	case Float:
+		return reprFloat(float64(v))
+	case Bool:
+		if v {
+			return "#t"
+		}
+		return "#f"
+	case Nil:
+		return "nil"
+	case Procedure:
+		return "#<procedure>"
+	case *UserData:
+		return fmt.Sprintf("#<userdata %v>", v.V)
+	case Code:
+		return "'" + v.Text()
+	case Symbol:
+		return "'" + v.Name()
+	case Keyword:
+		return ":" + v.Name()
+	case BigInt:
+		return v.String() + "n"
+	case Decimal:
+		return reprDecimal(v)
+	case Time:
+		return "#inst " + strconv.Quote(time.Time(v).Format(time.RFC3339Nano))
+	case Duration:
+		return time.Duration(v).String()
+	case Bytes:
+		return `#x"` + hex.EncodeToString(v) + `"`
+	case Regexp:
+		return reprRegexp(v)
+	}
+	return fmt.Sprintf("#<%s %v>", v.Type(), v.Unwrap())
+}
+
+func reprFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	// make sure that the text is not read as an integer
+	if !strings.ContainsAny(s, ".eEn") {
+		s += ".0"
+	}
+	return s
+}
+
+func reprDecimal(d Decimal) string {
+	s := d.String()
+	// a decimal literal needs a point
+	if !strings.Contains(s, ".") {
+		s += "."
+	}
+	return s + "m"
+}
@@ -0,0 +1,172 @@
+package gendsl
+
+import "strings"
+
+type (
+	// Scope holds the identifiers that the procedure calls around a node make visible to it,
+	// it is used by the tools that analyze the scripts without evaluating them, see [gendsl.Walker].
+	Scope struct {
+		parent   *Scope
+		node     Node
+		bindings map[string]Binding
+	}
+
+	// Walker walks the nodes of a script that are evaluated with the scopes visible to them.
+	// The code quoted is skipped except for the unquotes that belong to the quasiquotes,
+	// and so are the arguments that define identifiers by the Binders of the procedures.
+	//
+	// The children of a procedure call are walked in the order of the operator, the values of the options and the arguments.
+	Walker struct {
+		// Env is the env that the script is evaluated with, the Binders of its procedures make the scopes.
+		Env *Env
+		// Enter is called with each node and its scope before the children of the node are walked.
+		Enter func(n Node, sc *Scope)
+		// Leave is called with each node and its scope after the children of the node are walked.
+		Leave func(n Node, sc *Scope)
+		// Bind is called with the bindings introduced by a procedure call before its arguments are walked.
+		Bind func(call Node, bindings []Binding)
+	}
+)
+
+// NewScope returns the scope of a whole script with the bindings visible everywhere,
+// like the identifiers added to the EvalCtx by the host.
+func NewScope(bindings ...Binding) *Scope {
+	sc := &Scope{bindings: make(map[string]Binding, len(bindings))}
+	for _, b := range bindings {
+		sc.bindings[b.Name] = b
+	}
+	return sc
+}
+
+// Parent returns the scope that the scope is nested in, or nil if it is the scope of a whole script.
+func (s *Scope) Parent() *Scope {
+	return s.parent
+}
+
+// Node returns the argument that the scope is made for, or a zero Node if it is the scope of a whole script.
+func (s *Scope) Node() Node {
+	return s.node
+}
+
+// Lookup returns the binding of `id` in the scope or the scopes it is nested in.
+func (s *Scope) Lookup(id string) (Binding, bool) {
+	for ; s != nil; s = s.parent {
+		if b, ok := s.bindings[id]; ok {
+			return b, true
+		}
+	}
+	return Binding{}, false
+}
+
+// Range calls f for each binding in the scope but not the ones in its parent, it stops if f returns false.
+func (s *Scope) Range(f func(b Binding) bool) {
+	for _, b := range s.bindings {
+		if !f(b) {
+			return
+		}
+	}
+}
+
+// Walk walks the node `n` in the scope `sc`, the scope of a whole script is used if `sc` is nil.
+func (w *Walker) Walk(n Node, sc *Scope) {
+	if n.IsZero() {
+		return
+	}
+	if sc == nil {
+		sc = NewScope()
+	}
+	if w.Enter != nil {
+		w.Enter(n, sc)
+	}
+	switch n.Type() {
+	case ExprTypeInterpolation:
+		for _, child := range n.Children() {
+			w.Walk(child, sc)
+		}
+	case ExprTypeQuote:
+		switch text := n.Text(); {
+		case strings.HasPrefix(text, "`"):
+			w.walkQuasiquoted(n.Quoted(), sc, 1)
+		case strings.HasPrefix(text, ","):
+			w.Walk(n.Quoted(), sc)
+		}
+	case ExprTypeExpr:
+		w.walkCall(n, sc)
+	}
+	if w.Leave != nil {
+		w.Leave(n, sc)
+	}
+}
+
+func (w *Walker) walkCall(n Node, sc *Scope) {
+	op := n.Operator()
+	w.Walk(op, sc)
+
+	var proc Value
+	if _, local := sc.Lookup(op.Identifier()); !local && w.Env != nil {
+		proc, _ = w.Env.Lookup(op.Identifier())
+	}
+	switch {
+	case IsBuiltin(proc, "quote"):
+		return
+	case IsBuiltin(proc, "quasiquote"):
+		for _, arg := range n.Args() {
+			w.walkQuasiquoted(arg, sc, 1)
+		}
+		return
+	}
+	for _, opt := range n.Options() {
+		w.Walk(opt.Value, sc)
+	}
+
+	var (
+		bindings []Binding
+		defs     = make(map[Node]bool)
+	)
+	if proc != nil {
+		bindings = w.Env.Bindings(n)
+	}
+	if len(bindings) > 0 && w.Bind != nil {
+		w.Bind(n, bindings)
+	}
+	for _, b := range bindings {
+		if !b.Def.IsZero() {
+			defs[b.Def] = true
+		}
+	}
+	for i, arg := range n.Args() {
+		if defs[arg] {
+			continue
+		}
+		argScope := sc
+		for _, b := range bindings {
+			if !b.VisibleIn(i) {
+				continue
+			}
+			if argScope == sc {
+				argScope = &Scope{parent: sc, node: arg, bindings: make(map[string]Binding)}
+			}
+			argScope.bindings[b.Name] = b
+		}
+		w.Walk(arg, argScope)
+	}
+}
+
+// walkQuasiquoted walks the unquotes in the code quoted by `depth` quasiquotes that belong to the outermost one.
+func (w *Walker) walkQuasiquoted(n Node, sc *Scope, depth int) {
+	if n.Type() == ExprTypeQuote {
+		switch text := n.Text(); {
+		case strings.HasPrefix(text, "`"):
+			depth++
+		case strings.HasPrefix(text, ","):
+			depth--
+		}
+		if depth == 0 {
+			w.Walk(n.Quoted(), sc)
+			return
+		}
+	}
+	for _, child := range n.Children() {
+		w.walkQuasiquoted(child, sc, depth)
+	}
+}
@@ -0,0 +1,70 @@
+package gendsl
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("Walker", func() {
+	type walked struct {
+		entered, left, defs, scopes []string
+		bound                       map[string]bool
+	}
+	env := NewEnv().WithBuiltins().
+		WithProcedure("LET", Procedure{Eval: CheckNArgs("3", _define), Binder: BindArg(0, 2)}).
+		WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)})
+
+	walk := func(script string, sc *Scope) walked {
+		pc, err := MakeParseContext(script)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		ret := walked{bound: make(map[string]bool)}
+		w := &Walker{
+			Env: env,
+			Enter: func(n Node, sc *Scope) {
+				ret.entered = append(ret.entered, n.Text())
+				if sc.Node() == n {
+					ret.scopes = append(ret.scopes, n.Text())
+				}
+				if id := n.Identifier(); id != "" {
+					_, ret.bound[id] = sc.Lookup(id)
+				}
+			},
+			Leave: func(n Node, _ *Scope) {
+				ret.left = append(ret.left, n.Text())
+			},
+			Bind: func(_ Node, bindings []Binding) {
+				for _, b := range bindings {
+					ret.defs = append(ret.defs, b.Def.Text())
+				}
+			},
+		}
+		w.Walk(pc.Root(), sc)
+		return ret
+	}
+
+	It("walks the nodes evaluated with their scopes", func() {
+		ret := walk("(LET x 1 (PLUS x #:n y))", nil)
+		gomega.Expect(ret.entered).Should(gomega.Equal([]string{"(LET x 1 (PLUS x #:n y))", "LET", "1", "(PLUS x #:n y)", "PLUS", "y", "x"}))
+		gomega.Expect(ret.left).Should(gomega.Equal([]string{"LET", "1", "PLUS", "y", "x", "(PLUS x #:n y)", "(LET x 1 (PLUS x #:n y))"}))
+		gomega.Expect(ret.bound).Should(gomega.Equal(map[string]bool{"LET": false, "PLUS": false, "x": true, "y": false}))
+		gomega.Expect(ret.defs).Should(gomega.Equal([]string{"x"}))
+		gomega.Expect(ret.scopes).Should(gomega.Equal([]string{"(PLUS x #:n y)"}))
+
+		ret = walk("(PLUS x y)", NewScope(Binding{Name: "y"}))
+		gomega.Expect(ret.bound).Should(gomega.Equal(map[string]bool{"PLUS": false, "x": false, "y": true}))
+	})
+
+	It("walks only the unquotes that belong to the quasiquotes", func() {
+		ret := walk("(PLUS 'a `(b ,c `(d ,e ,,f)) (quote g) (quasiquote (h ,i)))", nil)
+		gomega.Expect(ret.entered).Should(gomega.Equal([]string{
+			"(PLUS 'a `(b ,c `(d ,e ,,f)) (quote g) (quasiquote (h ,i)))", "PLUS",
+			"'a", "`(b ,c `(d ,e ,,f))", "c", "f",
+			"(quote g)", "quote",
+			"(quasiquote (h ,i))", "quasiquote", "i",
+		}))
+
+		ret = walk("(LET quote 1 (quote x))", nil)
+		gomega.Expect(ret.defs).Should(gomega.Equal([]string{"quote"}))
+		gomega.Expect(ret.entered).Should(gomega.ContainElement("x"))
+	})
+})
@@ -0,0 +1,98 @@
+package gendsl
+
+import "sync"
+
+type (
+	// Symbol is a name, it is produced by quoting an identifier like 'foo.
+	// Symbols are interned, so two Symbols of the same name are equal by == which compares only a pointer.
+	// When a Symbol is filled into code by a quasiquote, it is evaluated as the identifier of its name.
+	Symbol struct {
+		name *string
+	}
+
+	// Keyword is a literal like :red that evaluates to itself, it is useful for tags and enums.
+	// Keywords are interned, so two Keywords of the same name are equal by == which compares only a pointer.
+	Keyword struct {
+		name *string
+	}
+)
+
+var (
+	_ Value = Symbol{}
+	_ Value = Keyword{}
+)
+
+// interned holds the names of the Symbols and Keywords, they are never released.
+var interned sync.Map // map[string]*string
+
+func intern(name string) *string {
+	if p, ok := interned.Load(name); ok {
+		return p.(*string)
+	}
+	p, _ := interned.LoadOrStore(name, &name)
+	return p.(*string)
+}
+
+// NewSymbol returns the Symbol of `name`.
+func NewSymbol(name string) Symbol {
+	return Symbol{name: intern(name)}
+}
+
+func (Symbol) _value()         {}
+func (Symbol) Type() ValueType { return ValueTypeSymbol }
+func (s Symbol) Unwrap() any   { return s.Name() }
+
+// Name returns the name of the Symbol, which is empty for a zero Symbol.
+func (s Symbol) Name() string {
+	if s.name == nil {
+		return ""
+	}
+	return *s.name
+}
+
+// NewKeyword returns the Keyword of `name`, the name does not include the leading ':'.
+func NewKeyword(name string) Keyword {
+	return Keyword{name: intern(name)}
+}
+
+func (Keyword) _value()         {}
+func (Keyword) Type() ValueType { return ValueTypeKeyword }
+func (k Keyword) Unwrap() any   { return k.Name() }
+
+// Name returns the name of the Keyword without the leading ':', which is empty for a zero Keyword.
+func (k Keyword) Name() string {
+	if k.name == nil {
+		return ""
+	}
+	return *k.name
+}
+
+func parseKeywordLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	return NewKeyword(c.nodeText(node)[1:]), nil
+}
+
+// Symbol returns the Symbol of the identifier if the expression is an identifier like `foo`,
+// so that procedures can take names without evaluating them.
+func (e Expr) Symbol() (Symbol, bool) {
+	if e.node == nil {
+		s, ok := e.value.(Symbol)
+		return s, ok
+	}
+	if e.node.pegRule != ruleIdentifier {
+		return Symbol{}, false
+	}
+	return NewSymbol(readIdentifierText(e.pc, e.node)), true
+}
+
+// keyName returns the identifier that a key refers to, a key can be a Symbol, a Keyword or a String.
+func keyName(key Value) (string, bool) {
+	switch key := key.(type) {
+	case Symbol:
+		return key.Name(), key.name != nil
+	case Keyword:
+		return key.Name(), key.name != nil
+	case String:
+		return string(key), true
+	}
+	return "", false
+}
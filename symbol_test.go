@@ -0,0 +1,116 @@
+package gendsl
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Symbol and Keyword", func() {
+	var testEnv *Env
+	BeforeEach(func() {
+		testEnv = NewEnv().WithBuiltins().
+			WithProcedure("RETURN", Procedure{Eval: CheckNArgs("1", _return)}).
+			WithProcedure("PLUS", Procedure{Eval: CheckNArgs("*", _plus)}).
+			WithProcedure("ARRAY", Procedure{Eval: CheckNArgs("*", _array)}).
+			WithProcedure("DEFINE", Procedure{Eval: CheckNArgs("3", _define)}).
+			WithProcedure("LET", Procedure{Eval: CheckNArgs("3", func(evalCtx *EvalCtx, args []Expr, _ map[string]Value) (Value, error) {
+				name, ok := args[0].Symbol()
+				if !ok {
+					return nil, errors.New("expecting an identifier")
+				}
+				val, err := args[1].Eval()
+				if err != nil {
+					return nil, err
+				}
+				return args[2].EvalWithEnv(NewEnv().WithKey(name, val))
+			})}).
+			WithProcedure("GET", Procedure{Eval: CheckNArgs("1", func(evalCtx *EvalCtx, args []Expr, _ map[string]Value) (Value, error) {
+				key, err := args[0].Eval()
+				if err != nil {
+					return nil, err
+				}
+				v, ok := evalCtx.LookupKey(key)
+				if !ok {
+					return Nil{}, nil
+				}
+				return v, nil
+			})})
+	})
+
+	It("interns the symbols and keywords", func() {
+		gomega.Expect(NewSymbol("foo") == NewSymbol("foo")).Should(gomega.BeTrue())
+		gomega.Expect(NewSymbol("foo") == NewSymbol("bar")).Should(gomega.BeFalse())
+		gomega.Expect(NewKeyword("red") == NewKeyword("red")).Should(gomega.BeTrue())
+		gomega.Expect(NewSymbol("red").Unwrap()).Should(gomega.Equal("red"))
+		gomega.Expect(Symbol{}.Name()).Should(gomega.BeEmpty())
+	})
+
+	It("can parse keywords", func() {
+		for expr, name := range map[string]string{
+			":red":        "red",
+			":is-string?": "is-string?",
+			":0":          "0",
+			"(RETURN :a)": "a",
+		} {
+			v, err := EvalExpr(expr, testEnv)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v).Should(gomega.Equal(NewKeyword(name)), expr)
+		}
+		gomega.Expect(Repr(NewKeyword("red"))).Should(gomega.Equal(":red"))
+
+		for _, expr := range []string{":", ":a:b", "(RETURN :a:b)"} {
+			_, err := MakeParseContext(expr)
+			gomega.Expect(err).Should(gomega.HaveOccurred(), expr)
+		}
+
+		pc, err := MakeParseContext("(RETURN #:color :red :blue)")
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(pc.Root().Options()[0].Value.Literal()).Should(gomega.Equal(NewKeyword("red")))
+	})
+
+	It("quotes the identifiers into symbols", func() {
+		for _, expr := range []string{"'foo", "(quote foo)", "`foo"} {
+			v, err := EvalExpr(expr, testEnv)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v).Should(gomega.Equal(NewSymbol("foo")), expr)
+		}
+		gomega.Expect(Repr(NewSymbol("foo"))).Should(gomega.Equal("'foo"))
+	})
+
+	It("evaluates the symbols filled into code as identifiers", func() {
+		v, err := EvalExpr("(DEFINE \"s\" 'x (eval `(PLUS ,s 1)))", testEnv.Clone().WithInt("x", 41))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(42)))
+
+		v, err = EvalExpr("(DEFINE \"s\" (ARRAY 'x 'x) `(PLUS ,@s))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.(Code).Text()).Should(gomega.Equal("(PLUS x x)"))
+		v, err = v.(Code).Eval(NewEvalCtx(nil, nil, testEnv.Clone().WithInt("x", 2)))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(4)))
+
+		_, err = EvalExpr("(DEFINE \"s\" 'y (eval `(PLUS ,s 1)))", testEnv)
+		var ue *UnboundedIdentifierError
+		gomega.Expect(errors.As(err, &ue)).Should(gomega.BeTrue())
+		gomega.Expect(ue.ID).Should(gomega.Equal("y"))
+	})
+
+	It("can be used as the keys of env", func() {
+		v, err := EvalExpr("(LET foo 1 (PLUS foo (GET 'foo) (GET :foo) (GET \"foo\")))", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Int(4)))
+
+		v, err = EvalExpr("(GET 'bar)", testEnv)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v).Should(gomega.Equal(Nil{}))
+
+		env := NewEnv().WithKey(NewKeyword("red"), Int(1))
+		v, ok := env.LookupKey(NewSymbol("red"))
+		gomega.Expect(ok).Should(gomega.BeTrue())
+		gomega.Expect(v).Should(gomega.Equal(Int(1)))
+		_, ok = env.LookupKey(Int(1))
+		gomega.Expect(ok).Should(gomega.BeFalse())
+		gomega.Expect(func() { env.WithKey(Int(1), Int(1)) }).Should(gomega.Panic())
+	})
+})
@@ -0,0 +1,131 @@
+package gendsl
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// timeLayouts are the layouts accepted by a #inst literal.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02"}
+
+// Time is an instant, it is written like #inst "2024-05-01T10:00:00Z" in the DSL.
+// A date like #inst "2024-05-01" is the midnight of the date in UTC.
+type Time time.Time
+
+var _ Value = Time{}
+
+func (Time) _value()         {}
+func (Time) Type() ValueType { return ValueTypeTime }
+func (t Time) Unwrap() any   { return time.Time(t) }
+
+// Duration is an elapsed time like 1h30m or 500ms in the DSL, it uses the units of [time.ParseDuration].
+// Note that 1.5m is a [gendsl.Decimal], a duration with a fraction of minutes should be written like 1m30s.
+type Duration time.Duration
+
+var _ Value = Duration(0)
+
+func (Duration) _value()         {}
+func (Duration) Type() ValueType { return ValueTypeDuration }
+func (d Duration) Unwrap() any   { return time.Duration(d) }
+
+// ParseTime parses the text of a #inst literal, which is either in the format of RFC 3339 or a date like "2024-05-01".
+func ParseTime(s string) (Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Time(t), nil
+		}
+	}
+	return Time{}, errors.Errorf("invalid time %q, expecting RFC 3339 like 2024-05-01T10:00:00Z or a date like 2024-05-01", s)
+}
+
+func parseTimeLiteral(c *ParseContext, evalCtx *EvalCtx, node *node32) (any, error) {
+	for cur := node.up; cur != nil; cur = cur.next {
+		if cur.pegRule != ruleStringLiteral {
+			continue
+		}
+		s, err := parseStringLiteral(c, evalCtx, cur)
+		if err != nil {
+			return nil, err
+		}
+		t, err := ParseTime(string(s.(String)))
+		if err != nil {
+			return nil, evalErrorf(c, cur, "invalid time literal: %s", err)
+		}
+		return t, nil
+	}
+	return nil, evalErrorf(c, node, "invalid time literal")
+}
+
+func parseDurationLiteral(c *ParseContext, _ *EvalCtx, node *node32) (any, error) {
+	text := c.nodeText(node)
+	d, err := time.ParseDuration(strings.ReplaceAll(text, "_", ""))
+	if err != nil {
+		return nil, evalErrorf(c, node, "invalid duration literal %q: %s", text, err)
+	}
+	return Duration(d), nil
+}
+
+// arithTime does the arithmetic of Time and Duration:
+//   - Time + Duration, Duration + Time and Time - Duration give a Time.
+//   - Time - Time and Duration ± Duration give a Duration.
+//   - Duration * integer, integer * Duration and Duration / integer give a Duration.
+//   - Duration / Duration gives an Int truncated toward zero.
+func arithTime(op byte, a, b Value) (Value, error) {
+	switch x := a.(type) {
+	case Time:
+		switch y := b.(type) {
+		case Duration:
+			switch op {
+			case '+':
+				return Time(time.Time(x).Add(time.Duration(y))), nil
+			case '-':
+				return Time(time.Time(x).Add(-time.Duration(y))), nil
+			}
+		case Time:
+			if op == '-' {
+				d := time.Time(x).Sub(time.Time(y))
+				if !time.Time(y).Add(d).Equal(time.Time(x)) {
+					return nil, errors.New("duration overflows")
+				}
+				return Duration(d), nil
+			}
+		}
+	case Duration:
+		switch y := b.(type) {
+		case Time:
+			if op == '+' {
+				return Time(time.Time(y).Add(time.Duration(x))), nil
+			}
+		case Duration:
+			switch op {
+			case '+', '-':
+				return durationOf(arith(op, Int(x), Int(y)))
+			case '/':
+				return arith(op, Int(x), Int(y))
+			}
+		case Int, Uint:
+			if op == '*' || op == '/' {
+				return durationOf(arith(op, Int(x), y))
+			}
+		}
+	case Int, Uint:
+		if y, ok := b.(Duration); ok && op == '*' {
+			return durationOf(arith(op, a, Int(y)))
+		}
+	}
+	return nil, errors.Errorf("unsupported operands %s and %s for %c", typeName(a), typeName(b), op)
+}
+
+// durationOf converts the integer nanoseconds to a Duration.
+func durationOf(v Value, err error) (Value, error) {
+	if err != nil {
+		return nil, err
+	}
+	i, ok := v.(Int)
+	if !ok {
+		return nil, errors.New("duration overflows")
+	}
+	return Duration(i), nil
+}
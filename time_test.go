@@ -0,0 +1,142 @@
+package gendsl
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("Time and Duration", func() {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	It("can parse the literals", func() {
+		for expr, want := range map[string]Value{
+			`#inst "2024-05-01T10:00:00Z"`:          Time(t0),
+			`#inst   "2024-05-01"`:                  Time(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+			`(RETURN #inst "2024-05-01T10:00:00Z")`: Time(t0),
+			"1h30m":                                 Duration(90 * time.Minute),
+			"500ms":                                 Duration(500 * time.Millisecond),
+			"-1.5h":                                 Duration(-90 * time.Minute),
+			"30m":                                   Duration(30 * time.Minute),
+			"1m30.5s":                               Duration(90*time.Second + 500*time.Millisecond),
+			"2us":                                   Duration(2 * time.Microsecond),
+			"2µs":                                   Duration(2 * time.Microsecond),
+			"1_000ns":                               Duration(time.Microsecond),
+			"1.5m":                                  decimal("1.5"),
+			"(RETURN #:t 1h 1.5ms)":                 Duration(1500 * time.Microsecond),
+		} {
+			v, err := EvalExpr(expr, NewEnv().WithProcedure("RETURN", Procedure{Eval: CheckNArgs("1", _return)}))
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), expr)
+			gomega.Expect(v).Should(gomega.Equal(want), expr)
+		}
+
+		for _, expr := range []string{`#inst "yesterday"`, "#inst 1", "99999999h", "10ms1", "(RETURN 1h30mx)"} {
+			_, err := EvalExpr(expr, NewEnv())
+			gomega.Expect(err).Should(gomega.HaveOccurred(), expr)
+		}
+	})
+
+	It("prints the literals", func() {
+		for _, v := range []Value{
+			Time(t0), Time(t0.In(time.FixedZone("", 8*3600)).Add(time.Nanosecond)),
+			Duration(0), Duration(-90 * time.Minute), Duration(1500 * time.Nanosecond),
+		} {
+			text := Repr(v)
+			got, err := EvalExpr(text, NewEnv())
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), text)
+			if t, ok := v.(Time); ok {
+				gomega.Expect(time.Time(got.(Time)).Equal(time.Time(t))).Should(gomega.BeTrue(), text)
+				continue
+			}
+			gomega.Expect(got).Should(gomega.Equal(v), text)
+		}
+		gomega.Expect(Repr(Time(t0))).Should(gomega.Equal(`#inst "2024-05-01T10:00:00Z"`))
+		gomega.Expect(Repr(Duration(90 * time.Minute))).Should(gomega.Equal("1h30m0s"))
+	})
+
+	It("can be used in the env", func() {
+		env := NewEnv().WithTime("now", Time(t0)).WithDuration("ttl", Duration(time.Hour))
+		v, err := EvalExpr("now", env)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.Unwrap()).Should(gomega.Equal(t0))
+		v, err = EvalExpr("ttl", env)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(v.Unwrap()).Should(gomega.Equal(time.Hour))
+	})
+
+	It("does the arithmetic", func() {
+		for _, c := range []struct {
+			fn   func(a, b Value) (Value, error)
+			a, b Value
+			want Value
+		}{
+			{Add, Time(t0), Duration(time.Hour), Time(t0.Add(time.Hour))},
+			{Add, Duration(time.Hour), Time(t0), Time(t0.Add(time.Hour))},
+			{Sub, Time(t0), Duration(time.Hour), Time(t0.Add(-time.Hour))},
+			{Sub, Time(t0.Add(time.Hour)), Time(t0), Duration(time.Hour)},
+			{Add, Duration(time.Hour), Duration(time.Minute), Duration(time.Hour + time.Minute)},
+			{Sub, Duration(time.Minute), Duration(time.Hour), Duration(-59 * time.Minute)},
+			{Mul, Duration(time.Minute), Int(3), Duration(3 * time.Minute)},
+			{Mul, Uint(3), Duration(time.Minute), Duration(3 * time.Minute)},
+			{Div, Duration(time.Hour), Int(4), Duration(15 * time.Minute)},
+			{Div, Duration(time.Hour), Duration(25 * time.Minute), Int(2)},
+		} {
+			v, err := c.fn(c.a, c.b)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), Repr(c.a)+" "+Repr(c.b))
+			gomega.Expect(v).Should(gomega.Equal(c.want), Repr(c.a)+" "+Repr(c.b))
+		}
+
+		for _, c := range []struct {
+			fn   func(a, b Value) (Value, error)
+			a, b Value
+		}{
+			{Add, Time(t0), Time(t0)},
+			{Add, Time(t0), Int(1)},
+			{Mul, Duration(1), Duration(1)},
+			{Mul, Duration(1), Float(1.5)},
+			{Sub, Int(1), Duration(1)},
+			{Add, Duration(math.MaxInt64), Duration(1)},
+			{Mul, Duration(math.MaxInt64), Int(2)},
+			{Div, Duration(1), Int(0)},
+			{Sub, Time(time.Time{}), Time(t0.AddDate(1000, 0, 0))},
+		} {
+			_, err := c.fn(c.a, c.b)
+			gomega.Expect(err).Should(gomega.HaveOccurred(), Repr(c.a)+" "+Repr(c.b))
+		}
+	})
+
+	It("compares the values", func() {
+		for _, c := range []struct {
+			a, b Value
+			want int
+		}{
+			{Time(t0), Time(t0.Add(time.Second)), -1},
+			{Time(t0), Time(t0.In(time.FixedZone("", 3600))), 0},
+			{Duration(time.Hour), Duration(time.Minute), 1},
+			{Int(1), Float(1), 0},
+			{Int(1), decimal("1.0"), 0},
+			{Uint(math.MaxUint64), Int(-1), 1},
+			{bigInt("99999999999999999999"), Float(1e20), -1},
+			{decimal("0.1"), Float(0.1), -1},
+			{Float(math.Inf(1)), bigInt("99999999999999999999"), 1},
+			{Float(math.Inf(-1)), Float(math.Inf(-1)), 0},
+			{String("a"), String("b"), -1},
+		} {
+			got, err := Compare(c.a, c.b)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), Repr(c.a)+" "+Repr(c.b))
+			gomega.Expect(got).Should(gomega.Equal(c.want), Repr(c.a)+" "+Repr(c.b))
+		}
+
+		for _, c := range [][2]Value{
+			{Time(t0), Duration(1)},
+			{Int(1), String("1")},
+			{Float(math.NaN()), Int(1)},
+			{Bool(true), Bool(true)},
+		} {
+			_, err := Compare(c[0], c[1])
+			gomega.Expect(err).Should(gomega.HaveOccurred(), Repr(c[0])+" "+Repr(c[1]))
+		}
+	})
+})
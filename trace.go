@@ -0,0 +1,201 @@
+package gendsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+	// Tracer observes the evaluation of the expressions, identifiers and literals in a script.
+	// EnterNode and ExitNode are called in pairs and nested as the evaluation goes,
+	// set it by [gendsl.EvalCtx.WithTracer].
+	Tracer interface {
+		// EnterNode is called before a node is evaluated, Value, Duration and Err of `ev` are always empty.
+		EnterNode(ev TraceEvent)
+		// ExitNode is called after a node is evaluated.
+		ExitNode(ev TraceEvent)
+	}
+
+	// CallTracer is a Tracer that is also told about the procedure calls before they are made,
+	// which allows a host to pause the evaluation.
+	CallTracer interface {
+		Tracer
+		// CallProcedure is called after the options of an expression are evaluated and before its procedure is called,
+		// the call is aborted with the error returned.
+		CallProcedure(call TraceCall) error
+	}
+
+	// TraceEvent describes a node being evaluated.
+	TraceEvent struct {
+		Node     Node
+		EvalCtx  *EvalCtx // EvalCtx that the node is evaluated with
+		Kind     ExprType
+		Range    Range
+		Name     string        // procedure name for an expression, identifier for an identifier and raw text for a literal
+		Value    Value         // result of the node, nil if failed
+		Duration time.Duration // time spent on the evaluation
+		Err      error
+	}
+
+	// TraceCall describes a procedure call to be made.
+	TraceCall struct {
+		Node      Node
+		Name      string // name of the procedure
+		Procedure Procedure
+		EvalCtx   *EvalCtx
+		Args      []Expr
+		Options   map[string]Value
+	}
+
+	// TextTracer writes an indented text trace, one line for each identifier or literal,
+	// and a pair of lines for each expression.
+	TextTracer struct {
+		w     io.Writer
+		depth int
+	}
+
+	// JSONTracer writes a JSON object for every EnterNode and ExitNode in lines.
+	JSONTracer struct {
+		enc   *json.Encoder
+		depth int
+	}
+
+	jsonTraceEvent struct {
+		Event    string `json:"event"` // "enter" or "exit"
+		Depth    int    `json:"depth"`
+		Kind     string `json:"kind"`
+		Name     string `json:"name"`
+		Range    Range  `json:"range"`
+		Value    string `json:"value,omitempty"`
+		Duration int64  `json:"durationNs,omitempty"`
+		Err      string `json:"error,omitempty"`
+	}
+)
+
+// tracedRule reports whether a node of `rule` is reported to a Tracer.
+func tracedRule(rule pegRule) bool {
+	switch rule {
+	case ruleExpression, ruleIdentifier, ruleIdentifierAttr, ruleLiteral, ruleInterpolation:
+		return true
+	}
+	return false
+}
+
+// traceNode parses `node` and reports it to the tracer of `evalCtx`.
+func (c *ParseContext) traceNode(node *node32, evalCtx *EvalCtx, parse func() (any, error)) (any, error) {
+	n := Node{node: node, pc: c}
+	ev := TraceEvent{
+		Node:    n,
+		EvalCtx: evalCtx,
+		Kind:    n.Type(),
+		Range:   n.Range(),
+	}
+	switch ev.Kind {
+	case ExprTypeExpr:
+		ev.Name = n.Operator().Identifier()
+	case ExprTypeIdentifier:
+		ev.Name = n.Identifier()
+	default:
+		ev.Name = n.Text()
+	}
+
+	tracer := evalCtx.tracer
+	tracer.EnterNode(ev)
+	begin := time.Now()
+	v, err := parse()
+	ev.Duration = time.Since(begin)
+	ev.Value, _ = v.(Value)
+	ev.Err = err
+	tracer.ExitNode(ev)
+	return v, err
+}
+
+// traceCall tells the CallTracer of `evalCtx` about a procedure call if there is one.
+func (c *ParseContext) traceCall(node *node32, evalCtx *EvalCtx, op Procedure, args []Expr, options map[string]Value) error {
+	ct, ok := evalCtx.tracer.(CallTracer)
+	if !ok {
+		return nil
+	}
+	n := Node{node: node, pc: c}
+	return ct.CallProcedure(TraceCall{
+		Node:      n,
+		Name:      n.Operator().Identifier(),
+		Procedure: op,
+		EvalCtx:   evalCtx,
+		Args:      args,
+		Options:   options,
+	})
+}
+
+// NewTextTracer creates a TextTracer writing to `w`, errors on writing are ignored.
+func NewTextTracer(w io.Writer) *TextTracer {
+	return &TextTracer{w: w}
+}
+
+func (t *TextTracer) EnterNode(ev TraceEvent) {
+	if ev.Kind == ExprTypeExpr {
+		t.printf("-> %s %d:%d\n", ev.Node.Text(), ev.Range.Begin.Line, ev.Range.Begin.Symbol)
+	}
+	t.depth++
+}
+
+func (t *TextTracer) ExitNode(ev TraceEvent) {
+	t.depth--
+	prefix := ""
+	if ev.Kind == ExprTypeExpr {
+		prefix = "<- "
+	}
+	if ev.Err != nil {
+		t.printf("%s%s !! %s (%s)\n", prefix, ev.Name, ev.Err, ev.Duration)
+		return
+	}
+	t.printf("%s%s = %s (%s)\n", prefix, ev.Name, traceValue(ev.Value), ev.Duration)
+}
+
+func (t *TextTracer) printf(f string, args ...any) {
+	fmt.Fprintf(t.w, strings.Repeat("  ", t.depth)+f, args...)
+}
+
+// NewJSONTracer creates a JSONTracer writing to `w`, errors on writing are ignored.
+func NewJSONTracer(w io.Writer) *JSONTracer {
+	return &JSONTracer{enc: json.NewEncoder(w)}
+}
+
+func (t *JSONTracer) EnterNode(ev TraceEvent) {
+	_ = t.enc.Encode(jsonTraceEvent{
+		Event: "enter",
+		Depth: t.depth,
+		Kind:  ev.Kind.String(),
+		Name:  ev.Name,
+		Range: ev.Range,
+	})
+	t.depth++
+}
+
+func (t *JSONTracer) ExitNode(ev TraceEvent) {
+	t.depth--
+	out := jsonTraceEvent{
+		Event:    "exit",
+		Depth:    t.depth,
+		Kind:     ev.Kind.String(),
+		Name:     ev.Name,
+		Range:    ev.Range,
+		Duration: int64(ev.Duration),
+	}
+	if ev.Err != nil {
+		out.Err = ev.Err.Error()
+	} else {
+		out.Value = traceValue(ev.Value)
+	}
+	_ = t.enc.Encode(out)
+}
+
+func traceValue(v Value) string {
+	if v == nil {
+		return "nil"
+	}
+	return Repr(v)
+}
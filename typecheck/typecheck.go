@@ -0,0 +1,276 @@
+// Package typecheck infers the types of the values in a script and reports the misuses before evaluation.
+//
+// The types are inferred from the literals, the values in the env, the identifiers declared by [typecheck.Checker.Declare]
+// and the [gendsl.Signature]s of the procedures:
+//
+//	env := gendsl.NewEnv().WithProcedure("PLUS", gendsl.Procedure{
+//		Eval:      plus,
+//		Signature: &gendsl.Signature{Variadic: true, Rest: gendsl.ValueTypeInt | gendsl.ValueTypeFloat, Returns: gendsl.ValueTypeInt | gendsl.ValueTypeFloat},
+//	})
+//	diags, err := typecheck.New(env).Declare("$1", gendsl.ValueTypeString).Check(`(PLUS 1 $1)`)
+//	// 1:9: error: argument 2 of PLUS expects int|float, but got string
+//
+// The checking is gradual, a value whose type cannot be inferred, like the result of a procedure without a Signature,
+// is accepted anywhere, but a warning is reported if it is passed where a type is expected.
+package typecheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type (
+	// Severity tells how serious a diagnostic is.
+	Severity int
+
+	// Diagnostic is a type error or warning found in a script.
+	Diagnostic struct {
+		Severity Severity
+		Range    gendsl.Range
+		Message  string
+	}
+
+	// Checker checks the types in scripts evaluated with an env.
+	Checker struct {
+		env   *gendsl.Env
+		decls map[string]gendsl.ValueType
+	}
+
+	// pass holds the states of checking a script.
+	pass struct {
+		*Checker
+		diags []Diagnostic
+		types map[gendsl.Node]typed // the nodes walked to their types
+	}
+
+	// typed is a value that is inferred.
+	typed struct {
+		t   gendsl.ValueType // 0 if unknown
+		v   gendsl.Value     // nil if it is not known before evaluation
+		bad bool             // whether a problem of the value is reported already
+	}
+)
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Range.Begin.Line, d.Range.Begin.Symbol, d.Severity, d.Message)
+}
+
+// New creates a Checker for the scripts evaluated with `env`, nil is allowed.
+func New(env *gendsl.Env) *Checker {
+	if env == nil {
+		env = gendsl.NewEnv()
+	}
+	return &Checker{env: env, decls: make(map[string]gendsl.ValueType)}
+}
+
+// Declare declares the type of the identifier `id`, like the ones added to the EvalCtx during the evaluation.
+// It overrides the type of the value in the env with the same id.
+func (c *Checker) Declare(id string, t gendsl.ValueType) *Checker {
+	c.decls[id] = t
+	return c
+}
+
+// Check parses `script` and checks it, the diagnostics are sorted by their positions.
+// An [gendsl.SyntaxError] is returned if the script cannot be parsed.
+func (c *Checker) Check(script string) ([]Diagnostic, error) {
+	pc, err := gendsl.MakeParseContext(script)
+	if err != nil {
+		return nil, err
+	}
+	return c.CheckContext(pc), nil
+}
+
+// CheckContext checks a parsed script, the diagnostics are sorted by their positions.
+func (c *Checker) CheckContext(pc *gendsl.ParseContext) []Diagnostic {
+	p := &pass{Checker: c, diags: make([]Diagnostic, 0), types: make(map[gendsl.Node]typed)}
+	decls := make([]gendsl.Binding, 0, len(c.decls))
+	for id := range c.decls {
+		decls = append(decls, gendsl.Binding{Name: id})
+	}
+	w := &gendsl.Walker{Env: c.env, Leave: p.infer}
+	w.Walk(pc.Root(), gendsl.NewScope(decls...))
+	sort.SliceStable(p.diags, func(i, j int) bool {
+		return p.diags[i].Range.Begin.Offset < p.diags[j].Range.Begin.Offset
+	})
+	return p.diags
+}
+
+// HasErrors reports whether any of the diagnostics is an error.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pass) report(r gendsl.Range, severity Severity, f string, args ...any) {
+	p.diags = append(p.diags, Diagnostic{Severity: severity, Range: r, Message: fmt.Sprintf(f, args...)})
+}
+
+// infer checks the node `n` after its children are checked and records its type.
+func (p *pass) infer(n gendsl.Node, sc *gendsl.Scope) {
+	p.types[n] = p.typeOf(n, sc)
+}
+
+func (p *pass) typeOf(n gendsl.Node, sc *gendsl.Scope) typed {
+	switch n.Type() {
+	case gendsl.ExprTypeLiteral:
+		v, err := n.Literal()
+		if err != nil {
+			p.report(n.Range(), SeverityError, "%s", err.Error())
+			return typed{bad: true}
+		}
+		return typed{t: v.Type(), v: v}
+	case gendsl.ExprTypeIdentifier:
+		return p.identifier(n, sc)
+	case gendsl.ExprTypeInterpolation:
+		return typed{t: gendsl.ValueTypeString}
+	case gendsl.ExprTypeQuote:
+		if strings.HasPrefix(n.Text(), ",") {
+			return typed{}
+		}
+		return typed{t: gendsl.ValueTypeCode}
+	case gendsl.ExprTypeExpr:
+		return p.call(n)
+	}
+	return typed{}
+}
+
+// lookup returns the type of the identifier `id`, it reports false if `id` is not declared.
+func (p *pass) lookup(id string, sc *gendsl.Scope) (typed, bool) {
+	if b, ok := sc.Lookup(id); ok {
+		if b.Value != nil {
+			return typed{t: b.Value.Type(), v: b.Value}, true
+		}
+		if t, ok := p.decls[id]; ok && b.Def.IsZero() { // declared by Declare
+			return typed{t: t}, true
+		}
+		return typed{}, true
+	}
+	if v, ok := p.env.Lookup(id); ok {
+		return typed{t: v.Type(), v: v}, true
+	}
+	return typed{}, false
+}
+
+func (p *pass) identifier(n gendsl.Node, sc *gendsl.Scope) typed {
+	ret, ok := p.lookup(n.Identifier(), sc)
+	if !ok {
+		p.report(n.Range(), SeverityWarning, "%s is not declared", n.Identifier())
+		return typed{bad: true}
+	}
+	for _, attr := range n.Path() {
+		if ret.t == 0 {
+			return typed{}
+		}
+		if ret.t != gendsl.ValueTypeUserData {
+			p.report(n.Range(), SeverityError, "cannot select %s of %s", attr, ret.t)
+			return typed{bad: true}
+		}
+		if ret.v == nil {
+			return typed{}
+		}
+		selector, ok := ret.v.Unwrap().(gendsl.Selector)
+		if !ok {
+			p.report(n.Range(), SeverityError, "cannot select %s of %s", attr, ret.t)
+			return typed{bad: true}
+		}
+		v, ok := selector.Select(attr)
+		if !ok {
+			return typed{}
+		}
+		ret = typed{t: v.Type(), v: v}
+	}
+	return ret
+}
+
+// call checks a procedure call with the Signature of the procedure and returns the type of its result.
+func (p *pass) call(n gendsl.Node) typed {
+	op := n.Operator()
+	id := op.Identifier()
+	fn := p.types[op] // reported by identifier if it is not declared
+	var proc gendsl.Procedure
+	switch {
+	case fn.t != 0 && fn.t != gendsl.ValueTypeProcedure:
+		p.report(op.Range(), SeverityError, "%s is %s, not a procedure", id, fn.t)
+	case fn.v != nil:
+		proc, _ = fn.v.(gendsl.Procedure)
+	}
+	if gendsl.IsBuiltin(proc, "quote") || gendsl.IsBuiltin(proc, "quasiquote") {
+		return typed{t: gendsl.ValueTypeCode}
+	}
+	sig := proc.Signature
+
+	for _, opt := range n.Options() {
+		got := p.types[opt.Value]
+		if sig == nil || sig.Options == nil {
+			continue
+		}
+		want, ok := sig.Options[opt.Name]
+		if !ok {
+			p.report(opt.Range, SeverityError, "%s accepts no option #:%s", id, opt.Name)
+			continue
+		}
+		p.expect(opt.Value, fmt.Sprintf("option #:%s of %s", opt.Name, id), want, got)
+	}
+
+	args := n.Args()
+	for i, arg := range args {
+		got, walked := p.types[arg]
+		if !walked { // an argument that defines an identifier
+			continue
+		}
+		if sig == nil {
+			continue
+		}
+		switch {
+		case i < len(sig.Params):
+			p.expect(arg, fmt.Sprintf("argument %d of %s", i+1, id), sig.Params[i], got)
+		case sig.Variadic:
+			p.expect(arg, fmt.Sprintf("argument %d of %s", i+1, id), sig.Rest, got)
+		}
+	}
+	if sig == nil {
+		return typed{}
+	}
+	switch {
+	case len(args) < len(sig.Params) && sig.Variadic:
+		p.report(n.Range(), SeverityError, "%s expects at least %d argument(s), but got %d", id, len(sig.Params), len(args))
+	case len(args) != len(sig.Params) && !sig.Variadic:
+		p.report(n.Range(), SeverityError, "%s expects %d argument(s), but got %d", id, len(sig.Params), len(args))
+	}
+	return typed{t: sig.Returns}
+}
+
+// expect checks whether a value of type `got` can be used as `what` which expects the type `want`.
+func (p *pass) expect(n gendsl.Node, what string, want gendsl.ValueType, got typed) {
+	switch {
+	case want == 0, got.bad:
+	case got.t == 0:
+		p.report(n.Range(), SeverityWarning, "cannot infer the type of %s, %s expects %s", n.Text(), what, want)
+	case got.t&want == 0:
+		p.report(n.Range(), SeverityError, "%s expects %s, but got %s", what, want, got.t)
+	case got.t&^want != 0:
+		p.report(n.Range(), SeverityWarning, "%s expects %s, but may get %s", what, want, got.t)
+	}
+}
@@ -0,0 +1,109 @@
+package typecheck
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ccbhj/gendsl"
+)
+
+type record map[string]gendsl.Value
+
+func (r record) Select(idx string) (gendsl.Value, bool) {
+	v, ok := r[idx]
+	return v, ok
+}
+
+var _ = Describe("Checker", func() {
+	const number = gendsl.ValueTypeInt | gendsl.ValueTypeFloat
+	var (
+		nop = func(_ *gendsl.EvalCtx, _ []gendsl.Expr, _ map[string]gendsl.Value) (gendsl.Value, error) {
+			return gendsl.Nil{}, nil
+		}
+		env *gendsl.Env
+	)
+
+	BeforeEach(func() {
+		env = gendsl.NewEnv().WithBuiltins().
+			WithProcedure("PLUS", gendsl.Procedure{Eval: nop, Signature: &gendsl.Signature{
+				Variadic: true, Rest: number, Returns: number,
+			}}).
+			WithProcedure("CONCAT", gendsl.Procedure{Eval: nop, Signature: &gendsl.Signature{
+				Params: []gendsl.ValueType{gendsl.ValueTypeString, gendsl.ValueTypeString}, Returns: gendsl.ValueTypeString,
+			}}).
+			WithProcedure("ROUND", gendsl.Procedure{Eval: nop, Signature: &gendsl.Signature{
+				Params:  []gendsl.ValueType{gendsl.ValueTypeFloat},
+				Options: map[string]gendsl.ValueType{"digits": gendsl.ValueTypeInt},
+				Returns: gendsl.ValueTypeFloat,
+			}}).
+			WithProcedure("LET", gendsl.Procedure{Eval: nop, Binder: gendsl.BindArg(0, 2)}).
+			WithProcedure("ANY", gendsl.Procedure{Eval: nop}).
+			WithUserData("user", &gendsl.UserData{V: record{"name": gendsl.String("bob"), "age": gendsl.Int(3)}}).
+			WithInt("x", 1)
+	})
+
+	check := func(script string) []string {
+		diags, err := New(env).Declare("$1", gendsl.ValueTypeString).Check(script)
+		Expect(err).ShouldNot(HaveOccurred())
+		ret := make([]string, 0, len(diags))
+		for _, d := range diags {
+			ret = append(ret, d.String())
+		}
+		return ret
+	}
+
+	It("accepts the well typed scripts", func() {
+		for _, script := range []string{
+			`(PLUS 1 2.5 x (PLUS user.age))`,
+			`(CONCAT $1 (CONCAT user.name #f"${x}"))`,
+			`(ROUND #:digits x 1.5)`,
+			`(ANY (eval '(PLUS 1)) (quote (PLUS $1)))`,
+			"(ANY `(PLUS ,$1 ,(PLUS x)))",
+		} {
+			Expect(check(script)).Should(BeEmpty(), script)
+		}
+	})
+
+	It("reports the type errors", func() {
+		Expect(check(`(PLUS 1 $1)`)).Should(Equal([]string{"1:9: error: argument 2 of PLUS expects int|float, but got string"}))
+		Expect(check(`(CONCAT user.name (PLUS 1 1))`)).Should(Equal([]string{
+			"1:19: error: argument 2 of CONCAT expects string, but got int|float",
+		}))
+		Expect(check(`(ROUND #:digits "2" 1.0)`)).Should(Equal([]string{
+			"1:17: error: option #:digits of ROUND expects int, but got string",
+		}))
+		Expect(check(`(ROUND #:precision 2 1.0)`)).Should(Equal([]string{"1:8: error: ROUND accepts no option #:precision"}))
+		Expect(check(`(CONCAT "a")`)).Should(Equal([]string{"1:1: error: CONCAT expects 2 argument(s), but got 1"}))
+		Expect(check(`(x 1)`)).Should(Equal([]string{"1:2: error: x is int, not a procedure"}))
+		Expect(check(`(PLUS x.y)`)).Should(Equal([]string{"1:7: error: cannot select y of int"}))
+		Expect(check(`(PLUS #x"abc")`)).Should(HaveLen(1))
+		Expect(check("(CONCAT $1 `(PLUS ,(PLUS $1)))")).Should(Equal([]string{
+			"1:12: error: argument 2 of CONCAT expects string, but got code",
+			"1:26: error: argument 1 of PLUS expects int|float, but got string",
+		}))
+	})
+
+	It("reports the types that cannot be inferred", func() {
+		Expect(check(`(PLUS (ANY) y)`)).Should(Equal([]string{
+			"1:7: warning: cannot infer the type of (ANY), argument 1 of PLUS expects int|float",
+			"1:13: warning: y is not declared",
+		}))
+		Expect(check(`(LET n 1 (PLUS n))`)).Should(Equal([]string{
+			"1:16: warning: cannot infer the type of n, argument 1 of PLUS expects int|float",
+		}))
+		Expect(check(`(ROUND (PLUS 1 2))`)).Should(Equal([]string{
+			"1:8: warning: argument 1 of ROUND expects float, but may get int|float",
+		}))
+		Expect(check(`(ANY (UNKNOWN 1) user.missing)`)).Should(Equal([]string{"1:7: warning: UNKNOWN is not declared"}))
+	})
+
+	It("uses the declarations over the env", func() {
+		diags, err := New(env).Declare("x", gendsl.ValueTypeString).Check(`(PLUS x)`)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(HasErrors(diags)).Should(BeTrue())
+		Expect(diags[0].Range.Begin.Symbol).Should(Equal(7))
+
+		_, err = New(nil).Check(`(PLUS`)
+		Expect(err).Should(BeAssignableToTypeOf(&gendsl.SyntaxError{}))
+	})
+})
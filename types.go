@@ -1,5 +1,7 @@
 package gendsl
 
+import "strings"
+
 // Selector can be used for the '.' syntax to get fields b
 type Selector interface {
 	// Select queries attributes by `idx`,
@@ -19,9 +21,28 @@ const (
 	ValueTypeProcedure             // Procedure
 	ValueTypeUserData              // UserData
 	ValueTypeNil                   // Nil
+	ValueTypeCode                  // Code
+	ValueTypeSymbol                // Symbol
+	ValueTypeKeyword               // Keyword
+	ValueTypeBigInt                // BigInt
+	ValueTypeDecimal               // Decimal
+	ValueTypeTime                  // Time
+	ValueTypeDuration              // Duration
+	ValueTypeBytes                 // Bytes
+	ValueTypeRegexp                // Regexp
 )
 
+// String returns the name of the type, the names of a union like ValueTypeInt|ValueTypeFloat are joined by '|'.
 func (v ValueType) String() string {
+	if v&(v-1) != 0 {
+		names := make([]string, 0)
+		for t := ValueType(1); t <= v && t != 0; t <<= 1 {
+			if v&t != 0 {
+				names = append(names, t.String())
+			}
+		}
+		return strings.Join(names, "|")
+	}
 	switch v {
 	case ValueTypeInt:
 		return "int"
@@ -39,6 +60,24 @@ func (v ValueType) String() string {
 		return "userdata"
 	case ValueTypeNil:
 		return "nil"
+	case ValueTypeCode:
+		return "code"
+	case ValueTypeSymbol:
+		return "symbol"
+	case ValueTypeKeyword:
+		return "keyword"
+	case ValueTypeBigInt:
+		return "bigint"
+	case ValueTypeDecimal:
+		return "decimal"
+	case ValueTypeTime:
+		return "time"
+	case ValueTypeDuration:
+		return "duration"
+	case ValueTypeBytes:
+		return "bytes"
+	case ValueTypeRegexp:
+		return "regexp"
 	}
 	return "unknown"
 }
@@ -54,6 +93,15 @@ func (v ValueType) String() string {
 //   - Nil        -> nil
 //   - Procedure  -> EvalFn
 //   - UserData   -> any
+//   - Code       -> Node
+//   - Symbol     -> string
+//   - Keyword    -> string
+//   - BigInt     -> *big.Int
+//   - Decimal    -> *big.Rat
+//   - Time       -> time.Time
+//   - Duration   -> time.Duration
+//   - Bytes      -> []byte
+//   - Regexp     -> *regexp.Regexp
 type Value interface {
 	// Type return the ValueType of a Value.
 	Type() ValueType
@@ -126,6 +174,32 @@ func (u *UserData) Unwrap() any   { return u.V }
 // Procedure define how an expression in the format of (X Y Z...) got evaluated.
 type Procedure struct {
 	Eval ProcedureFn
+	// Doc describes the procedure, it is shown to script authors by tools like the language server.
+	Doc string
+	// Options maps the options that the procedure accepts to their descriptions, it is only used by tools.
+	Options map[string]string
+	// Binder reports the identifiers that the procedure defines for its arguments, it is only used by tools.
+	Binder Binder
+	// Deprecated tells why the procedure should not be used anymore and what to use instead, empty if it is not deprecated.
+	Deprecated string
+	// Signature declares the types of the arguments, options and result, it is only used by tools, nil if unknown.
+	Signature *Signature
+
+	builtin string // the id of the procedure if it is built in the DSL, see [gendsl.Builtin]
+}
+
+// Signature declares the types that a procedure accepts and returns for static type checking.
+// A ValueType of 0 stands for any type, and the types can be combined like ValueTypeInt|ValueTypeFloat to accept either of them.
+type Signature struct {
+	// Params are the types of the arguments in order.
+	Params []ValueType
+	// Variadic reports whether more arguments than Params are accepted, they are all of the type Rest.
+	Variadic bool
+	Rest     ValueType
+	// Options maps the options accepted to their types, any option is accepted if it is nil.
+	Options map[string]ValueType
+	// Returns is the type of the result, 0 if it is unknown.
+	Returns ValueType
 }
 
 var _ Value = Procedure{}